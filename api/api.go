@@ -1,30 +1,79 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"log"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
 	"github.com/khanhvtn/netevent-go/graph"
 	"github.com/khanhvtn/netevent-go/graph/generated"
+	"github.com/khanhvtn/netevent-go/helpers"
 	"github.com/khanhvtn/netevent-go/middlewares"
 	"github.com/khanhvtn/netevent-go/routes"
 	"github.com/khanhvtn/netevent-go/services"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
+// Attach a machine readable code to the errors raised by the services
+func errorPresenter(ctx context.Context, e error) *gqlerror.Error {
+	err := graphql.DefaultErrorPresenter(ctx, e)
+	var errConflict *helpers.ErrConflict
+	var errNotFound *helpers.ErrNotFound
+	var errValidation *helpers.ErrValidation
+	code := ""
+	switch {
+	case errors.As(e, &errConflict):
+		code = "CONFLICT"
+	case errors.As(e, &errNotFound):
+		code = "NOT_FOUND"
+	case errors.As(e, &errValidation):
+		code = "BAD_USER_INPUT"
+	}
+	if code != "" {
+		if err.Extensions == nil {
+			err.Extensions = make(map[string]interface{})
+		}
+		err.Extensions["code"] = code
+	}
+	return err
+}
+
 // Defining the Graphql handler
 func graphqlHandler(di *services.DI) gin.HandlerFunc {
 	// NewExecutableSchema and Config are in the generated.go file
 	// Resolver is in the resolver.go file
 	h := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{Resolvers: graph.Init(di)}))
+	h.SetErrorPresenter(errorPresenter)
+	h.AroundOperations(invalidateDashboard(di))
 
 	return func(c *gin.Context) {
 		h.ServeHTTP(c.Writer, c.Request)
 	}
 }
 
+// Outdate the cached dashboards once a mutation has run
+func invalidateDashboard(di *services.DI) graphql.OperationMiddleware {
+	return func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		handler := next(ctx)
+		if graphql.GetOperationContext(ctx).Operation.Operation != ast.Mutation {
+			return handler
+		}
+		return func(ctx context.Context) *graphql.Response {
+			response := handler(ctx)
+			dashboardService := di.Container.Get(services.DashboardServiceName).(*services.DashboardService)
+			if err := dashboardService.Invalidate(); err != nil {
+				log.Printf("dashboard: cannot invalidate the cache: %s", err.Error())
+			}
+			return response
+		}
+	}
+}
+
 // Defining the Playground handler
 func playgroundHandler() gin.HandlerFunc {
 	h := playground.Handler("GraphQL", "/query")
@@ -39,11 +88,6 @@ func Init(di *services.DI) {
 	// Setting up Gin
 	app := gin.New()
 
-	//load env file
-	if err := godotenv.Load(); err != nil {
-		log.Fatalln("Error loading .env file")
-	}
-
 	//Middlewares
 	app.Use(middlewares.CheckDB())
 	app.Use(middlewares.ContextToContextMiddleware())
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khanhvtn/netevent-go/models"
+	"github.com/khanhvtn/netevent-go/services"
+	"github.com/khanhvtn/netevent-go/utilities"
+	"github.com/sarulabs/di"
+)
+
+/* EventCalendar: download a published event as an .ics file */
+func EventCalendar(c *gin.Context) {
+	container := c.MustGet("container").(di.Container)
+	calendarService := container.Get(services.CalendarServiceName).(*services.CalendarService)
+
+	objectId, err := utilities.ConvertStringIdToObjectID(strings.TrimSuffix(c.Param("id"), ".ics"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	calendar, err := calendarService.EventCalendar(*objectId)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename=event.ics")
+	c.Data(http.StatusOK, models.CalendarContentType, calendar.ToBytes())
+}
+
+/* PublishedCalendar: the feed of the published events, for calendar subscriptions */
+func PublishedCalendar(c *gin.Context) {
+	container := c.MustGet("container").(di.Container)
+	calendarService := container.Get(services.CalendarServiceName).(*services.CalendarService)
+
+	calendar, err := calendarService.PublishedCalendar()
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, models.CalendarContentType, calendar.ToBytes())
+}
+
+/* TaskCalendar: the feed of the tasks of a user, the token in the url stands for the login */
+func TaskCalendar(c *gin.Context) {
+	container := c.MustGet("container").(di.Container)
+	calendarService := container.Get(services.CalendarServiceName).(*services.CalendarService)
+
+	calendar, err := calendarService.TaskCalendar(strings.TrimSuffix(c.Param("token"), ".ics"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	//the url is a credential, keep the feed out of shared caches
+	c.Header("Cache-Control", "private, no-store")
+	c.Data(http.StatusOK, models.CalendarContentType, calendar.ToBytes())
+}
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/services"
+	"github.com/khanhvtn/netevent-go/utilities"
+	"github.com/sarulabs/di"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/* Export: download a dataset as csv or xlsx, narrowed with ?eventId= and ?columns=a,b */
+func Export(c *gin.Context) {
+	container := c.MustGet("container").(di.Container)
+	userService := container.Get(services.UserServiceName).(*services.UserService)
+
+	//exports hold the personal data of participants, only logged in users can download them
+	encryptedCookie, err := c.Cookie("netevent")
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "access denied"})
+		return
+	}
+	if _, err := userService.GetBySession(encryptedCookie); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "access denied"})
+		return
+	}
+
+	columns := make([]string, 0)
+	for _, column := range strings.Split(c.Query("columns"), ",") {
+		if column = strings.TrimSpace(column); column != "" {
+			columns = append(columns, column)
+		}
+	}
+	writeExport(c, c.Param("dataset"), c.Query("eventId"), c.DefaultQuery("format", services.ExportFormatCsv), columns)
+}
+
+/* writeExport: check the export, then stream it to the client */
+func writeExport(c *gin.Context, dataset string, eventId string, format string, columns []string) {
+	container := c.MustGet("container").(di.Container)
+	exportService := container.Get(services.ExportServiceName).(*services.ExportService)
+
+	var objectId *primitive.ObjectID
+	if eventId != "" {
+		id, err := utilities.ConvertStringIdToObjectID(eventId)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		objectId = id
+	}
+	if format != services.ExportFormatCsv && format != services.ExportFormatXlsx {
+		c.JSON(http.StatusBadRequest, gin.H{"error": helpers.NewErrValidation(fmt.Sprintf("unknown format %s", format)).Error()})
+		return
+	}
+	export, err := exportService.Prepare(dataset, objectId, columns)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	//nothing can be reported to the client once the rows are being written
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.%s", dataset, format))
+	var writer utilities.RowWriter
+	if format == services.ExportFormatXlsx {
+		c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		writer, err = utilities.NewXlsxRowWriter(c.Writer, dataset)
+	} else {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		writer = utilities.NewCsvRowWriter(c.Writer)
+	}
+	if err == nil {
+		err = export.Write(writer)
+	}
+	if err != nil {
+		log.Printf("export: %s export stopped: %s", dataset, err.Error())
+		c.Abort()
+	}
+}
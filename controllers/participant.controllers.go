@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"github.com/khanhvtn/netevent-go/services"
+	"github.com/sarulabs/di"
+)
+
+/* errorStatus: the http status matching an error raised by the services */
+func errorStatus(err error) int {
+	switch err.(type) {
+	case *helpers.ErrForbidden:
+		return http.StatusForbidden
+	case *helpers.ErrConflict:
+		return http.StatusConflict
+	case *helpers.ErrNotFound:
+		return http.StatusNotFound
+	case *helpers.ErrValidation:
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+/* CheckIn: staff scan of the QR code sent to a participant */
+func CheckIn(c *gin.Context) {
+	container := c.MustGet("container").(di.Container)
+	userService := container.Get(services.UserServiceName).(*services.UserService)
+	participantService := container.Get(services.ParticipantServiceName).(*services.ParticipantService)
+
+	//only logged in staff can check participants in
+	encryptedCookie, err := c.Cookie("netevent")
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "access denied"})
+		return
+	}
+	staff, err := userService.GetBySession(encryptedCookie)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "access denied"})
+		return
+	}
+	if err := userService.RequireRole(staff, models.UserRoleStaff); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	var body struct {
+		QrPayload string `json:"qrPayload" binding:"required"`
+		Device    string `json:"device"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "qrPayload must not be blanked"})
+		return
+	}
+	if body.Device == "" {
+		body.Device = c.Request.UserAgent()
+	}
+	participant, err := participantService.CheckIn(body.QrPayload, staff.ID, body.Device)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	//the attendance on the dashboard changed
+	dashboardService := container.Get(services.DashboardServiceName).(*services.DashboardService)
+	if err := dashboardService.Invalidate(); err != nil {
+		log.Printf("dashboard: cannot invalidate the cache: %s", err.Error())
+	}
+	c.JSON(http.StatusOK, participant)
+}
@@ -0,0 +1,34 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var warnStandaloneOnce sync.Once
+
+/* WithTransaction: run the writes of fn in one transaction, they are applied one by one on a standalone server */
+func (m *MongoInstance) WithTransaction(ctx context.Context, fn func(sessCtx mongo.SessionContext) error) error {
+	session, err := m.Client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	//transactions need a replica set, a standalone server answers with IllegalOperation
+	var commandErr mongo.CommandError
+	if errors.As(err, &commandErr) && commandErr.Code == 20 {
+		warnStandaloneOnce.Do(func() {
+			log.Println("MongoDB does not support transactions, writes are not atomic")
+		})
+		return mongo.WithSession(ctx, session, fn)
+	}
+	return err
+}
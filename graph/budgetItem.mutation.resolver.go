@@ -0,0 +1,71 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/models"
+	"github.com/khanhvtn/netevent-go/services"
+	"github.com/khanhvtn/netevent-go/utilities"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (r *mutationResolver) CreateBudgetItem(ctx context.Context, eventID string, input model.NewBudgetItem) (*model.BudgetItem, error) {
+	service := r.di.Container.Get(services.BudgetItemServiceName).(*services.BudgetItemService)
+	//check input
+	if err := service.ValidateNewBudgetItem(input); err != nil {
+		return nil, err
+	}
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(eventID)
+	if err != nil {
+		return nil, err
+	}
+	newBudgetItem, err := service.Create(*objectId, input)
+	if err != nil {
+		return nil, err
+	}
+	report, err := service.ReportItem(newBudgetItem)
+	if err != nil {
+		return nil, err
+	}
+	return r.mapBudgetItem(report), nil
+}
+func (r *mutationResolver) UpdateBudgetItem(ctx context.Context, id string, input model.UpdateBudgetItem) (*model.BudgetItem, error) {
+	service := r.di.Container.Get(services.BudgetItemServiceName).(*services.BudgetItemService)
+	//check input
+	if err := service.ValidateUpdateBudgetItem(input); err != nil {
+		return nil, err
+	}
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	updatedBudgetItem, err := service.UpdateOne(*objectId, input)
+	if err != nil {
+		return nil, err
+	}
+	report, err := service.ReportItem(updatedBudgetItem)
+	if err != nil {
+		return nil, err
+	}
+	return r.mapBudgetItem(report), nil
+}
+func (r *mutationResolver) DeleteBudgetItem(ctx context.Context, id string) (*model.BudgetItem, error) {
+	service := r.di.Container.Get(services.BudgetItemServiceName).(*services.BudgetItemService)
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	//an item can only be deleted without expenses, there is nothing to count
+	deletedBudgetItem, err := service.DeleteOne(bson.M{"_id": objectId})
+	if err != nil {
+		return nil, err
+	}
+	return r.mapBudgetItem(&models.BudgetItemReport{
+		Item:     deletedBudgetItem,
+		Variance: models.BudgetVariance{Currency: deletedBudgetItem.Currency, Planned: deletedBudgetItem.Planned},
+	}), nil
+}
@@ -0,0 +1,45 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/services"
+	"github.com/khanhvtn/netevent-go/utilities"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (r *budgetItemResolver) Expenses(ctx context.Context, obj *model.BudgetItem) ([]*model.Expense, error) {
+	service := r.di.Container.Get(services.ExpenseServiceName).(*services.ExpenseService)
+	expenses, err := service.GetAll(bson.M{"budgetItem": obj.ID})
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*model.Expense, 0)
+	for _, expense := range expenses {
+		mappedExpense, err := r.mapExpense(expense)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, mappedExpense)
+	}
+	return results, nil
+}
+
+func (r *queryResolver) BudgetItem(ctx context.Context, id string) (*model.BudgetItem, error) {
+	service := r.di.Container.Get(services.BudgetItemServiceName).(*services.BudgetItemService)
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	budgetItem, err := service.GetOne(bson.M{"_id": objectId})
+	if err != nil {
+		return nil, err
+	}
+	report, err := service.ReportItem(budgetItem)
+	if err != nil {
+		return nil, err
+	}
+	return r.mapBudgetItem(report), nil
+}
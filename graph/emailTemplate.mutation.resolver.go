@@ -0,0 +1,68 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/models"
+	"github.com/khanhvtn/netevent-go/services"
+	"github.com/khanhvtn/netevent-go/utilities"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (r *mutationResolver) CreateEmailTemplate(ctx context.Context, input model.NewEmailTemplate) (*model.EmailTemplate, error) {
+	//only admins override the mail templates
+	if _, err := r.currentUserWithRole(ctx, models.UserRoleAdmin); err != nil {
+		return nil, err
+	}
+	service := r.di.Container.Get(services.EmailTemplateServiceName).(*services.EmailTemplateService)
+	//validate input
+	if err := service.ValidateNewEmailTemplate(input); err != nil {
+		return nil, err
+	}
+	newEmailTemplate, err := service.Create(input)
+	if err != nil {
+		return nil, err
+	}
+	return r.mapEmailTemplate(newEmailTemplate), nil
+}
+
+func (r *mutationResolver) UpdateEmailTemplate(ctx context.Context, id string, input model.UpdateEmailTemplate) (*model.EmailTemplate, error) {
+	//only admins override the mail templates
+	if _, err := r.currentUserWithRole(ctx, models.UserRoleAdmin); err != nil {
+		return nil, err
+	}
+	service := r.di.Container.Get(services.EmailTemplateServiceName).(*services.EmailTemplateService)
+	//validate input
+	if err := service.ValidateUpdateEmailTemplate(id, input); err != nil {
+		return nil, err
+	}
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	updatedEmailTemplate, err := service.UpdateOne(bson.M{"_id": objectId}, input)
+	if err != nil {
+		return nil, err
+	}
+	return r.mapEmailTemplate(updatedEmailTemplate), nil
+}
+
+func (r *mutationResolver) DeleteEmailTemplate(ctx context.Context, id string) (*model.EmailTemplate, error) {
+	//only admins override the mail templates
+	if _, err := r.currentUserWithRole(ctx, models.UserRoleAdmin); err != nil {
+		return nil, err
+	}
+	service := r.di.Container.Get(services.EmailTemplateServiceName).(*services.EmailTemplateService)
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	deletedEmailTemplate, err := service.DeleteOne(bson.M{"_id": objectId})
+	if err != nil {
+		return nil, err
+	}
+	return r.mapEmailTemplate(deletedEmailTemplate), nil
+}
@@ -0,0 +1,38 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/services"
+	"github.com/khanhvtn/netevent-go/utilities"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (r *queryResolver) EmailTemplates(ctx context.Context) ([]*model.EmailTemplate, error) {
+	service := r.di.Container.Get(services.EmailTemplateServiceName).(*services.EmailTemplateService)
+	emailTemplates, err := service.GetAll(bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*model.EmailTemplate, 0)
+	for _, emailTemplate := range emailTemplates {
+		results = append(results, r.mapEmailTemplate(emailTemplate))
+	}
+	return results, nil
+}
+
+func (r *queryResolver) EmailTemplate(ctx context.Context, id string) (*model.EmailTemplate, error) {
+	service := r.di.Container.Get(services.EmailTemplateServiceName).(*services.EmailTemplateService)
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	//get email template based specific id
+	emailTemplate, err := service.GetOne(bson.M{"_id": objectId})
+	if err != nil {
+		return nil, err
+	}
+	return r.mapEmailTemplate(emailTemplate), nil
+}
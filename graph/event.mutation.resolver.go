@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/models"
 	"github.com/khanhvtn/netevent-go/services"
 	"github.com/khanhvtn/netevent-go/utilities"
 	"go.mongodb.org/mongo-driver/bson"
@@ -11,22 +12,38 @@ import (
 
 func (r *mutationResolver) CreateEvent(ctx context.Context, input model.NewEvent) (*model.Event, error) {
 	service := r.di.Container.Get(services.EventServiceName).(*services.EventService)
+	seriesService := r.di.Container.Get(services.EventSeriesServiceName).(*services.EventSeriesService)
 	//check input
 	if err := service.ValidateNewEvent(input); err != nil {
 		return nil, err
 	}
+	if err := seriesService.ValidateRecurrence(input.StartDate, input.Recurrence); err != nil {
+		return nil, err
+	}
 	newEvent, err := service.Create(input)
 	if err != nil {
 		return nil, err
 	}
+	//the new event is the first occurrence of its series, it is not kept without the rest of the series
+	if input.Recurrence != nil {
+		repeatedEvent, err := seriesService.Repeat(newEvent, *input.Recurrence)
+		if err != nil {
+			if _, deleteErr := service.DeleteOne(bson.M{"_id": newEvent.ID}); deleteErr != nil {
+				return nil, deleteErr
+			}
+			return nil, err
+		}
+		newEvent = repeatedEvent
+	}
 	results, err := r.mapEvent(newEvent)
 	if err != nil {
 		return nil, err
 	}
 	return results, nil
 }
-func (r *mutationResolver) UpdateEvent(ctx context.Context, id string, input model.UpdateEvent) (*model.Event, error) {
+func (r *mutationResolver) UpdateEvent(ctx context.Context, id string, input model.UpdateEvent, scope *model.RecurrenceScope) (*model.Event, error) {
 	service := r.di.Container.Get(services.EventServiceName).(*services.EventService)
+	seriesService := r.di.Container.Get(services.EventSeriesServiceName).(*services.EventSeriesService)
 	//check input
 	if err := service.ValidateUpdateEvent(id, input); err != nil {
 		return nil, err
@@ -36,7 +53,7 @@ func (r *mutationResolver) UpdateEvent(ctx context.Context, id string, input mod
 	if err != nil {
 		return nil, err
 	}
-	updatedEvent, err := service.UpdateOne(bson.M{"_id": objectId}, input)
+	updatedEvent, err := seriesService.UpdateOne(*objectId, input, recurrenceScope(scope))
 	if err != nil {
 		return nil, err
 	}
@@ -46,14 +63,14 @@ func (r *mutationResolver) UpdateEvent(ctx context.Context, id string, input mod
 	}
 	return results, nil
 }
-func (r *mutationResolver) DeleteEvent(ctx context.Context, id string) (*model.Event, error) {
-	service := r.di.Container.Get(services.EventServiceName).(*services.EventService)
+func (r *mutationResolver) DeleteEvent(ctx context.Context, id string, scope *model.RecurrenceScope) (*model.Event, error) {
+	service := r.di.Container.Get(services.EventSeriesServiceName).(*services.EventSeriesService)
 	//convert string id to object id
 	objectId, err := utilities.ConvertStringIdToObjectID(id)
 	if err != nil {
 		return nil, err
 	}
-	deletedEvent, err := service.DeleteOne(bson.M{"_id": objectId})
+	deletedEvent, err := service.DeleteOne(*objectId, recurrenceScope(scope))
 	if err != nil {
 		return nil, err
 	}
@@ -63,3 +80,29 @@ func (r *mutationResolver) DeleteEvent(ctx context.Context, id string) (*model.E
 	}
 	return results, nil
 }
+
+func (r *mutationResolver) ReorderCustomizeFields(ctx context.Context, id string, names []string) (*model.Event, error) {
+	service := r.di.Container.Get(services.EventServiceName).(*services.EventService)
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	updatedEvent, err := service.ReorderCustomizeFields(bson.M{"_id": objectId}, names)
+	if err != nil {
+		return nil, err
+	}
+	results, err := r.mapEvent(updatedEvent)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+/* recurrenceScope: only the given occurrence is changed unless told otherwise */
+func recurrenceScope(scope *model.RecurrenceScope) string {
+	if scope == nil {
+		return models.RecurrenceScopeThis
+	}
+	return scope.String()
+}
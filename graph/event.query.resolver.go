@@ -2,9 +2,9 @@ package graph
 
 import (
 	"context"
-	"os"
+	"sort"
+	"time"
 
-	"github.com/gin-gonic/gin"
 	"github.com/khanhvtn/netevent-go/graph/model"
 	"github.com/khanhvtn/netevent-go/services"
 	"github.com/khanhvtn/netevent-go/utilities"
@@ -136,26 +136,61 @@ func (r *queryResolver) Event(ctx context.Context, id string) (*model.Event, err
 	}
 	return result, nil
 }
-func (r *queryResolver) EventStatistic(ctx context.Context) (*model.EventStatisticResponse, error) {
-	// service := r.di.Container.Get(services.EventServiceName).(*services.EventService)
-
-	//get gin context
-	ginContext := ctx.Value("gincontext").(*gin.Context)
-	ginContext.Header("Content-Disposition", "attachment; filename=event.csv")
-	ginContext.Header("Content-Type", ginContext.ContentType())
-	file, err := os.CreateTemp("", "random")
+func (r *queryResolver) EventStatistic(ctx context.Context, eventID string) (*model.EventStatistic, error) {
+	service := r.di.Container.Get(services.StatisticServiceName).(*services.StatisticService)
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(eventID)
 	if err != nil {
 		return nil, err
 	}
-
-	defer file.Close()
-	if _, err := file.WriteString("abc"); err != nil {
+	statistic, err := service.GetEventStatistic(*objectId)
+	if err != nil {
 		return nil, err
 	}
-
+	return r.mapEventStatistic(statistic)
+}
+func (r *queryResolver) Dashboard(ctx context.Context, from time.Time, to time.Time) (*model.Dashboard, error) {
+	service := r.di.Container.Get(services.DashboardServiceName).(*services.DashboardService)
+	dashboard, err := service.GetDashboard(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return r.mapDashboard(dashboard)
+}
+func (r *eventResolver) BudgetItems(ctx context.Context, obj *model.Event) ([]*model.BudgetItem, error) {
+	service := r.di.Container.Get(services.BudgetItemServiceName).(*services.BudgetItemService)
+	report, err := service.Report(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*model.BudgetItem, 0)
+	for _, item := range report.Items {
+		results = append(results, r.mapBudgetItem(item))
+	}
+	return results, nil
+}
+func (r *eventResolver) BudgetTotals(ctx context.Context, obj *model.Event) ([]*model.BudgetTotal, error) {
+	service := r.di.Container.Get(services.BudgetItemServiceName).(*services.BudgetItemService)
+	report, err := service.Report(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*model.BudgetTotal, 0)
+	for _, total := range report.Totals {
+		results = append(results, r.mapBudgetTotal(total))
+	}
+	return results, nil
+}
+func (r *eventResolver) Sessions(ctx context.Context, obj *model.Event) ([]*model.Session, error) {
+	service := r.di.Container.Get(services.SessionServiceName).(*services.SessionService)
+	sessions, err := service.GetAll(bson.M{"event": obj.ID})
 	if err != nil {
 		return nil, err
 	}
-	ginContext.FileAttachment(file.Name(), "event.csv")
-	return &model.EventStatisticResponse{Result: "success"}, nil
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].StartDate.Before(sessions[j].StartDate) })
+	results := make([]*model.Session, 0)
+	for _, session := range sessions {
+		results = append(results, r.mapSession(session))
+	}
+	return results, nil
 }
@@ -0,0 +1,56 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/services"
+	"github.com/khanhvtn/netevent-go/utilities"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (r *eventResolver) Series(ctx context.Context, obj *model.Event) (*model.EventSeries, error) {
+	eventService := r.di.Container.Get(services.EventServiceName).(*services.EventService)
+	seriesService := r.di.Container.Get(services.EventSeriesServiceName).(*services.EventSeriesService)
+	event, err := eventService.GetOne(bson.M{"_id": obj.ID})
+	if err != nil {
+		return nil, err
+	}
+	if event.Series == nil {
+		return nil, nil
+	}
+	series, err := seriesService.GetOne(bson.M{"_id": *event.Series})
+	if err != nil {
+		return nil, err
+	}
+	return r.mapEventSeries(series), nil
+}
+func (r *eventSeriesResolver) Events(ctx context.Context, obj *model.EventSeries) ([]*model.Event, error) {
+	service := r.di.Container.Get(services.EventSeriesServiceName).(*services.EventSeriesService)
+	events, err := service.Events(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*model.Event, 0)
+	for _, event := range events {
+		mappedEvent, err := r.mapEvent(event)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, mappedEvent)
+	}
+	return results, nil
+}
+func (r *queryResolver) EventSeries(ctx context.Context, id string) (*model.EventSeries, error) {
+	service := r.di.Container.Get(services.EventSeriesServiceName).(*services.EventSeriesService)
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	series, err := service.GetOne(bson.M{"_id": objectId})
+	if err != nil {
+		return nil, err
+	}
+	return r.mapEventSeries(series), nil
+}
@@ -0,0 +1,70 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/services"
+	"github.com/khanhvtn/netevent-go/utilities"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (r *mutationResolver) CloneEvent(ctx context.Context, id string, overrides model.EventOverrides) (*model.Event, error) {
+	service := r.di.Container.Get(services.EventTemplateServiceName).(*services.EventTemplateService)
+	//check input
+	if err := service.ValidateEventOverrides(overrides); err != nil {
+		return nil, err
+	}
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	newEvent, err := service.Clone(*objectId, overrides)
+	if err != nil {
+		return nil, err
+	}
+	return r.mapEvent(newEvent)
+}
+func (r *mutationResolver) CreateEventTemplate(ctx context.Context, input model.NewEventTemplate) (*model.EventTemplate, error) {
+	service := r.di.Container.Get(services.EventTemplateServiceName).(*services.EventTemplateService)
+	//check input
+	if err := service.ValidateNewEventTemplate(input); err != nil {
+		return nil, err
+	}
+	newTemplate, err := service.Create(input)
+	if err != nil {
+		return nil, err
+	}
+	return r.mapEventTemplate(newTemplate), nil
+}
+func (r *mutationResolver) DeleteEventTemplate(ctx context.Context, id string) (*model.EventTemplate, error) {
+	service := r.di.Container.Get(services.EventTemplateServiceName).(*services.EventTemplateService)
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	deletedTemplate, err := service.DeleteOne(bson.M{"_id": objectId})
+	if err != nil {
+		return nil, err
+	}
+	return r.mapEventTemplate(deletedTemplate), nil
+}
+func (r *mutationResolver) CreateEventFromTemplate(ctx context.Context, templateID string, overrides model.EventOverrides) (*model.Event, error) {
+	service := r.di.Container.Get(services.EventTemplateServiceName).(*services.EventTemplateService)
+	//check input
+	if err := service.ValidateEventOverrides(overrides); err != nil {
+		return nil, err
+	}
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(templateID)
+	if err != nil {
+		return nil, err
+	}
+	newEvent, err := service.Instantiate(*objectId, overrides)
+	if err != nil {
+		return nil, err
+	}
+	return r.mapEvent(newEvent)
+}
@@ -0,0 +1,36 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/services"
+	"github.com/khanhvtn/netevent-go/utilities"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (r *queryResolver) EventTemplates(ctx context.Context) ([]*model.EventTemplate, error) {
+	service := r.di.Container.Get(services.EventTemplateServiceName).(*services.EventTemplateService)
+	templates, err := service.GetAll(bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*model.EventTemplate, 0)
+	for _, template := range templates {
+		results = append(results, r.mapEventTemplate(template))
+	}
+	return results, nil
+}
+func (r *queryResolver) EventTemplate(ctx context.Context, id string) (*model.EventTemplate, error) {
+	service := r.di.Container.Get(services.EventTemplateServiceName).(*services.EventTemplateService)
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	template, err := service.GetOne(bson.M{"_id": objectId})
+	if err != nil {
+		return nil, err
+	}
+	return r.mapEventTemplate(template), nil
+}
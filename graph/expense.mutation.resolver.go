@@ -0,0 +1,80 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/models"
+	"github.com/khanhvtn/netevent-go/services"
+	"github.com/khanhvtn/netevent-go/utilities"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (r *mutationResolver) CreateExpense(ctx context.Context, input model.NewExpense) (*model.Expense, error) {
+	service := r.di.Container.Get(services.ExpenseServiceName).(*services.ExpenseService)
+	//check input
+	if err := service.ValidateNewExpense(input); err != nil {
+		return nil, err
+	}
+	user, err := r.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	newExpense, err := service.Create(input, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return r.mapExpense(newExpense)
+}
+func (r *mutationResolver) UpdateExpense(ctx context.Context, id string, input model.UpdateExpense) (*model.Expense, error) {
+	service := r.di.Container.Get(services.ExpenseServiceName).(*services.ExpenseService)
+	//check input
+	if err := service.ValidateUpdateExpense(input); err != nil {
+		return nil, err
+	}
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	updatedExpense, err := service.UpdateOne(*objectId, input)
+	if err != nil {
+		return nil, err
+	}
+	return r.mapExpense(updatedExpense)
+}
+func (r *mutationResolver) DeleteExpense(ctx context.Context, id string) (*model.Expense, error) {
+	service := r.di.Container.Get(services.ExpenseServiceName).(*services.ExpenseService)
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	deletedExpense, err := service.DeleteOne(bson.M{"_id": objectId})
+	if err != nil {
+		return nil, err
+	}
+	return r.mapExpense(deletedExpense)
+}
+func (r *mutationResolver) ReviewExpense(ctx context.Context, id string, approve bool, note *string) (*model.Expense, error) {
+	service := r.di.Container.Get(services.ExpenseServiceName).(*services.ExpenseService)
+	//only reviewers decide on expenses over the threshold
+	user, err := r.currentUserWithRole(ctx, models.UserRoleReviewer)
+	if err != nil {
+		return nil, err
+	}
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	reviewNote := ""
+	if note != nil {
+		reviewNote = *note
+	}
+	reviewedExpense, err := service.Review(*objectId, user.ID, approve, reviewNote)
+	if err != nil {
+		return nil, err
+	}
+	return r.mapExpense(reviewedExpense)
+}
@@ -0,0 +1,44 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/services"
+	"github.com/khanhvtn/netevent-go/utilities"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (r *queryResolver) Expenses(ctx context.Context, status *model.ExpenseStatus) ([]*model.Expense, error) {
+	service := r.di.Container.Get(services.ExpenseServiceName).(*services.ExpenseService)
+	condition := bson.M{}
+	if status != nil {
+		condition["status"] = status.String()
+	}
+	expenses, err := service.GetAll(condition)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*model.Expense, 0)
+	for _, expense := range expenses {
+		mappedExpense, err := r.mapExpense(expense)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, mappedExpense)
+	}
+	return results, nil
+}
+func (r *queryResolver) Expense(ctx context.Context, id string) (*model.Expense, error) {
+	service := r.di.Container.Get(services.ExpenseServiceName).(*services.ExpenseService)
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	expense, err := service.GetOne(bson.M{"_id": objectId})
+	if err != nil {
+		return nil, err
+	}
+	return r.mapExpense(expense)
+}
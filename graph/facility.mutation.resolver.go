@@ -31,17 +31,12 @@ func (r *mutationResolver) UpdateFacility(ctx context.Context, id string, input
 	if err := service.ValidateUpdateFacility(id, input); err != nil {
 		return nil, err
 	}
-	//cast UpdateFacility to bson.M type
-	newUpdate, err := utilities.InterfaceToBsonM(input)
-	if err != nil {
-		return nil, err
-	}
 	//convert string id to object id
 	objectId, err := utilities.ConvertStringIdToObjectID(id)
 	if err != nil {
 		return nil, err
 	}
-	updatedFacility, err := service.UpdateOne(bson.M{"_id": objectId}, newUpdate)
+	updatedFacility, err := service.UpdateOne(bson.M{"_id": objectId}, input)
 	if err != nil {
 		return nil, err
 	}
@@ -2,6 +2,7 @@ package graph
 
 import (
 	"context"
+	"time"
 
 	"github.com/khanhvtn/netevent-go/graph/model"
 	"github.com/khanhvtn/netevent-go/services"
@@ -44,3 +45,70 @@ func (r *queryResolver) Facility(ctx context.Context, id string) (*model.Facilit
 	}
 	return result, nil
 }
+
+func (r *queryResolver) FacilityAvailability(ctx context.Context, facilityID string, from time.Time, to time.Time, quantity *int) (*model.FacilityAvailability, error) {
+	facilityService := r.di.Container.Get(services.FacilityServiceName).(*services.FacilityService)
+	facilityHistoryService := r.di.Container.Get(services.FacilityHistoryServiceName).(*services.FacilityHistoryService)
+	//check input
+	if err := services.ValidateBookingPeriod(from, to); err != nil {
+		return nil, err
+	}
+	requested := 1
+	if quantity != nil {
+		requested = *quantity
+	}
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(facilityID)
+	if err != nil {
+		return nil, err
+	}
+	facility, err := facilityService.GetOne(bson.M{"_id": objectId})
+	if err != nil {
+		return nil, err
+	}
+	conflicts, err := facilityHistoryService.FindConflicts(facility.ID, from, to, nil)
+	if err != nil {
+		return nil, err
+	}
+	booked := facilityHistoryService.BookedQuantity(conflicts, from, to)
+	mappedFacility, err := r.mapFacility(facility)
+	if err != nil {
+		return nil, err
+	}
+	mappedConflicts := make([]*model.FacilityHistory, 0)
+	for _, facilityHistory := range conflicts {
+		mappedFacilityHistory, err := r.mapFacilityHistory(facilityHistory)
+		if err != nil {
+			return nil, err
+		}
+		mappedConflicts = append(mappedConflicts, mappedFacilityHistory)
+	}
+	return &model.FacilityAvailability{
+		Facility:          mappedFacility,
+		From:              from,
+		To:                to,
+		Stock:             facility.Stock(),
+		Booked:            booked,
+		AvailableQuantity: facility.Stock() - booked,
+		Available:         facility.Stock()-booked >= requested,
+		Conflicts:         mappedConflicts,
+	}, nil
+}
+
+func (r *facilityResolver) Type(ctx context.Context, obj *model.Facility) (*model.FacilityType, error) {
+	facilityTypeService := r.di.Container.Get(services.FacilityTypeServiceName).(*services.FacilityTypeService)
+	facilityService := r.di.Container.Get(services.FacilityServiceName).(*services.FacilityService)
+	facility, err := facilityService.GetOne(bson.M{"_id": obj.ID})
+	if err != nil {
+		return nil, err
+	}
+	facilityType, err := facilityTypeService.GetOne(bson.M{"_id": facility.Type})
+	if err != nil {
+		return nil, err
+	}
+	results, err := r.mapFacilityType(facilityType)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
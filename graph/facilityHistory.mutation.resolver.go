@@ -63,3 +63,47 @@ func (r *mutationResolver) DeleteFacilityHistory(ctx context.Context, id string)
 	}
 	return results, nil
 }
+func (r *mutationResolver) CheckOutFacility(ctx context.Context, id string, input model.CheckOutFacility) (*model.FacilityHistory, error) {
+	service := r.di.Container.Get(services.FacilityHistoryServiceName).(*services.FacilityHistoryService)
+	//the logged in user is the staff handing the facility over
+	staff, err := r.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	checkedOutFacilityHistory, err := service.CheckOut(bson.M{"_id": objectId}, staff.ID, input)
+	if err != nil {
+		return nil, err
+	}
+	results, err := r.mapFacilityHistory(checkedOutFacilityHistory)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+func (r *mutationResolver) ReturnFacility(ctx context.Context, id string, input model.ReturnFacility) (*model.FacilityHistory, error) {
+	service := r.di.Container.Get(services.FacilityHistoryServiceName).(*services.FacilityHistoryService)
+	//the logged in user is the staff taking the facility back
+	staff, err := r.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	returnedFacilityHistory, err := service.Return(bson.M{"_id": objectId}, staff.ID, input)
+	if err != nil {
+		return nil, err
+	}
+	results, err := r.mapFacilityHistory(returnedFacilityHistory)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
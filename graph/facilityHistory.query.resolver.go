@@ -2,6 +2,7 @@ package graph
 
 import (
 	"context"
+	"time"
 
 	"github.com/khanhvtn/netevent-go/graph/model"
 	"github.com/khanhvtn/netevent-go/services"
@@ -79,3 +80,20 @@ func (r *queryResolver) FacilityHistory(ctx context.Context, id string) (*model.
 	}
 	return result, nil
 }
+
+func (r *queryResolver) OverdueFacilities(ctx context.Context) ([]*model.FacilityHistory, error) {
+	service := r.di.Container.Get(services.FacilityHistoryServiceName).(*services.FacilityHistoryService)
+	facilityHistories, err := service.GetOverdue(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*model.FacilityHistory, 0)
+	for _, facilityHistory := range facilityHistories {
+		mappedFacilityHistory, err := r.mapFacilityHistory(facilityHistory)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, mappedFacilityHistory)
+	}
+	return results, nil
+}
@@ -0,0 +1,66 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/services"
+	"github.com/khanhvtn/netevent-go/utilities"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (r *mutationResolver) CreateFacilityType(ctx context.Context, input model.NewFacilityType) (*model.FacilityType, error) {
+	service := r.di.Container.Get(services.FacilityTypeServiceName).(*services.FacilityTypeService)
+	//check input
+	if err := service.ValidateNewFacilityType(input); err != nil {
+		return nil, err
+	}
+	newFacilityType, err := service.Create(input)
+	if err != nil {
+		return nil, err
+	}
+	results, err := r.mapFacilityType(newFacilityType)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+func (r *mutationResolver) UpdateFacilityType(ctx context.Context, id string, input model.UpdateFacilityType) (*model.FacilityType, error) {
+
+	service := r.di.Container.Get(services.FacilityTypeServiceName).(*services.FacilityTypeService)
+	//check input
+	if err := service.ValidateUpdateFacilityType(id, input); err != nil {
+		return nil, err
+	}
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	updatedFacilityType, err := service.UpdateOne(bson.M{"_id": objectId}, input)
+	if err != nil {
+		return nil, err
+	}
+	results, err := r.mapFacilityType(updatedFacilityType)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+func (r *mutationResolver) DeleteFacilityType(ctx context.Context, id string) (*model.FacilityType, error) {
+	service := r.di.Container.Get(services.FacilityTypeServiceName).(*services.FacilityTypeService)
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	deletedFacilityType, err := service.DeleteOne(bson.M{"_id": objectId})
+	if err != nil {
+		return nil, err
+	}
+	results, err := r.mapFacilityType(deletedFacilityType)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
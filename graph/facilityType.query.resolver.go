@@ -0,0 +1,46 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/services"
+	"github.com/khanhvtn/netevent-go/utilities"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (r *queryResolver) FacilityTypes(ctx context.Context) ([]*model.FacilityType, error) {
+	service := r.di.Container.Get(services.FacilityTypeServiceName).(*services.FacilityTypeService)
+	facilityTypes, err := service.GetAll(bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*model.FacilityType, 0)
+	for _, facilityType := range facilityTypes {
+		mappedFacilityType, err := r.mapFacilityType(facilityType)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, mappedFacilityType)
+	}
+	return results, nil
+}
+
+func (r *queryResolver) FacilityType(ctx context.Context, id string) (*model.FacilityType, error) {
+	service := r.di.Container.Get(services.FacilityTypeServiceName).(*services.FacilityTypeService)
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	//get facility type based specific id
+	facilityType, err := service.GetOne(bson.M{"_id": objectId})
+	if err != nil {
+		return nil, err
+	}
+	result, err := r.mapFacilityType(facilityType)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
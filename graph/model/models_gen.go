@@ -3,48 +3,231 @@
 package model
 
 import (
+	"fmt"
+	"io"
+	"strconv"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+type ApprovalTurnaround struct {
+	Approved     int     `json:"approved" bson:"approved"`
+	AverageHours float64 `json:"averageHours" bson:"averageHours"`
+	MinHours     float64 `json:"minHours" bson:"minHours"`
+	MaxHours     float64 `json:"maxHours" bson:"maxHours"`
+}
+
+type AttendanceMonth struct {
+	Month      time.Time `json:"month" bson:"month"`
+	Registered int       `json:"registered" bson:"registered"`
+	Attended   int       `json:"attended" bson:"attended"`
+	Rate       float64   `json:"rate" bson:"rate"`
+}
+
+type BreakdownCount struct {
+	Value string `json:"value" bson:"value"`
+	Count int    `json:"count" bson:"count"`
+}
+
+type BudgetItem struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id"`
+	CreatedAt   time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt   time.Time          `json:"updatedAt" bson:"updatedAt"`
+	EventID     primitive.ObjectID `json:"eventId" bson:"eventId"`
+	Category    string             `json:"category" bson:"category"`
+	Description string             `json:"description" bson:"description"`
+	Planned     int64              `json:"planned" bson:"planned"`
+	Currency    string             `json:"currency" bson:"currency"`
+	Spent       int64              `json:"spent" bson:"spent"`
+	Pending     int64              `json:"pending" bson:"pending"`
+	Variance    int64              `json:"variance" bson:"variance"`
+	Expenses    []*Expense         `json:"expenses" bson:"expenses"`
+}
+
+type BudgetTotal struct {
+	Currency string `json:"currency" bson:"currency"`
+	Planned  int64  `json:"planned" bson:"planned"`
+	Spent    int64  `json:"spent" bson:"spent"`
+	Pending  int64  `json:"pending" bson:"pending"`
+	Variance int64  `json:"variance" bson:"variance"`
+}
+
+type CheckIn struct {
+	At     time.Time `json:"at" bson:"at"`
+	Staff  *User     `json:"staff" bson:"staff"`
+	Device string    `json:"device" bson:"device"`
+}
+
+type CheckOutFacility struct {
+	Condition    *string      `json:"condition" bson:"condition"`
+	DamageStatus DamageStatus `json:"damageStatus" bson:"damageStatus"`
+}
+
 type CustomizeField struct {
-	Name     string   `json:"name" bson:"name"`
-	Type     string   `json:"type" bson:"type"`
-	Value    []string `json:"value" bson:"value"`
-	Required bool     `json:"required" bson:"required"`
+	Name      string             `json:"name" bson:"name"`
+	Type      CustomizeFieldType `json:"type" bson:"type"`
+	Value     []string           `json:"value" bson:"value"`
+	Required  bool               `json:"required" bson:"required"`
+	Order     int                `json:"order" bson:"order"`
+	Min       *float64           `json:"min" bson:"min"`
+	Max       *float64           `json:"max" bson:"max"`
+	Pattern   *string            `json:"pattern" bson:"pattern"`
+	MaxLength *int               `json:"maxLength" bson:"maxLength"`
+}
+
+type CustomizeFieldVersion struct {
+	Version   int               `json:"version" bson:"version"`
+	CreatedAt time.Time         `json:"createdAt" bson:"createdAt"`
+	Fields    []*CustomizeField `json:"fields" bson:"fields"`
+}
+
+type DailyCount struct {
+	Date       time.Time `json:"date" bson:"date"`
+	Count      int       `json:"count" bson:"count"`
+	Cumulative int       `json:"cumulative" bson:"cumulative"`
+}
+
+type Dashboard struct {
+	From               time.Time              `json:"from" bson:"from"`
+	To                 time.Time              `json:"to" bson:"to"`
+	Events             int                    `json:"events" bson:"events"`
+	Budgets            []*DashboardBudget     `json:"budgets" bson:"budgets"`
+	EventsPerType      []*EventTypeMonthCount `json:"eventsPerType" bson:"eventsPerType"`
+	ApprovalTurnaround *ApprovalTurnaround    `json:"approvalTurnaround" bson:"approvalTurnaround"`
+	Attendance         []*AttendanceMonth     `json:"attendance" bson:"attendance"`
+	BusiestFacilities  []*FacilityUsage       `json:"busiestFacilities" bson:"busiestFacilities"`
+	TopStaff           []*StaffAssignment     `json:"topStaff" bson:"topStaff"`
+}
+
+type DashboardBudget struct {
+	Currency string `json:"currency" bson:"currency"`
+	Total    int64  `json:"total" bson:"total"`
+	Events   int    `json:"events" bson:"events"`
+	Average  int64  `json:"average" bson:"average"`
+}
+
+type DeleteImpact struct {
+	Collection string               `json:"collection" bson:"collection"`
+	Field      string               `json:"field" bson:"field"`
+	Action     string               `json:"action" bson:"action"`
+	Count      int                  `json:"count" bson:"count"`
+	Ids        []primitive.ObjectID `json:"ids" bson:"ids"`
+}
+
+type DeletePreview struct {
+	Allowed  bool            `json:"allowed" bson:"allowed"`
+	Blockers []*DeleteImpact `json:"blockers" bson:"blockers"`
+	Impacts  []*DeleteImpact `json:"impacts" bson:"impacts"`
+}
+
+type EmailTemplate struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time          `json:"updatedAt" bson:"updatedAt"`
+	Name      EmailTemplateName  `json:"name" bson:"name"`
+	Language  MailLanguage       `json:"language" bson:"language"`
+	Subject   string             `json:"subject" bson:"subject"`
+	HTML      string             `json:"html" bson:"html"`
+	Text      string             `json:"text" bson:"text"`
 }
 
 type Event struct {
-	ID                    primitive.ObjectID `json:"id" bson:"_id"`
-	CreatedAt             time.Time          `json:"createdAt" bson:"createdAt"`
-	UpdatedAt             time.Time          `json:"updatedAt" bson:"updatedAt"`
-	Tags                  []string           `json:"tags" bson:"tags"`
-	IsApproved            bool               `json:"isApproved" bson:"isApproved"`
-	Reviewer              *User              `json:"reviewer" bson:"reviewer"`
-	IsFinished            bool               `json:"isFinished" bson:"isFinished"`
-	Tasks                 []*Task            `json:"tasks" bson:"tasks"`
-	FacilityHistories     []*FacilityHistory `json:"facilityHistories" bson:"facilityHistories"`
-	Name                  string             `json:"name" bson:"name"`
-	Language              string             `json:"language" bson:"language"`
-	EventType             *EventType         `json:"eventType" bson:"eventType"`
-	Mode                  string             `json:"mode" bson:"mode"`
-	Location              string             `json:"location" bson:"location"`
-	Accommodation         string             `json:"accommodation" bson:"accommodation"`
-	RegistrationCloseDate time.Time          `json:"registrationCloseDate" bson:"registrationCloseDate"`
-	StartDate             time.Time          `json:"startDate" bson:"startDate"`
-	EndDate               time.Time          `json:"endDate" bson:"endDate"`
-	MaxParticipants       int                `json:"maxParticipants" bson:"maxParticipants"`
-	Description           string             `json:"description" bson:"description"`
-	Owner                 *User              `json:"owner" bson:"owner"`
-	Budget                float64            `json:"budget" bson:"budget"`
-	Image                 string             `json:"image" bson:"image"`
-	IsDeleted             bool               `json:"isDeleted" bson:"isDeleted"`
-	CustomizeFields       []*CustomizeField  `json:"customizeFields" bson:"customizeFields"`
-}
-
-type EventStatisticResponse struct {
-	Result string `json:"result" bson:"result"`
+	ID                     primitive.ObjectID       `json:"id" bson:"_id"`
+	CreatedAt              time.Time                `json:"createdAt" bson:"createdAt"`
+	UpdatedAt              time.Time                `json:"updatedAt" bson:"updatedAt"`
+	Tags                   []string                 `json:"tags" bson:"tags"`
+	IsApproved             bool                     `json:"isApproved" bson:"isApproved"`
+	ApprovedAt             *time.Time               `json:"approvedAt" bson:"approvedAt"`
+	Reviewer               *User                    `json:"reviewer" bson:"reviewer"`
+	IsFinished             bool                     `json:"isFinished" bson:"isFinished"`
+	Tasks                  []*Task                  `json:"tasks" bson:"tasks"`
+	FacilityHistories      []*FacilityHistory       `json:"facilityHistories" bson:"facilityHistories"`
+	Name                   string                   `json:"name" bson:"name"`
+	Language               string                   `json:"language" bson:"language"`
+	EventType              *EventType               `json:"eventType" bson:"eventType"`
+	Mode                   string                   `json:"mode" bson:"mode"`
+	Location               string                   `json:"location" bson:"location"`
+	Accommodation          string                   `json:"accommodation" bson:"accommodation"`
+	RegistrationCloseDate  time.Time                `json:"registrationCloseDate" bson:"registrationCloseDate"`
+	StartDate              time.Time                `json:"startDate" bson:"startDate"`
+	EndDate                time.Time                `json:"endDate" bson:"endDate"`
+	MaxParticipants        int                      `json:"maxParticipants" bson:"maxParticipants"`
+	RegisteredCount        int                      `json:"registeredCount" bson:"registeredCount"`
+	Description            string                   `json:"description" bson:"description"`
+	Owner                  *User                    `json:"owner" bson:"owner"`
+	Budget                 float64                  `json:"budget" bson:"budget"`
+	BudgetItems            []*BudgetItem            `json:"budgetItems" bson:"budgetItems"`
+	BudgetTotals           []*BudgetTotal           `json:"budgetTotals" bson:"budgetTotals"`
+	Sessions               []*Session               `json:"sessions" bson:"sessions"`
+	Image                  string                   `json:"image" bson:"image"`
+	IsDeleted              bool                     `json:"isDeleted" bson:"isDeleted"`
+	CustomizeFields        []*CustomizeField        `json:"customizeFields" bson:"customizeFields"`
+	CustomizeFieldsVersion int                      `json:"customizeFieldsVersion" bson:"customizeFieldsVersion"`
+	CustomizeFieldVersions []*CustomizeFieldVersion `json:"customizeFieldVersions" bson:"customizeFieldVersions"`
+	Series                 *EventSeries             `json:"series" bson:"series"`
+	OccurrenceStart        *time.Time               `json:"occurrenceStart" bson:"occurrenceStart"`
+}
+
+type EventOverrides struct {
+	Name                  string     `json:"name" bson:"name"`
+	StartDate             time.Time  `json:"startDate" bson:"startDate"`
+	EndDate               *time.Time `json:"endDate" bson:"endDate"`
+	RegistrationCloseDate *time.Time `json:"registrationCloseDate" bson:"registrationCloseDate"`
+	OwnerID               *string    `json:"ownerId" bson:"ownerId"`
+	Location              *string    `json:"location" bson:"location"`
+	MaxParticipants       *int       `json:"maxParticipants" bson:"maxParticipants"`
+}
+
+type EventSeries struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt  time.Time          `json:"updatedAt" bson:"updatedAt"`
+	Rule       string             `json:"rule" bson:"rule"`
+	Start      time.Time          `json:"start" bson:"start"`
+	Exceptions []*time.Time       `json:"exceptions" bson:"exceptions"`
+	Events     []*Event           `json:"events" bson:"events"`
+}
+
+type EventStatistic struct {
+	Event             *Event            `json:"event" bson:"event"`
+	Registrations     []*DailyCount     `json:"registrations" bson:"registrations"`
+	TotalParticipants int               `json:"totalParticipants" bson:"totalParticipants"`
+	Registered        int               `json:"registered" bson:"registered"`
+	Waitlisted        int               `json:"waitlisted" bson:"waitlisted"`
+	Valid             int               `json:"valid" bson:"valid"`
+	Invalid           int               `json:"invalid" bson:"invalid"`
+	Attended          int               `json:"attended" bson:"attended"`
+	AttendanceRate    float64           `json:"attendanceRate" bson:"attendanceRate"`
+	CapacityFill      *float64          `json:"capacityFill" bson:"capacityFill"`
+	BySchool          []*BreakdownCount `json:"bySchool" bson:"bySchool"`
+	ByMajor           []*BreakdownCount `json:"byMajor" bson:"byMajor"`
+	ByAcademic        []*BreakdownCount `json:"byAcademic" bson:"byAcademic"`
+	Tasks             *TaskStatistic    `json:"tasks" bson:"tasks"`
+	Facilities        []*FacilityUsage  `json:"facilities" bson:"facilities"`
+}
+
+type EventTemplate struct {
+	ID                    primitive.ObjectID  `json:"id" bson:"_id"`
+	CreatedAt             time.Time           `json:"createdAt" bson:"createdAt"`
+	UpdatedAt             time.Time           `json:"updatedAt" bson:"updatedAt"`
+	Name                  string              `json:"name" bson:"name"`
+	Tags                  []string            `json:"tags" bson:"tags"`
+	Language              string              `json:"language" bson:"language"`
+	EventTypeID           primitive.ObjectID  `json:"eventTypeId" bson:"eventTypeId"`
+	Mode                  string              `json:"mode" bson:"mode"`
+	Location              string              `json:"location" bson:"location"`
+	Accommodation         string              `json:"accommodation" bson:"accommodation"`
+	MaxParticipants       int                 `json:"maxParticipants" bson:"maxParticipants"`
+	Description           string              `json:"description" bson:"description"`
+	OwnerID               primitive.ObjectID  `json:"ownerId" bson:"ownerId"`
+	Budget                float64             `json:"budget" bson:"budget"`
+	Image                 string              `json:"image" bson:"image"`
+	CustomizeFields       []*CustomizeField   `json:"customizeFields" bson:"customizeFields"`
+	Duration              int                 `json:"duration" bson:"duration"`
+	RegistrationCloseLead int                 `json:"registrationCloseLead" bson:"registrationCloseLead"`
+	Tasks                 []*TemplateTask     `json:"tasks" bson:"tasks"`
+	Facilities            []*TemplateFacility `json:"facilities" bson:"facilities"`
 }
 
 type EventType struct {
@@ -55,6 +238,34 @@ type EventType struct {
 	IsDeleted bool               `json:"isDeleted" bson:"isDeleted"`
 }
 
+type EventTypeMonthCount struct {
+	EventType *EventType `json:"eventType" bson:"eventType"`
+	Month     time.Time  `json:"month" bson:"month"`
+	Count     int        `json:"count" bson:"count"`
+}
+
+type Expense struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id"`
+	CreatedAt    time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt    time.Time          `json:"updatedAt" bson:"updatedAt"`
+	EventID      primitive.ObjectID `json:"eventId" bson:"eventId"`
+	BudgetItemID primitive.ObjectID `json:"budgetItemId" bson:"budgetItemId"`
+	Description  string             `json:"description" bson:"description"`
+	Amount       int64              `json:"amount" bson:"amount"`
+	Currency     string             `json:"currency" bson:"currency"`
+	SpentAt      time.Time          `json:"spentAt" bson:"spentAt"`
+	ReceiptURL   string             `json:"receiptUrl" bson:"receiptUrl"`
+	Status       ExpenseStatus      `json:"status" bson:"status"`
+	SubmittedBy  *User              `json:"submittedBy" bson:"submittedBy"`
+	Review       *ExpenseReview     `json:"review" bson:"review"`
+}
+
+type ExpenseReview struct {
+	At       time.Time `json:"at" bson:"at"`
+	Reviewer *User     `json:"reviewer" bson:"reviewer"`
+	Note     string    `json:"note" bson:"note"`
+}
+
 type Facility struct {
 	ID        primitive.ObjectID `json:"id" bson:"_id"`
 	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
@@ -62,25 +273,85 @@ type Facility struct {
 	Status    bool               `json:"status" bson:"status"`
 	Name      string             `json:"name" bson:"name"`
 	Code      string             `json:"code" bson:"code"`
-	Type      string             `json:"type" bson:"type"`
+	Type      *FacilityType      `json:"type" bson:"type"`
+	Quantity  int                `json:"quantity" bson:"quantity"`
 	IsDeleted bool               `json:"isDeleted" bson:"isDeleted"`
 }
 
+type FacilityAvailability struct {
+	Facility          *Facility          `json:"facility" bson:"facility"`
+	From              time.Time          `json:"from" bson:"from"`
+	To                time.Time          `json:"to" bson:"to"`
+	Stock             int                `json:"stock" bson:"stock"`
+	Booked            int                `json:"booked" bson:"booked"`
+	AvailableQuantity int                `json:"availableQuantity" bson:"availableQuantity"`
+	Available         bool               `json:"available" bson:"available"`
+	Conflicts         []*FacilityHistory `json:"conflicts" bson:"conflicts"`
+}
+
 type FacilityHistory struct {
 	ID         primitive.ObjectID `json:"id" bson:"_id"`
 	CreatedAt  time.Time          `json:"createdAt" bson:"createdAt"`
 	UpdatedAt  time.Time          `json:"updatedAt" bson:"updatedAt"`
 	Facility   *Facility          `json:"facility" bson:"facility"`
+	Quantity   int                `json:"quantity" bson:"quantity"`
 	BorrowDate time.Time          `json:"borrowDate" bson:"borrowDate"`
 	ReturnDate time.Time          `json:"returnDate" bson:"returnDate"`
 	Event      *Event             `json:"event" bson:"event"`
+	CheckedOut *FacilityMovement  `json:"checkedOut" bson:"checkedOut"`
+	Returned   *FacilityMovement  `json:"returned" bson:"returned"`
+	IsLate     bool               `json:"isLate" bson:"isLate"`
+}
+
+type FacilityMovement struct {
+	At           time.Time    `json:"at" bson:"at"`
+	Staff        *User        `json:"staff" bson:"staff"`
+	Condition    string       `json:"condition" bson:"condition"`
+	DamageStatus DamageStatus `json:"damageStatus" bson:"damageStatus"`
+}
+
+type FacilityType struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time          `json:"updatedAt" bson:"updatedAt"`
+	Name      string             `json:"name" bson:"name"`
+	IsDeleted bool               `json:"isDeleted" bson:"isDeleted"`
+}
+
+type FacilityUsage struct {
+	Facility   *Facility `json:"facility" bson:"facility"`
+	Bookings   int       `json:"bookings" bson:"bookings"`
+	Quantity   int       `json:"quantity" bson:"quantity"`
+	CheckedOut int       `json:"checkedOut" bson:"checkedOut"`
+	Returned   int       `json:"returned" bson:"returned"`
+}
+
+type FieldAnswer struct {
+	Name   string   `json:"name" bson:"name"`
+	Values []string `json:"values" bson:"values"`
+}
+
+type ImportRowError struct {
+	Row      int      `json:"row" bson:"row"`
+	Email    string   `json:"email" bson:"email"`
+	Messages []string `json:"messages" bson:"messages"`
 }
 
 type InputCustomizeField struct {
-	Name     string   `json:"name" bson:"name"`
-	Type     string   `json:"type" bson:"type"`
-	Value    []string `json:"value" bson:"value"`
-	Required bool     `json:"required" bson:"required"`
+	Name      string             `json:"name" bson:"name"`
+	Type      CustomizeFieldType `json:"type" bson:"type"`
+	Value     []string           `json:"value" bson:"value"`
+	Required  bool               `json:"required" bson:"required"`
+	Order     *int               `json:"order" bson:"order"`
+	Min       *float64           `json:"min" bson:"min"`
+	Max       *float64           `json:"max" bson:"max"`
+	Pattern   *string            `json:"pattern" bson:"pattern"`
+	MaxLength *int               `json:"maxLength" bson:"maxLength"`
+}
+
+type InputFieldAnswer struct {
+	Name   string   `json:"name" bson:"name"`
+	Values []string `json:"values" bson:"values"`
 }
 
 type Login struct {
@@ -88,6 +359,21 @@ type Login struct {
 	Password string `json:"password" bson:"password"`
 }
 
+type NewBudgetItem struct {
+	Category    string  `json:"category" bson:"category"`
+	Description *string `json:"description" bson:"description"`
+	Planned     int64   `json:"planned" bson:"planned"`
+	Currency    string  `json:"currency" bson:"currency"`
+}
+
+type NewEmailTemplate struct {
+	Name     EmailTemplateName `json:"name" bson:"name"`
+	Language MailLanguage      `json:"language" bson:"language"`
+	Subject  string            `json:"subject" bson:"subject"`
+	HTML     string            `json:"html" bson:"html"`
+	Text     string            `json:"text" bson:"text"`
+}
+
 type NewEvent struct {
 	Tags                  []string               `json:"tags" bson:"tags"`
 	Tasks                 []*NewTask             `json:"tasks" bson:"tasks"`
@@ -107,16 +393,31 @@ type NewEvent struct {
 	Budget                float64                `json:"budget" bson:"budget"`
 	Image                 string                 `json:"image" bson:"image"`
 	CustomizeFields       []*InputCustomizeField `json:"customizeFields" bson:"customizeFields"`
+	Recurrence            *RecurrenceInput       `json:"recurrence" bson:"recurrence"`
+}
+
+type NewEventTemplate struct {
+	EventID string `json:"eventId" bson:"eventId"`
+	Name    string `json:"name" bson:"name"`
 }
 
 type NewEventType struct {
 	Name string `json:"name" bson:"name"`
 }
 
+type NewExpense struct {
+	BudgetItemID string    `json:"budgetItemId" bson:"budgetItemId"`
+	Description  string    `json:"description" bson:"description"`
+	Amount       int64     `json:"amount" bson:"amount"`
+	SpentAt      time.Time `json:"spentAt" bson:"spentAt"`
+	ReceiptURL   string    `json:"receiptUrl" bson:"receiptUrl"`
+}
+
 type NewFacility struct {
-	Name string `json:"name" bson:"name"`
-	Code string `json:"code" bson:"code"`
-	Type string `json:"type" bson:"type"`
+	Name     string `json:"name" bson:"name"`
+	Code     string `json:"code" bson:"code"`
+	TypeID   string `json:"typeId" bson:"typeId"`
+	Quantity *int   `json:"quantity" bson:"quantity"`
 }
 
 type NewFacilityHistory struct {
@@ -125,18 +426,36 @@ type NewFacilityHistory struct {
 	BorrowDate time.Time `json:"borrowDate" bson:"borrowDate"`
 	ReturnDate time.Time `json:"returnDate" bson:"returnDate"`
 	EventID    *string   `json:"eventId" bson:"eventId"`
+	Quantity   *int      `json:"quantity" bson:"quantity"`
+}
+
+type NewFacilityType struct {
+	Name string `json:"name" bson:"name"`
 }
 
 type NewParticipant struct {
-	EventID              string    `json:"eventId" bson:"eventId"`
-	Email                string    `json:"email" bson:"email"`
-	Name                 string    `json:"name" bson:"name"`
-	Academic             string    `json:"academic" bson:"academic"`
-	School               string    `json:"school" bson:"school"`
-	Major                string    `json:"major" bson:"major"`
-	Phone                string    `json:"phone" bson:"phone"`
-	Dob                  time.Time `json:"dob" bson:"dob"`
-	ExpectedGraduateDate time.Time `json:"expectedGraduateDate" bson:"expectedGraduateDate"`
+	EventID              string              `json:"eventId" bson:"eventId"`
+	Email                string              `json:"email" bson:"email"`
+	Name                 string              `json:"name" bson:"name"`
+	Academic             string              `json:"academic" bson:"academic"`
+	School               string              `json:"school" bson:"school"`
+	Major                string              `json:"major" bson:"major"`
+	Phone                string              `json:"phone" bson:"phone"`
+	Dob                  time.Time           `json:"dob" bson:"dob"`
+	ExpectedGraduateDate time.Time           `json:"expectedGraduateDate" bson:"expectedGraduateDate"`
+	Answers              []*InputFieldAnswer `json:"answers" bson:"answers"`
+}
+
+type NewSession struct {
+	EventID     string          `json:"eventId" bson:"eventId"`
+	Title       string          `json:"title" bson:"title"`
+	Description *string         `json:"description" bson:"description"`
+	Track       *string         `json:"track" bson:"track"`
+	StartDate   time.Time       `json:"startDate" bson:"startDate"`
+	EndDate     time.Time       `json:"endDate" bson:"endDate"`
+	RoomID      *string         `json:"roomId" bson:"roomId"`
+	Speakers    []*SpeakerInput `json:"speakers" bson:"speakers"`
+	Capacity    *int            `json:"capacity" bson:"capacity"`
 }
 
 type NewTask struct {
@@ -156,21 +475,105 @@ type NewUser struct {
 	Roles           []string `json:"roles" bson:"roles"`
 }
 
+type OutboxMessage struct {
+	ID            primitive.ObjectID  `json:"id" bson:"_id"`
+	CreatedAt     time.Time           `json:"createdAt" bson:"createdAt"`
+	UpdatedAt     time.Time           `json:"updatedAt" bson:"updatedAt"`
+	Kind          OutboxKind          `json:"kind" bson:"kind"`
+	ParticipantID *primitive.ObjectID `json:"participantId" bson:"participantId"`
+	EventID       *primitive.ObjectID `json:"eventId" bson:"eventId"`
+	UserID        *primitive.ObjectID `json:"userId" bson:"userId"`
+	TaskID        *primitive.ObjectID `json:"taskId" bson:"taskId"`
+	To            string              `json:"to" bson:"to"`
+	Status        OutboxStatus        `json:"status" bson:"status"`
+	Attempts      int                 `json:"attempts" bson:"attempts"`
+	NextAttemptAt time.Time           `json:"nextAttemptAt" bson:"nextAttemptAt"`
+	LastError     string              `json:"lastError" bson:"lastError"`
+	SentAt        *time.Time          `json:"sentAt" bson:"sentAt"`
+}
+
 type Participant struct {
-	ID                   primitive.ObjectID `json:"id" bson:"_id"`
-	CreatedAt            time.Time          `json:"createdAt" bson:"createdAt"`
-	UpdatedAt            time.Time          `json:"updatedAt" bson:"updatedAt"`
-	IsValid              bool               `json:"isValid" bson:"isValid"`
-	IsAttended           bool               `json:"isAttended" bson:"isAttended"`
-	Event                *Event             `json:"event" bson:"event"`
-	Email                string             `json:"email" bson:"email"`
-	Name                 string             `json:"name" bson:"name"`
-	Academic             string             `json:"academic" bson:"academic"`
-	School               string             `json:"school" bson:"school"`
-	Major                string             `json:"major" bson:"major"`
-	Phone                string             `json:"phone" bson:"phone"`
-	Dob                  time.Time          `json:"dob" bson:"dob"`
-	ExpectedGraduateDate time.Time          `json:"expectedGraduateDate" bson:"expectedGraduateDate"`
+	ID                     primitive.ObjectID `json:"id" bson:"_id"`
+	CreatedAt              time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt              time.Time          `json:"updatedAt" bson:"updatedAt"`
+	IsValid                bool               `json:"isValid" bson:"isValid"`
+	IsAttended             bool               `json:"isAttended" bson:"isAttended"`
+	Status                 ParticipantStatus  `json:"status" bson:"status"`
+	Event                  *Event             `json:"event" bson:"event"`
+	Email                  string             `json:"email" bson:"email"`
+	Name                   string             `json:"name" bson:"name"`
+	Academic               string             `json:"academic" bson:"academic"`
+	School                 string             `json:"school" bson:"school"`
+	Major                  string             `json:"major" bson:"major"`
+	Phone                  string             `json:"phone" bson:"phone"`
+	Dob                    time.Time          `json:"dob" bson:"dob"`
+	ExpectedGraduateDate   time.Time          `json:"expectedGraduateDate" bson:"expectedGraduateDate"`
+	CheckIn                *CheckIn           `json:"checkIn" bson:"checkIn"`
+	Sessions               []*Session         `json:"sessions" bson:"sessions"`
+	Answers                []*FieldAnswer     `json:"answers" bson:"answers"`
+	CustomizeFieldsVersion int                `json:"customizeFieldsVersion" bson:"customizeFieldsVersion"`
+}
+
+type ParticipantImport struct {
+	Mode           ImportMode        `json:"mode" bson:"mode"`
+	Total          int               `json:"total" bson:"total"`
+	Valid          int               `json:"valid" bson:"valid"`
+	Imported       []*Participant    `json:"imported" bson:"imported"`
+	Errors         []*ImportRowError `json:"errors" bson:"errors"`
+	IgnoredColumns []string          `json:"ignoredColumns" bson:"ignoredColumns"`
+}
+
+type RecurrenceInput struct {
+	Rule       string       `json:"rule" bson:"rule"`
+	Exceptions []*time.Time `json:"exceptions" bson:"exceptions"`
+}
+
+type ReturnFacility struct {
+	Condition    *string      `json:"condition" bson:"condition"`
+	DamageStatus DamageStatus `json:"damageStatus" bson:"damageStatus"`
+}
+
+type SearchResult struct {
+	Type        SearchType         `json:"type" bson:"type"`
+	ID          primitive.ObjectID `json:"id" bson:"_id"`
+	Title       string             `json:"title" bson:"title"`
+	Field       string             `json:"field" bson:"field"`
+	Snippet     string             `json:"snippet" bson:"snippet"`
+	Score       float64            `json:"score" bson:"score"`
+	Event       *Event             `json:"event" bson:"event"`
+	Participant *Participant       `json:"participant" bson:"participant"`
+}
+
+type Session struct {
+	ID              primitive.ObjectID `json:"id" bson:"_id"`
+	CreatedAt       time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt       time.Time          `json:"updatedAt" bson:"updatedAt"`
+	EventID         primitive.ObjectID `json:"eventId" bson:"eventId"`
+	Title           string             `json:"title" bson:"title"`
+	Description     string             `json:"description" bson:"description"`
+	Track           string             `json:"track" bson:"track"`
+	StartDate       time.Time          `json:"startDate" bson:"startDate"`
+	EndDate         time.Time          `json:"endDate" bson:"endDate"`
+	Room            *Facility          `json:"room" bson:"room"`
+	Speakers        []*Speaker         `json:"speakers" bson:"speakers"`
+	Capacity        int                `json:"capacity" bson:"capacity"`
+	RegisteredCount int                `json:"registeredCount" bson:"registeredCount"`
+	Participants    []*Participant     `json:"participants" bson:"participants"`
+}
+
+type Speaker struct {
+	Name  string `json:"name" bson:"name"`
+	Email string `json:"email" bson:"email"`
+}
+
+type SpeakerInput struct {
+	Name  string  `json:"name" bson:"name"`
+	Email *string `json:"email" bson:"email"`
+}
+
+type StaffAssignment struct {
+	User  *User `json:"user" bson:"user"`
+	Tasks int   `json:"tasks" bson:"tasks"`
 }
 
 type Task struct {
@@ -185,6 +588,42 @@ type Task struct {
 	EndDate   time.Time          `json:"endDate" bson:"endDate"`
 }
 
+type TaskStatistic struct {
+	Total       int     `json:"total" bson:"total"`
+	Elapsed     int     `json:"elapsed" bson:"elapsed"`
+	InProgress  int     `json:"inProgress" bson:"inProgress"`
+	Upcoming    int     `json:"upcoming" bson:"upcoming"`
+	ElapsedRate float64 `json:"elapsedRate" bson:"elapsedRate"`
+}
+
+type TemplateFacility struct {
+	FacilityID   primitive.ObjectID `json:"facilityId" bson:"facilityId"`
+	BorrowOffset int                `json:"borrowOffset" bson:"borrowOffset"`
+	ReturnOffset int                `json:"returnOffset" bson:"returnOffset"`
+	Quantity     int                `json:"quantity" bson:"quantity"`
+}
+
+type TemplateTask struct {
+	Name        string             `json:"name" bson:"name"`
+	UserID      primitive.ObjectID `json:"userId" bson:"userId"`
+	Type        string             `json:"type" bson:"type"`
+	StartOffset int                `json:"startOffset" bson:"startOffset"`
+	EndOffset   int                `json:"endOffset" bson:"endOffset"`
+}
+
+type UpdateBudgetItem struct {
+	Category    string  `json:"category" bson:"category"`
+	Description *string `json:"description" bson:"description"`
+	Planned     int64   `json:"planned" bson:"planned"`
+	Currency    string  `json:"currency" bson:"currency"`
+}
+
+type UpdateEmailTemplate struct {
+	Subject string `json:"subject" bson:"subject"`
+	HTML    string `json:"html" bson:"html"`
+	Text    string `json:"text" bson:"text"`
+}
+
 type UpdateEvent struct {
 	Tags                  []string               `json:"tags" bson:"tags"`
 	Tasks                 []*NewTask             `json:"tasks" bson:"tasks"`
@@ -215,11 +654,18 @@ type UpdateEventType struct {
 	IsDeleted bool   `json:"isDeleted" bson:"isDeleted"`
 }
 
+type UpdateExpense struct {
+	Description string    `json:"description" bson:"description"`
+	Amount      int64     `json:"amount" bson:"amount"`
+	SpentAt     time.Time `json:"spentAt" bson:"spentAt"`
+	ReceiptURL  string    `json:"receiptUrl" bson:"receiptUrl"`
+}
+
 type UpdateFacility struct {
 	Name      string `json:"name" bson:"name"`
 	Code      string `json:"code" bson:"code"`
-	Type      string `json:"type" bson:"type"`
-	Status    bool   `json:"status" bson:"status"`
+	TypeID    string `json:"typeId" bson:"typeId"`
+	Quantity  *int   `json:"quantity" bson:"quantity"`
 	IsDeleted bool   `json:"isDeleted" bson:"isDeleted"`
 }
 
@@ -228,20 +674,38 @@ type UpdateFacilityHistory struct {
 	BorrowDate time.Time `json:"borrowDate" bson:"borrowDate"`
 	ReturnDate time.Time `json:"returnDate" bson:"returnDate"`
 	EventID    string    `json:"eventId" bson:"eventId"`
+	Quantity   *int      `json:"quantity" bson:"quantity"`
+}
+
+type UpdateFacilityType struct {
+	Name      string `json:"name" bson:"name"`
+	IsDeleted bool   `json:"isDeleted" bson:"isDeleted"`
 }
 
 type UpdateParticipant struct {
-	EventID              string    `json:"eventId" bson:"eventId"`
-	Email                string    `json:"email" bson:"email"`
-	Name                 string    `json:"name" bson:"name"`
-	Academic             string    `json:"academic" bson:"academic"`
-	School               string    `json:"school" bson:"school"`
-	Major                string    `json:"major" bson:"major"`
-	Phone                string    `json:"phone" bson:"phone"`
-	Dob                  time.Time `json:"dob" bson:"dob"`
-	ExpectedGraduateDate time.Time `json:"expectedGraduateDate" bson:"expectedGraduateDate"`
-	IsValid              bool      `json:"isValid" bson:"isValid"`
-	IsAttended           bool      `json:"isAttended" bson:"isAttended"`
+	EventID              string              `json:"eventId" bson:"eventId"`
+	Email                string              `json:"email" bson:"email"`
+	Name                 string              `json:"name" bson:"name"`
+	Academic             string              `json:"academic" bson:"academic"`
+	School               string              `json:"school" bson:"school"`
+	Major                string              `json:"major" bson:"major"`
+	Phone                string              `json:"phone" bson:"phone"`
+	Dob                  time.Time           `json:"dob" bson:"dob"`
+	ExpectedGraduateDate time.Time           `json:"expectedGraduateDate" bson:"expectedGraduateDate"`
+	IsValid              bool                `json:"isValid" bson:"isValid"`
+	IsAttended           bool                `json:"isAttended" bson:"isAttended"`
+	Answers              []*InputFieldAnswer `json:"answers" bson:"answers"`
+}
+
+type UpdateSession struct {
+	Title       string          `json:"title" bson:"title"`
+	Description *string         `json:"description" bson:"description"`
+	Track       *string         `json:"track" bson:"track"`
+	StartDate   time.Time       `json:"startDate" bson:"startDate"`
+	EndDate     time.Time       `json:"endDate" bson:"endDate"`
+	RoomID      *string         `json:"roomId" bson:"roomId"`
+	Speakers    []*SpeakerInput `json:"speakers" bson:"speakers"`
+	Capacity    *int            `json:"capacity" bson:"capacity"`
 }
 
 type UpdateTask struct {
@@ -267,3 +731,557 @@ type User struct {
 	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
 	UpdatedAt time.Time          `json:"updatedAt" bson:"updatedAt"`
 }
+
+type CustomizeFieldType string
+
+const (
+	CustomizeFieldTypeText         CustomizeFieldType = "TEXT"
+	CustomizeFieldTypeNumber       CustomizeFieldType = "NUMBER"
+	CustomizeFieldTypeEmail        CustomizeFieldType = "EMAIL"
+	CustomizeFieldTypePhone        CustomizeFieldType = "PHONE"
+	CustomizeFieldTypeDate         CustomizeFieldType = "DATE"
+	CustomizeFieldTypeSingleSelect CustomizeFieldType = "SINGLE_SELECT"
+	CustomizeFieldTypeMultiSelect  CustomizeFieldType = "MULTI_SELECT"
+	CustomizeFieldTypeCheckbox     CustomizeFieldType = "CHECKBOX"
+	CustomizeFieldTypeFile         CustomizeFieldType = "FILE"
+)
+
+var AllCustomizeFieldType = []CustomizeFieldType{
+	CustomizeFieldTypeText,
+	CustomizeFieldTypeNumber,
+	CustomizeFieldTypeEmail,
+	CustomizeFieldTypePhone,
+	CustomizeFieldTypeDate,
+	CustomizeFieldTypeSingleSelect,
+	CustomizeFieldTypeMultiSelect,
+	CustomizeFieldTypeCheckbox,
+	CustomizeFieldTypeFile,
+}
+
+func (e CustomizeFieldType) IsValid() bool {
+	switch e {
+	case CustomizeFieldTypeText, CustomizeFieldTypeNumber, CustomizeFieldTypeEmail, CustomizeFieldTypePhone, CustomizeFieldTypeDate, CustomizeFieldTypeSingleSelect, CustomizeFieldTypeMultiSelect, CustomizeFieldTypeCheckbox, CustomizeFieldTypeFile:
+		return true
+	}
+	return false
+}
+
+func (e CustomizeFieldType) String() string {
+	return string(e)
+}
+
+func (e *CustomizeFieldType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CustomizeFieldType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CustomizeFieldType", str)
+	}
+	return nil
+}
+
+func (e CustomizeFieldType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type DamageStatus string
+
+const (
+	DamageStatusNone  DamageStatus = "NONE"
+	DamageStatusMinor DamageStatus = "MINOR"
+	DamageStatusMajor DamageStatus = "MAJOR"
+	DamageStatusLost  DamageStatus = "LOST"
+)
+
+var AllDamageStatus = []DamageStatus{
+	DamageStatusNone,
+	DamageStatusMinor,
+	DamageStatusMajor,
+	DamageStatusLost,
+}
+
+func (e DamageStatus) IsValid() bool {
+	switch e {
+	case DamageStatusNone, DamageStatusMinor, DamageStatusMajor, DamageStatusLost:
+		return true
+	}
+	return false
+}
+
+func (e DamageStatus) String() string {
+	return string(e)
+}
+
+func (e *DamageStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DamageStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DamageStatus", str)
+	}
+	return nil
+}
+
+func (e DamageStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type DeleteTarget string
+
+const (
+	DeleteTargetUser            DeleteTarget = "USER"
+	DeleteTargetEvent           DeleteTarget = "EVENT"
+	DeleteTargetEventType       DeleteTarget = "EVENT_TYPE"
+	DeleteTargetFacility        DeleteTarget = "FACILITY"
+	DeleteTargetFacilityHistory DeleteTarget = "FACILITY_HISTORY"
+	DeleteTargetTask            DeleteTarget = "TASK"
+	DeleteTargetParticipant     DeleteTarget = "PARTICIPANT"
+	DeleteTargetBudgetItem      DeleteTarget = "BUDGET_ITEM"
+	DeleteTargetExpense         DeleteTarget = "EXPENSE"
+	DeleteTargetSession         DeleteTarget = "SESSION"
+	DeleteTargetEventTemplate   DeleteTarget = "EVENT_TEMPLATE"
+)
+
+var AllDeleteTarget = []DeleteTarget{
+	DeleteTargetUser,
+	DeleteTargetEvent,
+	DeleteTargetEventType,
+	DeleteTargetFacility,
+	DeleteTargetFacilityHistory,
+	DeleteTargetTask,
+	DeleteTargetParticipant,
+	DeleteTargetBudgetItem,
+	DeleteTargetExpense,
+	DeleteTargetSession,
+	DeleteTargetEventTemplate,
+}
+
+func (e DeleteTarget) IsValid() bool {
+	switch e {
+	case DeleteTargetUser, DeleteTargetEvent, DeleteTargetEventType, DeleteTargetFacility, DeleteTargetFacilityHistory, DeleteTargetTask, DeleteTargetParticipant, DeleteTargetBudgetItem, DeleteTargetExpense, DeleteTargetSession, DeleteTargetEventTemplate:
+		return true
+	}
+	return false
+}
+
+func (e DeleteTarget) String() string {
+	return string(e)
+}
+
+func (e *DeleteTarget) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DeleteTarget(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DeleteTarget", str)
+	}
+	return nil
+}
+
+func (e DeleteTarget) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type EmailTemplateName string
+
+const (
+	EmailTemplateNameInvitation    EmailTemplateName = "INVITATION"
+	EmailTemplateNameReminder      EmailTemplateName = "REMINDER"
+	EmailTemplateNamePromotion     EmailTemplateName = "PROMOTION"
+	EmailTemplateNameCancellation  EmailTemplateName = "CANCELLATION"
+	EmailTemplateNameApproval      EmailTemplateName = "APPROVAL"
+	EmailTemplateNamePasswordReset EmailTemplateName = "PASSWORD_RESET"
+	EmailTemplateNameTaskReminder  EmailTemplateName = "TASK_REMINDER"
+)
+
+var AllEmailTemplateName = []EmailTemplateName{
+	EmailTemplateNameInvitation,
+	EmailTemplateNameReminder,
+	EmailTemplateNamePromotion,
+	EmailTemplateNameCancellation,
+	EmailTemplateNameApproval,
+	EmailTemplateNamePasswordReset,
+	EmailTemplateNameTaskReminder,
+}
+
+func (e EmailTemplateName) IsValid() bool {
+	switch e {
+	case EmailTemplateNameInvitation, EmailTemplateNameReminder, EmailTemplateNamePromotion, EmailTemplateNameCancellation, EmailTemplateNameApproval, EmailTemplateNamePasswordReset, EmailTemplateNameTaskReminder:
+		return true
+	}
+	return false
+}
+
+func (e EmailTemplateName) String() string {
+	return string(e)
+}
+
+func (e *EmailTemplateName) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EmailTemplateName(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EmailTemplateName", str)
+	}
+	return nil
+}
+
+func (e EmailTemplateName) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ExpenseStatus string
+
+const (
+	ExpenseStatusPending  ExpenseStatus = "PENDING"
+	ExpenseStatusApproved ExpenseStatus = "APPROVED"
+	ExpenseStatusRejected ExpenseStatus = "REJECTED"
+)
+
+var AllExpenseStatus = []ExpenseStatus{
+	ExpenseStatusPending,
+	ExpenseStatusApproved,
+	ExpenseStatusRejected,
+}
+
+func (e ExpenseStatus) IsValid() bool {
+	switch e {
+	case ExpenseStatusPending, ExpenseStatusApproved, ExpenseStatusRejected:
+		return true
+	}
+	return false
+}
+
+func (e ExpenseStatus) String() string {
+	return string(e)
+}
+
+func (e *ExpenseStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ExpenseStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ExpenseStatus", str)
+	}
+	return nil
+}
+
+func (e ExpenseStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ImportMode string
+
+const (
+	ImportModeDryRun ImportMode = "DRY_RUN"
+	ImportModeCommit ImportMode = "COMMIT"
+)
+
+var AllImportMode = []ImportMode{
+	ImportModeDryRun,
+	ImportModeCommit,
+}
+
+func (e ImportMode) IsValid() bool {
+	switch e {
+	case ImportModeDryRun, ImportModeCommit:
+		return true
+	}
+	return false
+}
+
+func (e ImportMode) String() string {
+	return string(e)
+}
+
+func (e *ImportMode) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImportMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImportMode", str)
+	}
+	return nil
+}
+
+func (e ImportMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type MailLanguage string
+
+const (
+	MailLanguageEn MailLanguage = "en"
+	MailLanguageVi MailLanguage = "vi"
+)
+
+var AllMailLanguage = []MailLanguage{
+	MailLanguageEn,
+	MailLanguageVi,
+}
+
+func (e MailLanguage) IsValid() bool {
+	switch e {
+	case MailLanguageEn, MailLanguageVi:
+		return true
+	}
+	return false
+}
+
+func (e MailLanguage) String() string {
+	return string(e)
+}
+
+func (e *MailLanguage) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MailLanguage(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MailLanguage", str)
+	}
+	return nil
+}
+
+func (e MailLanguage) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type OutboxKind string
+
+const (
+	OutboxKindInvitation   OutboxKind = "INVITATION"
+	OutboxKindPromotion    OutboxKind = "PROMOTION"
+	OutboxKindReminder     OutboxKind = "REMINDER"
+	OutboxKindTaskReminder OutboxKind = "TASK_REMINDER"
+	OutboxKindCancellation OutboxKind = "CANCELLATION"
+	OutboxKindApproval     OutboxKind = "APPROVAL"
+)
+
+var AllOutboxKind = []OutboxKind{
+	OutboxKindInvitation,
+	OutboxKindPromotion,
+	OutboxKindReminder,
+	OutboxKindTaskReminder,
+	OutboxKindCancellation,
+	OutboxKindApproval,
+}
+
+func (e OutboxKind) IsValid() bool {
+	switch e {
+	case OutboxKindInvitation, OutboxKindPromotion, OutboxKindReminder, OutboxKindTaskReminder, OutboxKindCancellation, OutboxKindApproval:
+		return true
+	}
+	return false
+}
+
+func (e OutboxKind) String() string {
+	return string(e)
+}
+
+func (e *OutboxKind) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OutboxKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OutboxKind", str)
+	}
+	return nil
+}
+
+func (e OutboxKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type OutboxStatus string
+
+const (
+	OutboxStatusQueued OutboxStatus = "QUEUED"
+	OutboxStatusSent   OutboxStatus = "SENT"
+	OutboxStatusFailed OutboxStatus = "FAILED"
+	OutboxStatusDead   OutboxStatus = "DEAD"
+)
+
+var AllOutboxStatus = []OutboxStatus{
+	OutboxStatusQueued,
+	OutboxStatusSent,
+	OutboxStatusFailed,
+	OutboxStatusDead,
+}
+
+func (e OutboxStatus) IsValid() bool {
+	switch e {
+	case OutboxStatusQueued, OutboxStatusSent, OutboxStatusFailed, OutboxStatusDead:
+		return true
+	}
+	return false
+}
+
+func (e OutboxStatus) String() string {
+	return string(e)
+}
+
+func (e *OutboxStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OutboxStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OutboxStatus", str)
+	}
+	return nil
+}
+
+func (e OutboxStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ParticipantStatus string
+
+const (
+	ParticipantStatusRegistered ParticipantStatus = "REGISTERED"
+	ParticipantStatusWaitlisted ParticipantStatus = "WAITLISTED"
+)
+
+var AllParticipantStatus = []ParticipantStatus{
+	ParticipantStatusRegistered,
+	ParticipantStatusWaitlisted,
+}
+
+func (e ParticipantStatus) IsValid() bool {
+	switch e {
+	case ParticipantStatusRegistered, ParticipantStatusWaitlisted:
+		return true
+	}
+	return false
+}
+
+func (e ParticipantStatus) String() string {
+	return string(e)
+}
+
+func (e *ParticipantStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ParticipantStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ParticipantStatus", str)
+	}
+	return nil
+}
+
+func (e ParticipantStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type RecurrenceScope string
+
+const (
+	RecurrenceScopeThis             RecurrenceScope = "THIS"
+	RecurrenceScopeThisAndFollowing RecurrenceScope = "THIS_AND_FOLLOWING"
+	RecurrenceScopeAll              RecurrenceScope = "ALL"
+)
+
+var AllRecurrenceScope = []RecurrenceScope{
+	RecurrenceScopeThis,
+	RecurrenceScopeThisAndFollowing,
+	RecurrenceScopeAll,
+}
+
+func (e RecurrenceScope) IsValid() bool {
+	switch e {
+	case RecurrenceScopeThis, RecurrenceScopeThisAndFollowing, RecurrenceScopeAll:
+		return true
+	}
+	return false
+}
+
+func (e RecurrenceScope) String() string {
+	return string(e)
+}
+
+func (e *RecurrenceScope) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RecurrenceScope(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RecurrenceScope", str)
+	}
+	return nil
+}
+
+func (e RecurrenceScope) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SearchType string
+
+const (
+	SearchTypeEvent       SearchType = "EVENT"
+	SearchTypeParticipant SearchType = "PARTICIPANT"
+)
+
+var AllSearchType = []SearchType{
+	SearchTypeEvent,
+	SearchTypeParticipant,
+}
+
+func (e SearchType) IsValid() bool {
+	switch e {
+	case SearchTypeEvent, SearchTypeParticipant:
+		return true
+	}
+	return false
+}
+
+func (e SearchType) String() string {
+	return string(e)
+}
+
+func (e *SearchType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SearchType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SearchType", str)
+	}
+	return nil
+}
+
+func (e SearchType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
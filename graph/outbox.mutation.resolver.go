@@ -0,0 +1,23 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/services"
+	"github.com/khanhvtn/netevent-go/utilities"
+)
+
+func (r *mutationResolver) RetryOutboxMessage(ctx context.Context, id string) (*model.OutboxMessage, error) {
+	service := r.di.Container.Get(services.OutboxServiceName).(*services.OutboxService)
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	message, err := service.Retry(*objectId)
+	if err != nil {
+		return nil, err
+	}
+	return r.mapOutboxMessage(message), nil
+}
@@ -0,0 +1,26 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/services"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (r *queryResolver) OutboxMessages(ctx context.Context, status *model.OutboxStatus) ([]*model.OutboxMessage, error) {
+	service := r.di.Container.Get(services.OutboxServiceName).(*services.OutboxService)
+	condition := bson.M{}
+	if status != nil {
+		condition["status"] = status.String()
+	}
+	messages, err := service.GetAll(condition)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*model.OutboxMessage, 0)
+	for _, message := range messages {
+		results = append(results, r.mapOutboxMessage(message))
+	}
+	return results, nil
+}
@@ -3,6 +3,8 @@ package graph
 import (
 	"context"
 
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/gin-gonic/gin"
 	"github.com/khanhvtn/netevent-go/graph/model"
 	"github.com/khanhvtn/netevent-go/models"
 	"github.com/khanhvtn/netevent-go/services"
@@ -12,25 +14,15 @@ import (
 
 func (r *mutationResolver) CreateParticipant(ctx context.Context, input model.NewParticipant) (*model.Participant, error) {
 	participantService := r.di.Container.Get(services.ParticipantServiceName).(*services.ParticipantService)
-	eventService := r.di.Container.Get(services.EventServiceName).(*services.EventService)
 	//check input
 	if err := participantService.ValidateNewParticipant(input); err != nil {
 		return nil, err
 	}
+	//the invitation is sent by the outbox worker
 	newParticipant, err := participantService.Create(input)
 	if err != nil {
 		return nil, err
 	}
-
-	event, err := eventService.GetOne(bson.M{"_id": newParticipant.Event})
-	if err != nil {
-		return nil, err
-	}
-
-	//send invitation to particpant
-	if err := utilities.SendMail(event.Name, newParticipant.ID.Hex(), event.ID.Hex(), []*models.Participant{newParticipant}); err != nil {
-		return nil, err
-	}
 	results, err := r.mapParticipant(newParticipant)
 	if err != nil {
 		return nil, err
@@ -75,3 +67,70 @@ func (r *mutationResolver) DeleteParticipant(ctx context.Context, id string) (*m
 	}
 	return results, nil
 }
+func (r *mutationResolver) CheckIn(ctx context.Context, qrPayload string, device *string) (*model.Participant, error) {
+	service := r.di.Container.Get(services.ParticipantServiceName).(*services.ParticipantService)
+	staff, err := r.currentUserWithRole(ctx, models.UserRoleStaff)
+	if err != nil {
+		return nil, err
+	}
+	ginContext := ctx.Value("gincontext").(*gin.Context)
+	scanDevice := ginContext.Request.UserAgent()
+	if device != nil {
+		scanDevice = *device
+	}
+	checkedIn, err := service.CheckIn(qrPayload, staff.ID, scanDevice)
+	if err != nil {
+		return nil, err
+	}
+	results, err := r.mapParticipant(checkedIn)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+func (r *mutationResolver) SendInvitations(ctx context.Context, eventID string) (int, error) {
+	service := r.di.Container.Get(services.ParticipantServiceName).(*services.ParticipantService)
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(eventID)
+	if err != nil {
+		return 0, err
+	}
+	return service.SendInvitations(*objectId)
+}
+
+func (r *mutationResolver) ImportParticipants(ctx context.Context, eventID string, file graphql.Upload, mode model.ImportMode, sendInvitations *bool) (*model.ParticipantImport, error) {
+	service := r.di.Container.Get(services.ParticipantServiceName).(*services.ParticipantService)
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(eventID)
+	if err != nil {
+		return nil, err
+	}
+	participantImport, err := service.Import(*objectId, file.File, mode.String(), sendInvitations != nil && *sendInvitations)
+	if err != nil {
+		return nil, err
+	}
+	graphModelImported := make([]*model.Participant, 0)
+	for _, participant := range participantImport.Imported {
+		graphModelParticipant, err := r.mapParticipant(participant)
+		if err != nil {
+			return nil, err
+		}
+		graphModelImported = append(graphModelImported, graphModelParticipant)
+	}
+	graphModelErrors := make([]*model.ImportRowError, 0)
+	for _, rowError := range participantImport.Errors {
+		graphModelErrors = append(graphModelErrors, &model.ImportRowError{
+			Row:      rowError.Row,
+			Email:    rowError.Email,
+			Messages: rowError.Messages,
+		})
+	}
+	return &model.ParticipantImport{
+		Mode:           model.ImportMode(participantImport.Mode),
+		Total:          participantImport.Total,
+		Valid:          participantImport.Valid,
+		Imported:       graphModelImported,
+		Errors:         graphModelErrors,
+		IgnoredColumns: participantImport.IgnoredColumns,
+	}, nil
+}
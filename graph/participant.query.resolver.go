@@ -2,6 +2,7 @@ package graph
 
 import (
 	"context"
+	"sort"
 
 	"github.com/khanhvtn/netevent-go/graph/model"
 	"github.com/khanhvtn/netevent-go/services"
@@ -26,6 +27,19 @@ func (r *participantResolver) Event(ctx context.Context, obj *model.Participant)
 	}
 	return results, nil
 }
+func (r *participantResolver) Sessions(ctx context.Context, obj *model.Participant) ([]*model.Session, error) {
+	service := r.di.Container.Get(services.SessionServiceName).(*services.SessionService)
+	sessions, err := service.GetAll(bson.M{"attendees": obj.ID})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].StartDate.Before(sessions[j].StartDate) })
+	results := make([]*model.Session, 0)
+	for _, session := range sessions {
+		results = append(results, r.mapSession(session))
+	}
+	return results, nil
+}
 
 func (r *queryResolver) Participants(ctx context.Context) ([]*model.Participant, error) {
 	service := r.di.Container.Get(services.ParticipantServiceName).(*services.ParticipantService)
@@ -62,3 +76,20 @@ func (r *queryResolver) Participant(ctx context.Context, id string) (*model.Part
 	}
 	return result, nil
 }
+
+func (r *queryResolver) ParticipantHistory(ctx context.Context, email string) ([]*model.Participant, error) {
+	service := r.di.Container.Get(services.ParticipantServiceName).(*services.ParticipantService)
+	participants, err := service.GetHistory(email)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*model.Participant, 0)
+	for _, participant := range participants {
+		mappedParticipant, err := r.mapParticipant(participant)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, mappedParticipant)
+	}
+	return results, nil
+}
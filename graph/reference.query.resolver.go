@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"context"
+	"errors"
+
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/models"
+	"github.com/khanhvtn/netevent-go/services"
+	"github.com/khanhvtn/netevent-go/utilities"
+)
+
+/* deleteTargetCollections: collection behind every target that can be previewed */
+var deleteTargetCollections = map[model.DeleteTarget]string{
+	model.DeleteTargetUser:            models.CollectionUserName,
+	model.DeleteTargetEvent:           models.CollectionEventName,
+	model.DeleteTargetEventType:       models.CollectionEventTypeName,
+	model.DeleteTargetFacility:        models.CollectionFacilityName,
+	model.DeleteTargetFacilityHistory: models.CollectionFacilityHistoryName,
+	model.DeleteTargetTask:            models.CollectionTaskName,
+	model.DeleteTargetParticipant:     models.CollectionParticipantName,
+	model.DeleteTargetBudgetItem:      models.CollectionBudgetItemName,
+	model.DeleteTargetExpense:         models.CollectionExpenseName,
+	model.DeleteTargetSession:         models.CollectionSessionName,
+	model.DeleteTargetEventTemplate:   models.CollectionEventTemplateName,
+}
+
+func (r *queryResolver) DeletePreview(ctx context.Context, target model.DeleteTarget, id string) (*model.DeletePreview, error) {
+	service := r.di.Container.Get(services.ReferenceServiceName).(*services.ReferenceService)
+	colName, ok := deleteTargetCollections[target]
+	if !ok {
+		return nil, errors.New("invalid delete target")
+	}
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	report, err := service.Plan(colName, *objectId)
+	if err != nil {
+		return nil, err
+	}
+	result, err := r.mapDeleteReport(report)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
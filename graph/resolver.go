@@ -1,11 +1,17 @@
 package graph
 
 import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
 	"github.com/khanhvtn/netevent-go/graph/generated"
 	"github.com/khanhvtn/netevent-go/graph/model"
 	"github.com/khanhvtn/netevent-go/models"
 	"github.com/khanhvtn/netevent-go/services"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // This file will not be regenerated automatically.
@@ -25,9 +31,13 @@ func Init(di *services.DI) *Resolver {
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
 type eventResolver struct{ *Resolver }
+type facilityResolver struct{ *Resolver }
 type facilityHistoryResolver struct{ *Resolver }
 type participantResolver struct{ *Resolver }
 type taskResolver struct{ *Resolver }
+type budgetItemResolver struct{ *Resolver }
+type sessionResolver struct{ *Resolver }
+type eventSeriesResolver struct{ *Resolver }
 
 func (r *Resolver) Mutation() generated.MutationResolver {
 	return &mutationResolver{r}
@@ -40,6 +50,9 @@ func (r *Resolver) Query() generated.QueryResolver {
 // Event returns generated.EventResolver implementation.
 func (r *Resolver) Event() generated.EventResolver { return &eventResolver{r} }
 
+// Facility returns generated.FacilityResolver implementation.
+func (r *Resolver) Facility() generated.FacilityResolver { return &facilityResolver{r} }
+
 // FacilityHistory returns generated.FacilityHistoryResolver implementation.
 func (r *Resolver) FacilityHistory() generated.FacilityHistoryResolver {
 	return &facilityHistoryResolver{r}
@@ -51,6 +64,40 @@ func (r *Resolver) Participant() generated.ParticipantResolver { return &partici
 // Task returns generated.TaskResolver implementation.
 func (r *Resolver) Task() generated.TaskResolver { return &taskResolver{r} }
 
+// BudgetItem returns generated.BudgetItemResolver implementation.
+func (r *Resolver) BudgetItem() generated.BudgetItemResolver { return &budgetItemResolver{r} }
+
+// Session returns generated.SessionResolver implementation.
+func (r *Resolver) Session() generated.SessionResolver { return &sessionResolver{r} }
+
+// EventSeries returns generated.EventSeriesResolver implementation.
+func (r *Resolver) EventSeries() generated.EventSeriesResolver { return &eventSeriesResolver{r} }
+
+/* currentUser: get the logged in user from the session cookie */
+func (r *Resolver) currentUser(ctx context.Context) (*models.User, error) {
+	service := r.di.Container.Get(services.UserServiceName).(*services.UserService)
+	//get gin context
+	ginContext := ctx.Value("gincontext").(*gin.Context)
+	encryptedCookie, err := ginContext.Cookie("netevent")
+	if err != nil {
+		return nil, errors.New("access denied")
+	}
+	return service.GetBySession(encryptedCookie)
+}
+
+/* currentUserWithRole: get the logged in user, refused when they hold none of the roles */
+func (r *Resolver) currentUserWithRole(ctx context.Context, roles ...string) (*models.User, error) {
+	user, err := r.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	service := r.di.Container.Get(services.UserServiceName).(*services.UserService)
+	if err := service.RequireRole(user, roles...); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
 func (r *Resolver) mapUser(m *models.User) (*model.User, error) {
 	return &model.User{
 		ID:        m.ID,
@@ -64,37 +111,66 @@ func (r *Resolver) mapUser(m *models.User) (*model.User, error) {
 func (r *Resolver) mapEvent(m *models.Event) (*model.Event, error) {
 	var customizeFields []*model.CustomizeField
 	for _, value := range m.CustomizeFields {
-		customizeFields = append(customizeFields, &model.CustomizeField{
-			Name:     value.Name,
-			Type:     value.Type,
-			Value:    value.Values,
-			Required: value.Required,
+		customizeFields = append(customizeFields, r.mapCustomizeField(value))
+	}
+	customizeFieldVersions := make([]*model.CustomizeFieldVersion, 0)
+	for _, version := range m.CustomizeFieldVersions {
+		fields := make([]*model.CustomizeField, 0)
+		for _, value := range version.Fields {
+			fields = append(fields, r.mapCustomizeField(value))
+		}
+		customizeFieldVersions = append(customizeFieldVersions, &model.CustomizeFieldVersion{
+			Version:   version.Version,
+			CreatedAt: version.CreatedAt,
+			Fields:    fields,
 		})
 	}
 	return &model.Event{
-		ID:                    m.ID,
-		CreatedAt:             m.CreatedAt,
-		UpdatedAt:             m.UpdatedAt,
-		Tags:                  m.Tags,
-		IsApproved:            m.IsApproved,
-		IsFinished:            m.IsFinished,
-		Name:                  m.Name,
-		Language:              m.Language,
-		Mode:                  m.Mode,
-		Location:              m.Location,
-		Accommodation:         m.Accommodation,
-		RegistrationCloseDate: m.RegistrationCloseDate,
-		StartDate:             m.StartDate,
-		EndDate:               m.EndDate,
-		MaxParticipants:       m.MaxParticipants,
-		Description:           m.Description,
-		Budget:                m.Budget,
-		Image:                 m.Image,
-		IsDeleted:             m.IsDeleted,
-		CustomizeFields:       customizeFields,
+		ID:                     m.ID,
+		CreatedAt:              m.CreatedAt,
+		UpdatedAt:              m.UpdatedAt,
+		Tags:                   m.Tags,
+		IsApproved:             m.IsApproved,
+		ApprovedAt:             m.ApprovedAt,
+		IsFinished:             m.IsFinished,
+		Name:                   m.Name,
+		Language:               m.Language,
+		Mode:                   m.Mode,
+		Location:               m.Location,
+		Accommodation:          m.Accommodation,
+		RegistrationCloseDate:  m.RegistrationCloseDate,
+		StartDate:              m.StartDate,
+		EndDate:                m.EndDate,
+		MaxParticipants:        m.MaxParticipants,
+		RegisteredCount:        m.RegisteredCount,
+		Description:            m.Description,
+		Budget:                 m.Budget,
+		Image:                  m.Image,
+		IsDeleted:              m.IsDeleted,
+		CustomizeFields:        customizeFields,
+		CustomizeFieldsVersion: m.CustomizeFieldsVersion,
+		CustomizeFieldVersions: customizeFieldVersions,
+		OccurrenceStart:        m.OccurrenceStart,
 	}, nil
 }
 
+func (r *Resolver) mapCustomizeField(m *models.CustomizeField) *model.CustomizeField {
+	var pattern *string
+	if m.Pattern != "" {
+		pattern = &m.Pattern
+	}
+	return &model.CustomizeField{
+		Name:      m.Name,
+		Type:      model.CustomizeFieldType(m.FieldType()),
+		Value:     m.Values,
+		Required:  m.Required,
+		Order:     m.Order,
+		Min:       m.Min,
+		Max:       m.Max,
+		Pattern:   pattern,
+		MaxLength: m.MaxLength,
+	}
+}
 func (r *Resolver) mapEventType(m *models.EventType) (*model.EventType, error) {
 	return &model.EventType{
 		ID:        m.ID,
@@ -112,7 +188,16 @@ func (r *Resolver) mapFacility(m *models.Facility) (*model.Facility, error) {
 		Name:      m.Name,
 		Status:    m.Status,
 		Code:      m.Code,
-		Type:      m.Type,
+		Quantity:  m.Stock(),
+		IsDeleted: m.IsDeleted,
+	}, nil
+}
+func (r *Resolver) mapFacilityType(m *models.FacilityType) (*model.FacilityType, error) {
+	return &model.FacilityType{
+		ID:        m.ID,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+		Name:      m.Name,
 		IsDeleted: m.IsDeleted,
 	}, nil
 }
@@ -135,6 +220,14 @@ func (r *Resolver) mapFacilityHistory(m *models.FacilityHistory) (*model.Facilit
 	if err != nil {
 		return nil, err
 	}
+	graphModelCheckedOut, err := r.mapFacilityMovement(m.CheckedOut)
+	if err != nil {
+		return nil, err
+	}
+	graphModelReturned, err := r.mapFacilityMovement(m.Returned)
+	if err != nil {
+		return nil, err
+	}
 	return &model.FacilityHistory{
 		ID:         m.ID,
 		CreatedAt:  m.CreatedAt,
@@ -143,6 +236,30 @@ func (r *Resolver) mapFacilityHistory(m *models.FacilityHistory) (*model.Facilit
 		ReturnDate: m.ReturnDate,
 		Event:      graphModelEvent,
 		Facility:   graphModelFacility,
+		Quantity:   m.Units(),
+		CheckedOut: graphModelCheckedOut,
+		Returned:   graphModelReturned,
+		IsLate:     m.IsLate(time.Now()),
+	}, nil
+}
+func (r *Resolver) mapFacilityMovement(m *models.FacilityMovement) (*model.FacilityMovement, error) {
+	if m == nil {
+		return nil, nil
+	}
+	userService := r.di.Container.Get(services.UserServiceName).(*services.UserService)
+	staff, err := userService.GetOne(bson.M{"_id": m.Staff})
+	if err != nil {
+		return nil, err
+	}
+	graphModelStaff, err := r.mapUser(staff)
+	if err != nil {
+		return nil, err
+	}
+	return &model.FacilityMovement{
+		At:           m.At,
+		Staff:        graphModelStaff,
+		Condition:    m.Condition,
+		DamageStatus: model.DamageStatus(m.DamageStatus),
 	}, nil
 }
 func (r *Resolver) mapParticipant(m *models.Participant) (*model.Participant, error) {
@@ -156,21 +273,397 @@ func (r *Resolver) mapParticipant(m *models.Participant) (*model.Participant, er
 	if err != nil {
 		return nil, err
 	}
+	graphModelCheckIn, err := r.mapCheckIn(m.CheckIn)
+	if err != nil {
+		return nil, err
+	}
+	answers := make([]*model.FieldAnswer, 0)
+	for _, answer := range m.Answers {
+		answers = append(answers, &model.FieldAnswer{
+			Name:   answer.Name,
+			Values: answer.Values,
+		})
+	}
 	return &model.Participant{
-		ID:                   m.ID,
-		CreatedAt:            m.CreatedAt,
-		UpdatedAt:            m.UpdatedAt,
-		IsValid:              m.IsValid,
-		IsAttended:           m.IsAttended,
-		Email:                m.Email,
-		Name:                 m.Name,
-		Academic:             m.Academic,
-		School:               m.School,
-		Major:                m.Major,
-		Phone:                m.Phone,
-		Dob:                  m.DOB,
-		ExpectedGraduateDate: m.ExpectedGraduateDate,
-		Event:                graphModelEvent,
+		ID:                     m.ID,
+		CreatedAt:              m.CreatedAt,
+		UpdatedAt:              m.UpdatedAt,
+		IsValid:                m.IsValid,
+		IsAttended:             m.IsAttended,
+		Status:                 model.ParticipantStatus(m.RegistrationStatus()),
+		Email:                  m.Email,
+		Name:                   m.Name,
+		Academic:               m.Academic,
+		School:                 m.School,
+		Major:                  m.Major,
+		Phone:                  m.Phone,
+		Dob:                    m.DOB,
+		ExpectedGraduateDate:   m.ExpectedGraduateDate,
+		CheckIn:                graphModelCheckIn,
+		Answers:                answers,
+		CustomizeFieldsVersion: m.CustomizeFieldsVersion,
+		Event:                  graphModelEvent,
+	}, nil
+}
+func (r *Resolver) mapCheckIn(m *models.CheckIn) (*model.CheckIn, error) {
+	if m == nil {
+		return nil, nil
+	}
+	userService := r.di.Container.Get(services.UserServiceName).(*services.UserService)
+	staff, err := userService.GetOne(bson.M{"_id": m.Staff})
+	if err != nil {
+		return nil, err
+	}
+	graphModelStaff, err := r.mapUser(staff)
+	if err != nil {
+		return nil, err
+	}
+	return &model.CheckIn{
+		At:     m.At,
+		Staff:  graphModelStaff,
+		Device: m.Device,
+	}, nil
+}
+func (r *Resolver) mapOutboxMessage(m *models.OutboxMessage) *model.OutboxMessage {
+	//only the references of its kind are set on a message
+	optionalId := func(id primitive.ObjectID) *primitive.ObjectID {
+		if id.IsZero() {
+			return nil
+		}
+		return &id
+	}
+	return &model.OutboxMessage{
+		ID:            m.ID,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
+		Kind:          model.OutboxKind(m.Kind),
+		ParticipantID: optionalId(m.Participant),
+		EventID:       optionalId(m.Event),
+		UserID:        optionalId(m.User),
+		TaskID:        optionalId(m.Task),
+		To:            m.To,
+		Status:        model.OutboxStatus(m.Status),
+		Attempts:      m.Attempts,
+		NextAttemptAt: m.NextAttemptAt,
+		LastError:     m.LastError,
+		SentAt:        m.SentAt,
+	}
+}
+func (r *Resolver) mapEmailTemplate(m *models.EmailTemplate) *model.EmailTemplate {
+	return &model.EmailTemplate{
+		ID:        m.ID,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+		Name:      model.EmailTemplateName(m.Name),
+		Language:  model.MailLanguage(m.Language),
+		Subject:   m.Subject,
+		HTML:      m.Html,
+		Text:      m.Text,
+	}
+}
+func (r *Resolver) mapEventStatistic(m *models.EventStatistic) (*model.EventStatistic, error) {
+	graphModelEvent, err := r.mapEvent(m.Event)
+	if err != nil {
+		return nil, err
+	}
+	graphModelRegistrations := make([]*model.DailyCount, 0)
+	for _, registration := range m.Participants.Registrations {
+		graphModelRegistrations = append(graphModelRegistrations, &model.DailyCount{
+			Date:       registration.Date,
+			Count:      registration.Count,
+			Cumulative: registration.Cumulative,
+		})
+	}
+	mapBreakdown := func(counts []*models.BreakdownCount) []*model.BreakdownCount {
+		graphModelCounts := make([]*model.BreakdownCount, 0)
+		for _, count := range counts {
+			graphModelCounts = append(graphModelCounts, &model.BreakdownCount{Value: count.Value, Count: count.Count})
+		}
+		return graphModelCounts
+	}
+	graphModelFacilities, err := r.mapFacilityUsages(m.Facilities)
+	if err != nil {
+		return nil, err
+	}
+	return &model.EventStatistic{
+		Event:             graphModelEvent,
+		Registrations:     graphModelRegistrations,
+		TotalParticipants: m.Participants.Counts.Total,
+		Registered:        m.Registered(),
+		Waitlisted:        m.Participants.Counts.Waitlisted,
+		Valid:             m.Participants.Counts.Valid,
+		Invalid:           m.Invalid(),
+		Attended:          m.Participants.Counts.Attended,
+		AttendanceRate:    m.AttendanceRate(),
+		CapacityFill:      m.CapacityFill(),
+		BySchool:          mapBreakdown(m.Participants.BySchool),
+		ByMajor:           mapBreakdown(m.Participants.ByMajor),
+		ByAcademic:        mapBreakdown(m.Participants.ByAcademic),
+		Tasks: &model.TaskStatistic{
+			Total:       m.Tasks.Total,
+			Elapsed:     m.Tasks.Elapsed,
+			InProgress:  m.Tasks.InProgress,
+			Upcoming:    m.Tasks.Total - m.Tasks.Elapsed - m.Tasks.InProgress,
+			ElapsedRate: m.TaskElapsedRate(),
+		},
+		Facilities: graphModelFacilities,
+	}, nil
+}
+func (r *Resolver) mapFacilityUsages(m []*models.FacilityUsage) ([]*model.FacilityUsage, error) {
+	facilityService := r.di.Container.Get(services.FacilityServiceName).(*services.FacilityService)
+	graphModelFacilities := make([]*model.FacilityUsage, 0)
+	for _, usage := range m {
+		facility, err := facilityService.GetOne(bson.M{"_id": usage.Facility})
+		if err != nil {
+			return nil, err
+		}
+		graphModelFacility, err := r.mapFacility(facility)
+		if err != nil {
+			return nil, err
+		}
+		graphModelFacilities = append(graphModelFacilities, &model.FacilityUsage{
+			Facility:   graphModelFacility,
+			Bookings:   usage.Bookings,
+			Quantity:   usage.Quantity,
+			CheckedOut: usage.CheckedOut,
+			Returned:   usage.Returned,
+		})
+	}
+	return graphModelFacilities, nil
+}
+func (r *Resolver) mapDashboard(m *models.Dashboard) (*model.Dashboard, error) {
+	eventTypeService := r.di.Container.Get(services.EventTypeServiceName).(*services.EventTypeService)
+	userService := r.di.Container.Get(services.UserServiceName).(*services.UserService)
+	graphModelEventsPerType := make([]*model.EventTypeMonthCount, 0)
+	for _, count := range m.EventsPerType {
+		eventType, err := eventTypeService.GetOne(bson.M{"_id": count.EventType})
+		if err != nil {
+			return nil, err
+		}
+		graphModelEventType, err := r.mapEventType(eventType)
+		if err != nil {
+			return nil, err
+		}
+		graphModelEventsPerType = append(graphModelEventsPerType, &model.EventTypeMonthCount{
+			EventType: graphModelEventType,
+			Month:     count.Month,
+			Count:     count.Count,
+		})
+	}
+	graphModelBudgets := make([]*model.DashboardBudget, 0)
+	for _, budget := range m.Budgets {
+		graphModelBudgets = append(graphModelBudgets, &model.DashboardBudget{
+			Currency: budget.Currency,
+			Total:    budget.Total,
+			Events:   budget.Events,
+			Average:  budget.Average(),
+		})
+	}
+	graphModelAttendance := make([]*model.AttendanceMonth, 0)
+	for _, attendance := range m.Attendance {
+		graphModelAttendance = append(graphModelAttendance, &model.AttendanceMonth{
+			Month:      attendance.Month,
+			Registered: attendance.Registered,
+			Attended:   attendance.Attended,
+			Rate:       attendance.Rate(),
+		})
+	}
+	graphModelFacilities, err := r.mapFacilityUsages(m.BusiestFacilities)
+	if err != nil {
+		return nil, err
+	}
+	graphModelStaff := make([]*model.StaffAssignment, 0)
+	for _, assignment := range m.TopStaff {
+		user, err := userService.GetOne(bson.M{"_id": assignment.User})
+		if err != nil {
+			return nil, err
+		}
+		graphModelUser, err := r.mapUser(user)
+		if err != nil {
+			return nil, err
+		}
+		graphModelStaff = append(graphModelStaff, &model.StaffAssignment{User: graphModelUser, Tasks: assignment.Tasks})
+	}
+	return &model.Dashboard{
+		From:          m.From,
+		To:            m.To,
+		Events:        m.Events.Count,
+		Budgets:       graphModelBudgets,
+		EventsPerType: graphModelEventsPerType,
+		ApprovalTurnaround: &model.ApprovalTurnaround{
+			Approved:     m.ApprovalTurnaround.Approved,
+			AverageHours: m.ApprovalTurnaround.AverageHours,
+			MinHours:     m.ApprovalTurnaround.MinHours,
+			MaxHours:     m.ApprovalTurnaround.MaxHours,
+		},
+		Attendance:        graphModelAttendance,
+		BusiestFacilities: graphModelFacilities,
+		TopStaff:          graphModelStaff,
+	}, nil
+}
+func (r *Resolver) mapEventSeries(m *models.EventSeries) *model.EventSeries {
+	exceptions := make([]*time.Time, 0)
+	for i := range m.Exceptions {
+		exceptions = append(exceptions, &m.Exceptions[i])
+	}
+	return &model.EventSeries{
+		ID:         m.ID,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+		Rule:       m.Rule,
+		Start:      m.Start,
+		Exceptions: exceptions,
+	}
+}
+func (r *Resolver) mapEventTemplate(m *models.EventTemplate) *model.EventTemplate {
+	customizeFields := make([]*model.CustomizeField, 0)
+	for _, value := range m.CustomizeFields {
+		customizeFields = append(customizeFields, r.mapCustomizeField(value))
+	}
+	tasks := make([]*model.TemplateTask, 0)
+	for _, task := range m.Tasks {
+		tasks = append(tasks, &model.TemplateTask{
+			Name:        task.Name,
+			UserID:      task.User,
+			Type:        task.Type,
+			StartOffset: task.StartOffset,
+			EndOffset:   task.EndOffset,
+		})
+	}
+	facilities := make([]*model.TemplateFacility, 0)
+	for _, facility := range m.Facilities {
+		facilities = append(facilities, &model.TemplateFacility{
+			FacilityID:   facility.Facility,
+			BorrowOffset: facility.BorrowOffset,
+			ReturnOffset: facility.ReturnOffset,
+			Quantity:     facility.Quantity,
+		})
+	}
+	return &model.EventTemplate{
+		ID:                    m.ID,
+		CreatedAt:             m.CreatedAt,
+		UpdatedAt:             m.UpdatedAt,
+		Name:                  m.Name,
+		Tags:                  m.Tags,
+		Language:              m.Language,
+		EventTypeID:           m.EventType,
+		Mode:                  m.Mode,
+		Location:              m.Location,
+		Accommodation:         m.Accommodation,
+		MaxParticipants:       m.MaxParticipants,
+		Description:           m.Description,
+		OwnerID:               m.Owner,
+		Budget:                m.Budget,
+		Image:                 m.Image,
+		CustomizeFields:       customizeFields,
+		Duration:              m.Duration,
+		RegistrationCloseLead: m.RegistrationCloseLead,
+		Tasks:                 tasks,
+		Facilities:            facilities,
+	}
+}
+func (r *Resolver) mapSearchResult(m *models.SearchHit) (*model.SearchResult, error) {
+	result := &model.SearchResult{
+		Type:    model.SearchType(m.Type),
+		ID:      m.ID,
+		Title:   m.Title,
+		Field:   m.Field,
+		Snippet: m.Snippet,
+		Score:   m.Score,
+	}
+	var err error
+	if m.Event != nil {
+		if result.Event, err = r.mapEvent(m.Event); err != nil {
+			return nil, err
+		}
+	}
+	if m.Participant != nil {
+		if result.Participant, err = r.mapParticipant(m.Participant); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+func (r *Resolver) mapSession(m *models.Session) *model.Session {
+	speakers := make([]*model.Speaker, 0)
+	for _, speaker := range m.Speakers {
+		speakers = append(speakers, &model.Speaker{Name: speaker.Name, Email: speaker.Email})
+	}
+	return &model.Session{
+		ID:              m.ID,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+		EventID:         m.Event,
+		Title:           m.Title,
+		Description:     m.Description,
+		Track:           m.Track,
+		StartDate:       m.StartDate,
+		EndDate:         m.EndDate,
+		Speakers:        speakers,
+		Capacity:        m.Capacity,
+		RegisteredCount: len(m.Attendees),
+	}
+}
+func (r *Resolver) mapBudgetItem(m *models.BudgetItemReport) *model.BudgetItem {
+	return &model.BudgetItem{
+		ID:          m.Item.ID,
+		CreatedAt:   m.Item.CreatedAt,
+		UpdatedAt:   m.Item.UpdatedAt,
+		EventID:     m.Item.Event,
+		Category:    m.Item.Category,
+		Description: m.Item.Description,
+		Planned:     m.Variance.Planned,
+		Currency:    m.Item.Currency,
+		Spent:       m.Variance.Spent,
+		Pending:     m.Variance.Pending,
+		Variance:    m.Variance.Variance(),
+	}
+}
+func (r *Resolver) mapBudgetTotal(m *models.BudgetVariance) *model.BudgetTotal {
+	return &model.BudgetTotal{
+		Currency: m.Currency,
+		Planned:  m.Planned,
+		Spent:    m.Spent,
+		Pending:  m.Pending,
+		Variance: m.Variance(),
+	}
+}
+func (r *Resolver) mapExpense(m *models.Expense) (*model.Expense, error) {
+	userService := r.di.Container.Get(services.UserServiceName).(*services.UserService)
+	submittedBy, err := userService.GetOne(bson.M{"_id": m.SubmittedBy})
+	if err != nil {
+		return nil, err
+	}
+	graphModelSubmittedBy, err := r.mapUser(submittedBy)
+	if err != nil {
+		return nil, err
+	}
+	var graphModelReview *model.ExpenseReview
+	if m.Review != nil {
+		reviewer, err := userService.GetOne(bson.M{"_id": m.Review.Reviewer})
+		if err != nil {
+			return nil, err
+		}
+		graphModelReviewer, err := r.mapUser(reviewer)
+		if err != nil {
+			return nil, err
+		}
+		graphModelReview = &model.ExpenseReview{At: m.Review.At, Reviewer: graphModelReviewer, Note: m.Review.Note}
+	}
+	return &model.Expense{
+		ID:           m.ID,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+		EventID:      m.Event,
+		BudgetItemID: m.BudgetItem,
+		Description:  m.Description,
+		Amount:       m.Amount,
+		Currency:     m.Currency,
+		SpentAt:      m.SpentAt,
+		ReceiptURL:   m.ReceiptUrl,
+		Status:       model.ExpenseStatus(m.Status),
+		SubmittedBy:  graphModelSubmittedBy,
+		Review:       graphModelReview,
 	}, nil
 }
 func (r *Resolver) mapTask(m *models.Task) (*model.Task, error) {
@@ -204,3 +697,35 @@ func (r *Resolver) mapTask(m *models.Task) (*model.Task, error) {
 		User:      graphModelUser,
 	}, nil
 }
+func (r *Resolver) mapDeleteImpact(m *models.DeleteImpact) (*model.DeleteImpact, error) {
+	return &model.DeleteImpact{
+		Collection: m.Collection,
+		Field:      m.Field,
+		Action:     string(m.Action),
+		Count:      len(m.IDs),
+		Ids:        m.IDs,
+	}, nil
+}
+func (r *Resolver) mapDeleteReport(m *models.DeleteReport) (*model.DeletePreview, error) {
+	blockers := make([]*model.DeleteImpact, 0)
+	for _, v := range m.Blockers {
+		mappedImpact, err := r.mapDeleteImpact(v)
+		if err != nil {
+			return nil, err
+		}
+		blockers = append(blockers, mappedImpact)
+	}
+	impacts := make([]*model.DeleteImpact, 0)
+	for _, v := range m.Impacts {
+		mappedImpact, err := r.mapDeleteImpact(v)
+		if err != nil {
+			return nil, err
+		}
+		impacts = append(impacts, mappedImpact)
+	}
+	return &model.DeletePreview{
+		Allowed:  m.Allowed(),
+		Blockers: blockers,
+		Impacts:  impacts,
+	}, nil
+}
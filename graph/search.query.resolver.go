@@ -0,0 +1,37 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/services"
+)
+
+func (r *queryResolver) Search(ctx context.Context, query string, types []model.SearchType, limit *int) ([]*model.SearchResult, error) {
+	service := r.di.Container.Get(services.SearchServiceName).(*services.SearchService)
+	//results carry the names, emails and schools of participants
+	if _, err := r.currentUser(ctx); err != nil {
+		return nil, err
+	}
+	searchTypes := make([]string, 0)
+	for _, searchType := range types {
+		searchTypes = append(searchTypes, searchType.String())
+	}
+	resultLimit := services.SearchDefaultLimit
+	if limit != nil {
+		resultLimit = *limit
+	}
+	hits, err := service.Search(query, searchTypes, resultLimit)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*model.SearchResult, 0)
+	for _, hit := range hits {
+		result, err := r.mapSearchResult(hit)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
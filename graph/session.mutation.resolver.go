@@ -0,0 +1,87 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/services"
+	"github.com/khanhvtn/netevent-go/utilities"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (r *mutationResolver) CreateSession(ctx context.Context, input model.NewSession) (*model.Session, error) {
+	service := r.di.Container.Get(services.SessionServiceName).(*services.SessionService)
+	//check input
+	if err := service.ValidateNewSession(input); err != nil {
+		return nil, err
+	}
+	newSession, err := service.Create(input)
+	if err != nil {
+		return nil, err
+	}
+	return r.mapSession(newSession), nil
+}
+func (r *mutationResolver) UpdateSession(ctx context.Context, id string, input model.UpdateSession) (*model.Session, error) {
+	service := r.di.Container.Get(services.SessionServiceName).(*services.SessionService)
+	//check input
+	if err := service.ValidateUpdateSession(input); err != nil {
+		return nil, err
+	}
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	updatedSession, err := service.UpdateOne(*objectId, input)
+	if err != nil {
+		return nil, err
+	}
+	return r.mapSession(updatedSession), nil
+}
+func (r *mutationResolver) DeleteSession(ctx context.Context, id string) (*model.Session, error) {
+	service := r.di.Container.Get(services.SessionServiceName).(*services.SessionService)
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	deletedSession, err := service.DeleteOne(bson.M{"_id": objectId})
+	if err != nil {
+		return nil, err
+	}
+	return r.mapSession(deletedSession), nil
+}
+func (r *mutationResolver) RegisterForSession(ctx context.Context, sessionID string, participantID string) (*model.Session, error) {
+	service := r.di.Container.Get(services.SessionServiceName).(*services.SessionService)
+	//convert string ids to object ids
+	sessionObjectId, err := utilities.ConvertStringIdToObjectID(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	participantObjectId, err := utilities.ConvertStringIdToObjectID(participantID)
+	if err != nil {
+		return nil, err
+	}
+	session, err := service.Register(*sessionObjectId, *participantObjectId)
+	if err != nil {
+		return nil, err
+	}
+	return r.mapSession(session), nil
+}
+func (r *mutationResolver) UnregisterFromSession(ctx context.Context, sessionID string, participantID string) (*model.Session, error) {
+	service := r.di.Container.Get(services.SessionServiceName).(*services.SessionService)
+	//convert string ids to object ids
+	sessionObjectId, err := utilities.ConvertStringIdToObjectID(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	participantObjectId, err := utilities.ConvertStringIdToObjectID(participantID)
+	if err != nil {
+		return nil, err
+	}
+	session, err := service.Unregister(*sessionObjectId, *participantObjectId)
+	if err != nil {
+		return nil, err
+	}
+	return r.mapSession(session), nil
+}
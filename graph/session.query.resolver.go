@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/services"
+	"github.com/khanhvtn/netevent-go/utilities"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (r *sessionResolver) Room(ctx context.Context, obj *model.Session) (*model.Facility, error) {
+	sessionService := r.di.Container.Get(services.SessionServiceName).(*services.SessionService)
+	facilityService := r.di.Container.Get(services.FacilityServiceName).(*services.FacilityService)
+	session, err := sessionService.GetOne(bson.M{"_id": obj.ID})
+	if err != nil {
+		return nil, err
+	}
+	if session.Room == nil {
+		return nil, nil
+	}
+	facility, err := facilityService.GetOne(bson.M{"_id": *session.Room})
+	if err != nil {
+		return nil, err
+	}
+	return r.mapFacility(facility)
+}
+func (r *sessionResolver) Participants(ctx context.Context, obj *model.Session) ([]*model.Participant, error) {
+	sessionService := r.di.Container.Get(services.SessionServiceName).(*services.SessionService)
+	participantService := r.di.Container.Get(services.ParticipantServiceName).(*services.ParticipantService)
+	session, err := sessionService.GetOne(bson.M{"_id": obj.ID})
+	if err != nil {
+		return nil, err
+	}
+	participants, err := participantService.GetAll(bson.M{"_id": bson.M{"$in": session.Attendees}})
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*model.Participant, 0)
+	for _, participant := range participants {
+		mappedParticipant, err := r.mapParticipant(participant)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, mappedParticipant)
+	}
+	return results, nil
+}
+
+func (r *queryResolver) Session(ctx context.Context, id string) (*model.Session, error) {
+	service := r.di.Container.Get(services.SessionServiceName).(*services.SessionService)
+	//convert string id to object id
+	objectId, err := utilities.ConvertStringIdToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	session, err := service.GetOne(bson.M{"_id": objectId})
+	if err != nil {
+		return nil, err
+	}
+	return r.mapSession(session), nil
+}
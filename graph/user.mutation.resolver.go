@@ -2,6 +2,7 @@ package graph
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/gin-gonic/gin"
 	"github.com/khanhvtn/netevent-go/graph/model"
@@ -108,3 +109,16 @@ func (r *mutationResolver) Logout(ctx context.Context) (string, error) {
 	ginContext.SetCookie("netevent", "", 0, "/", "localhost", false, true)
 	return "Logout successful", nil
 }
+
+func (r *mutationResolver) ResetCalendarToken(ctx context.Context) (string, error) {
+	service := r.di.Container.Get(services.CalendarServiceName).(*services.CalendarService)
+	user, err := r.currentUser(ctx)
+	if err != nil {
+		return "", err
+	}
+	token, err := service.ResetCalendarToken(user.ID)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("/api/calendar/tasks/%s.ics", token), nil
+}
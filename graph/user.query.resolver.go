@@ -2,9 +2,7 @@ package graph
 
 import (
 	"context"
-	"errors"
 
-	"github.com/gin-gonic/gin"
 	"github.com/khanhvtn/netevent-go/graph/model"
 	"github.com/khanhvtn/netevent-go/services"
 	"github.com/khanhvtn/netevent-go/utilities"
@@ -29,24 +27,7 @@ func (r *queryResolver) Users(ctx context.Context) ([]*model.User, error) {
 }
 
 func (r *queryResolver) CheckLoginStatus(ctx context.Context) (*model.User, error) {
-	service := r.di.Container.Get(services.UserServiceName).(*services.UserService)
-	//get gin context
-	ginContext := ctx.Value("gincontext").(*gin.Context)
-	encryptedCookie, err := ginContext.Cookie("netevent")
-	if err != nil {
-		return nil, errors.New("access denied")
-	}
-	//decrypt cookie
-	id, err := utilities.Decrypted([]byte(encryptedCookie))
-	if err != nil {
-		return nil, err
-	}
-	objectId, err := utilities.ConvertStringIdToObjectID(string(id))
-	if err != nil {
-		return nil, err
-	}
-	//get user based specific id
-	user, err := service.GetOne(bson.M{"_id": objectId})
+	user, err := r.currentUser(ctx)
 	if err != nil {
 		return nil, err
 	}
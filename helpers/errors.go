@@ -31,3 +31,35 @@ func NewErrNotFound(msg string) *ErrNotFound {
 func (err *ErrNotFound) Error() string {
 	return err.msg
 }
+
+// ErrConflict is the error type that should be used
+// to indicate that the request conflicts with the current state of other resources.
+type ErrConflict struct {
+	msg string
+}
+
+// NewErrConflict is the ErrConflict constructor.
+func NewErrConflict(msg string) *ErrConflict {
+	return &ErrConflict{msg: msg}
+}
+
+// Error returns the error message.
+func (err *ErrConflict) Error() string {
+	return err.msg
+}
+
+// ErrForbidden is the error type that should be used
+// to indicate that the logged in user is not allowed to do the request.
+type ErrForbidden struct {
+	msg string
+}
+
+// NewErrForbidden is the ErrForbidden constructor.
+func NewErrForbidden(msg string) *ErrForbidden {
+	return &ErrForbidden{msg: msg}
+}
+
+// Error returns the error message.
+func (err *ErrForbidden) Error() string {
+	return err.msg
+}
@@ -2,7 +2,10 @@ package main
 
 import (
 	"log"
+	//calendar time zones must load on hosts without a zone database
+	_ "time/tzdata"
 
+	"github.com/joho/godotenv"
 	"github.com/khanhvtn/netevent-go/api"
 	"github.com/khanhvtn/netevent-go/database"
 	"github.com/khanhvtn/netevent-go/services"
@@ -15,12 +18,62 @@ func main() {
 		return
 	}
 
+	//load env file, the services read their config from it
+	if err := godotenv.Load(); err != nil {
+		log.Fatalln("Error loading .env file")
+	}
+
 	//Create services
 	di, err := services.New()
 	if err != nil {
 		log.Fatal(err.Error())
 		return
 	}
+	//Upgrade facilities stored with a free text type
+	facilityTypeService := di.Container.Get(services.FacilityTypeServiceName).(*services.FacilityTypeService)
+	if err := facilityTypeService.MigrateLegacyTypes(); err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+	//An email can register only once per event
+	participantService := di.Container.Get(services.ParticipantServiceName).(*services.ParticipantService)
+	if err := participantService.MigrateEmails(); err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+	participantRepository := di.Container.Get(services.ParticipantRepositoryName).(*services.ParticipantRepository)
+	if err := participantRepository.CreateIndexes(); err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+	//A mail template can be overridden only once per language
+	emailTemplateRepository := di.Container.Get(services.EmailTemplateRepositoryName).(*services.EmailTemplateRepository)
+	if err := emailTemplateRepository.CreateIndexes(); err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+	//Text indexes behind the search box
+	searchRepository := di.Container.Get(services.SearchRepositoryName).(*services.SearchRepository)
+	if err := searchRepository.CreateIndexes(); err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+	//Recount the taken seats of every event
+	if err := participantService.SyncRegisteredCounts(); err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+	//Send queued mails in the background
+	outboxService := di.Container.Get(services.OutboxServiceName).(*services.OutboxService)
+	outboxService.Start(3)
+	//Queue reminders before events and tasks start
+	reminderRepository := di.Container.Get(services.ReminderRepositoryName).(*services.ReminderRepository)
+	if err := reminderRepository.CreateIndexes(); err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+	reminderService := di.Container.Get(services.ReminderServiceName).(*services.ReminderService)
+	reminderService.Start()
 	//start API
 	api.Init(di)
 }
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var CollectionBudgetItemName = "budgetItems"
+var CollectionExpenseName = "expenses"
+
+/* Status of an expense */
+var (
+	ExpensePending  = "PENDING"
+	ExpenseApproved = "APPROVED"
+	ExpenseRejected = "REJECTED"
+)
+
+/* BudgetItem: the amount planned for one category of spending of an event, in minor units of its currency */
+type BudgetItem struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+	Event       primitive.ObjectID `bson:"event" json:"event"`
+	Category    string             `bson:"category" json:"category"`
+	Description string             `bson:"description" json:"description"`
+	Planned     int64              `bson:"planned" json:"planned"`
+	Currency    string             `bson:"currency" json:"currency"`
+}
+
+/* ExpenseReview: the approval or rejection of an expense over the threshold */
+type ExpenseReview struct {
+	At       time.Time          `bson:"at" json:"at"`
+	Reviewer primitive.ObjectID `bson:"reviewer" json:"reviewer"`
+	Note     string             `bson:"note" json:"note"`
+}
+
+/* Expense: money actually spent against a budget item, in minor units of the currency of the item */
+type Expense struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+	Event       primitive.ObjectID `bson:"event" json:"event"`
+	BudgetItem  primitive.ObjectID `bson:"budgetItem" json:"budgetItem"`
+	Description string             `bson:"description" json:"description"`
+	Amount      int64              `bson:"amount" json:"amount"`
+	Currency    string             `bson:"currency" json:"currency"`
+	SpentAt     time.Time          `bson:"spentAt" json:"spentAt"`
+	ReceiptUrl  string             `bson:"receiptUrl" json:"receiptUrl"`
+	Status      string             `bson:"status" json:"status"`
+	SubmittedBy primitive.ObjectID `bson:"submittedBy" json:"submittedBy"`
+	//set once an expense over the threshold has been reviewed
+	Review *ExpenseReview `bson:"review,omitempty" json:"review"`
+}
+
+/* ExpenseSum: the expenses of one budget item and status */
+type ExpenseSum struct {
+	BudgetItem primitive.ObjectID `bson:"budgetItem" json:"budgetItem"`
+	Status     string             `bson:"status" json:"status"`
+	Amount     int64              `bson:"amount" json:"amount"`
+}
+
+/* BudgetVariance: planned against spent, approved expenses count as spent */
+type BudgetVariance struct {
+	Currency string
+	Planned  int64
+	Spent    int64
+	Pending  int64
+}
+
+/* Variance: the amount left, negative when the budget is overspent */
+func (v *BudgetVariance) Variance() int64 {
+	return v.Planned - v.Spent
+}
+
+/* Add: count the expenses of a sum */
+func (v *BudgetVariance) Add(sum *ExpenseSum) {
+	switch sum.Status {
+	case ExpenseApproved:
+		v.Spent += sum.Amount
+	case ExpensePending:
+		v.Pending += sum.Amount
+	}
+}
+
+/* BudgetItemReport: a budget item with the expenses counted against it */
+type BudgetItemReport struct {
+	Item     *BudgetItem
+	Variance BudgetVariance
+}
+
+/* BudgetReport: the budget items of an event and the totals per currency */
+type BudgetReport struct {
+	Items  []*BudgetItemReport
+	Totals []*BudgetVariance
+}
@@ -0,0 +1,238 @@
+package models
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//CalendarContentType: the media type of RFC 5545 files
+var CalendarContentType = "text/calendar; charset=utf-8"
+
+//CalendarDomain: the right hand side of the UIDs, kept the same so clients update events instead of duplicating them
+var CalendarDomain = "netevent"
+
+func init() {
+	//mail attachments and downloads get their type from the extension
+	mime.AddExtensionType(".ics", CalendarContentType)
+}
+
+/* Calendar: an iCalendar object of events, with times written in one time zone */
+type Calendar struct {
+	Name     string
+	Location *time.Location
+	Events   []*CalendarEvent
+}
+
+/* CalendarEvent: one VEVENT */
+type CalendarEvent struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	Start        time.Time
+	End          time.Time
+	CreatedAt    time.Time
+	LastModified time.Time
+}
+
+func NewCalendar(name string, location *time.Location) *Calendar {
+	return &Calendar{
+		Name:     name,
+		Location: location,
+		Events:   make([]*CalendarEvent, 0),
+	}
+}
+
+/* CalendarUID: the UID of a record, the same on every export */
+func CalendarUID(kind string, id primitive.ObjectID) string {
+	return fmt.Sprintf("%s-%s@%s", kind, id.Hex(), CalendarDomain)
+}
+
+/* AddEvent: add an event of the organization */
+func (c *Calendar) AddEvent(event *Event) {
+	c.Events = append(c.Events, &CalendarEvent{
+		UID:          CalendarUID("event", event.ID),
+		Summary:      event.Name,
+		Description:  event.Description,
+		Location:     event.Location,
+		Start:        event.StartDate,
+		End:          event.EndDate,
+		CreatedAt:    event.CreatedAt,
+		LastModified: event.UpdatedAt,
+	})
+}
+
+/* AddTask: add a task, with the event it belongs to when it has one */
+func (c *Calendar) AddTask(task *Task, event *Event) {
+	calendarEvent := &CalendarEvent{
+		UID:          CalendarUID("task", task.ID),
+		Summary:      task.Name,
+		Description:  task.Type,
+		Start:        task.StartDate,
+		End:          task.EndDate,
+		CreatedAt:    task.CreatedAt,
+		LastModified: task.UpdatedAt,
+	}
+	if event != nil {
+		calendarEvent.Description = strings.TrimSpace(fmt.Sprintf("%s\n%s", event.Name, task.Type))
+		calendarEvent.Location = event.Location
+	}
+	c.Events = append(c.Events, calendarEvent)
+}
+
+/* ToBytes: build the RFC 5545 object, with the VTIMEZONE its times refer to */
+func (c *Calendar) ToBytes() []byte {
+	tzid := c.Location.String()
+	buf := bytes.NewBuffer(nil)
+	writeContentLine(buf, "BEGIN:VCALENDAR")
+	writeContentLine(buf, "VERSION:2.0")
+	writeContentLine(buf, "PRODID:-//NetEvent//NetEvent//EN")
+	writeContentLine(buf, "CALSCALE:GREGORIAN")
+	writeContentLine(buf, "METHOD:PUBLISH")
+	writeContentLine(buf, "X-WR-CALNAME:"+escapeText(c.Name))
+	writeContentLine(buf, "X-WR-TIMEZONE:"+tzid)
+	c.writeTimezone(buf)
+	for _, event := range c.Events {
+		writeContentLine(buf, "BEGIN:VEVENT")
+		writeContentLine(buf, "UID:"+event.UID)
+		writeContentLine(buf, "DTSTAMP:"+utcTime(event.LastModified))
+		writeContentLine(buf, "CREATED:"+utcTime(event.CreatedAt))
+		writeContentLine(buf, "LAST-MODIFIED:"+utcTime(event.LastModified))
+		writeContentLine(buf, fmt.Sprintf("DTSTART;TZID=%s:%s", tzid, localTime(event.Start.In(c.Location))))
+		//without DTEND the event ends when it starts
+		if event.End.After(event.Start) {
+			writeContentLine(buf, fmt.Sprintf("DTEND;TZID=%s:%s", tzid, localTime(event.End.In(c.Location))))
+		}
+		writeContentLine(buf, "SUMMARY:"+escapeText(event.Summary))
+		if event.Description != "" {
+			writeContentLine(buf, "DESCRIPTION:"+escapeText(event.Description))
+		}
+		if event.Location != "" {
+			writeContentLine(buf, "LOCATION:"+escapeText(event.Location))
+		}
+		writeContentLine(buf, "STATUS:CONFIRMED")
+		writeContentLine(buf, "END:VEVENT")
+	}
+	writeContentLine(buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+/* writeTimezone: a VTIMEZONE with every offset change of the years the events cover */
+func (c *Calendar) writeTimezone(buf *bytes.Buffer) {
+	first, last := time.Now().Year(), time.Now().Year()
+	for _, event := range c.Events {
+		if year := event.Start.In(c.Location).Year(); year < first {
+			first = year
+		}
+		if year := event.End.In(c.Location).Year(); year > last {
+			last = year
+		}
+	}
+	from := time.Date(first, time.January, 1, 0, 0, 0, 0, c.Location)
+	to := time.Date(last+1, time.January, 1, 0, 0, 0, 0, c.Location)
+
+	writeContentLine(buf, "BEGIN:VTIMEZONE")
+	writeContentLine(buf, "TZID:"+c.Location.String())
+	//the offset in effect when the window opens, then one onset per change
+	name, offset := from.Zone()
+	writeTimezoneOnset(buf, from, offset, offset, name, false)
+	for t := from; t.Before(to); {
+		next := t.Add(24 * time.Hour)
+		if _, nextOffset := next.Zone(); nextOffset != offset {
+			//find the second the offset changes
+			low, high := t, next
+			for high.Sub(low) > time.Second {
+				middle := low.Add(high.Sub(low) / 2)
+				if _, middleOffset := middle.Zone(); middleOffset == offset {
+					low = middle
+				} else {
+					high = middle
+				}
+			}
+			name, nextOffset = high.Zone()
+			//zones observing daylight saving move forward from their standard offset
+			writeTimezoneOnset(buf, high, offset, nextOffset, name, nextOffset > standardOffset(high))
+			offset = nextOffset
+		}
+		t = next
+	}
+	writeContentLine(buf, "END:VTIMEZONE")
+}
+
+/* writeTimezoneOnset: a STANDARD or DAYLIGHT component, its start written in the offset it replaces */
+func writeTimezoneOnset(buf *bytes.Buffer, onset time.Time, offsetFrom int, offsetTo int, name string, daylight bool) {
+	component := "STANDARD"
+	if daylight {
+		component = "DAYLIGHT"
+	}
+	writeContentLine(buf, "BEGIN:"+component)
+	writeContentLine(buf, "DTSTART:"+localTime(onset.In(time.FixedZone("", offsetFrom))))
+	writeContentLine(buf, "TZOFFSETFROM:"+formatOffset(offsetFrom))
+	writeContentLine(buf, "TZOFFSETTO:"+formatOffset(offsetTo))
+	writeContentLine(buf, "TZNAME:"+escapeText(name))
+	writeContentLine(buf, "END:"+component)
+}
+
+/* standardOffset: the lowest offset of the year, taken as the standard one */
+func standardOffset(t time.Time) int {
+	_, january := time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location()).Zone()
+	_, july := time.Date(t.Year(), time.July, 1, 0, 0, 0, 0, t.Location()).Zone()
+	if january < july {
+		return january
+	}
+	return july
+}
+
+/* formatOffset: an offset as +hhmm */
+func formatOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset%3600/60)
+}
+
+/* utcTime: a time in the UTC form of RFC 5545 */
+func utcTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+/* localTime: a time in the local form of RFC 5545, read in the zone of its TZID */
+func localTime(t time.Time) string {
+	return t.Format("20060102T150405")
+}
+
+/* escapeText: escape a TEXT value */
+func escapeText(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(text)
+}
+
+/* writeContentLine: write a line ended by CRLF, folded after 75 octets without splitting a character */
+func writeContentLine(buf *bytes.Buffer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		//the space starting a folded line counts towards its length
+		limit = 74
+	}
+	buf.WriteString(line + "\r\n")
+}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+	"unicode/utf8"
+)
+
+/* timezoneLines: the VTIMEZONE of a calendar with one event at start, as unfolded lines */
+func timezoneLines(t *testing.T, zone string, start time.Time) []string {
+	location, err := time.LoadLocation(zone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calendar := NewCalendar("Events", location)
+	calendar.Events = append(calendar.Events, &CalendarEvent{UID: "event@test", Start: start, End: start.Add(time.Hour)})
+	buf := bytes.NewBuffer(nil)
+	calendar.writeTimezone(buf)
+	return strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+}
+
+func TestCalendarWriteTimezone(t *testing.T) {
+	tests := []struct {
+		name  string
+		zone  string
+		start time.Time
+		want  []string
+	}{
+		{
+			name:  "daylight saving",
+			zone:  "Europe/Berlin",
+			start: time.Date(2026, time.June, 1, 9, 0, 0, 0, time.UTC),
+			want: []string{
+				"BEGIN:STANDARD", "DTSTART:20260101T000000", "TZOFFSETFROM:+0100", "TZOFFSETTO:+0100", "TZNAME:CET", "END:STANDARD",
+				"BEGIN:DAYLIGHT", "DTSTART:20260329T020000", "TZOFFSETFROM:+0100", "TZOFFSETTO:+0200", "TZNAME:CEST", "END:DAYLIGHT",
+				"BEGIN:STANDARD", "DTSTART:20261025T030000", "TZOFFSETFROM:+0200", "TZOFFSETTO:+0100", "TZNAME:CET", "END:STANDARD",
+			},
+		},
+		{
+			name:  "no daylight saving",
+			zone:  "Asia/Ho_Chi_Minh",
+			start: time.Date(2026, time.June, 1, 9, 0, 0, 0, time.UTC),
+			want: []string{
+				"BEGIN:STANDARD", "DTSTART:20260101T000000", "TZOFFSETFROM:+0700", "TZOFFSETTO:+0700", "TZNAME:+07", "END:STANDARD",
+			},
+		},
+		{
+			name:  "negative offset",
+			zone:  "America/New_York",
+			start: time.Date(2026, time.June, 1, 9, 0, 0, 0, time.UTC),
+			want: []string{
+				"BEGIN:DAYLIGHT", "DTSTART:20260308T020000", "TZOFFSETFROM:-0500", "TZOFFSETTO:-0400", "TZNAME:EDT", "END:DAYLIGHT",
+				"BEGIN:STANDARD", "DTSTART:20261101T020000", "TZOFFSETFROM:-0400", "TZOFFSETTO:-0500", "TZNAME:EST", "END:STANDARD",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := timezoneLines(t, tt.zone, tt.start)
+			if lines[0] != "BEGIN:VTIMEZONE" || lines[1] != "TZID:"+tt.zone || lines[len(lines)-1] != "END:VTIMEZONE" {
+				t.Fatalf("VTIMEZONE = %q", lines)
+			}
+			//the window runs to the current year, the onsets of the event year come first
+			got := strings.Join(lines, "\n")
+			if !strings.Contains(got, strings.Join(tt.want, "\n")) {
+				t.Errorf("VTIMEZONE =\n%s\nwant the lines\n%s", got, strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestCalendarToBytesTimes(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, time.March, 30, 7, 0, 0, 0, time.UTC)
+	calendar := NewCalendar("Events", berlin)
+	calendar.Events = append(calendar.Events,
+		&CalendarEvent{UID: "long@test", Summary: "Hội thảo", Start: start, End: start.Add(2 * time.Hour)},
+		&CalendarEvent{UID: "instant@test", Summary: "Deadline", Start: start, End: start},
+	)
+	got := string(calendar.ToBytes())
+	for _, want := range []string{
+		"X-WR-TIMEZONE:Europe/Berlin\r\n",
+		"DTSTART;TZID=Europe/Berlin:20260330T090000\r\n",
+		"DTEND;TZID=Europe/Berlin:20260330T110000\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ToBytes() does not contain %q", want)
+		}
+	}
+	//an event ending when it starts has no DTEND
+	if count := strings.Count(got, "DTEND;"); count != 1 {
+		t.Errorf("ToBytes() has %d DTEND, want 1", count)
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "Go workshop", want: "Go workshop"},
+		{text: "Room 1; floor 2, left", want: `Room 1\; floor 2\, left`},
+		{text: `C:\events`, want: `C:\\events`},
+		{text: "first\r\nsecond\nthird\rfourth", want: `first\nsecond\nthird\nfourth`},
+	}
+	for _, tt := range tests {
+		if got := escapeText(tt.text); got != tt.want {
+			t.Errorf("escapeText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestWriteContentLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{name: "short", line: "SUMMARY:Go workshop"},
+		{name: "exactly 75 octets", line: "SUMMARY:" + strings.Repeat("a", 67)},
+		{name: "long ascii", line: "DESCRIPTION:" + strings.Repeat("abcdefghij", 20)},
+		{name: "multi byte characters", line: "DESCRIPTION:" + strings.Repeat("Hội thảo ", 30)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			writeContentLine(buf, tt.line)
+			got := buf.String()
+			if !strings.HasSuffix(got, "\r\n") {
+				t.Fatalf("writeContentLine() = %q, want a CRLF at the end", got)
+			}
+			lines := strings.Split(strings.TrimSuffix(got, "\r\n"), "\r\n")
+			for i, line := range lines {
+				if len(line) > 75 {
+					t.Errorf("line %d has %d octets, want at most 75", i, len(line))
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a character: %q", i, line)
+				}
+				if i > 0 && !strings.HasPrefix(line, " ") {
+					t.Errorf("folded line %d = %q, want a leading space", i, line)
+				}
+			}
+			//unfolding gives the line back
+			if unfolded := strings.ReplaceAll(strings.TrimSuffix(got, "\r\n"), "\r\n ", ""); unfolded != tt.line {
+				t.Errorf("unfolded = %q, want %q", unfolded, tt.line)
+			}
+			if len(tt.line) <= 75 && len(lines) != 1 {
+				t.Errorf("writeContentLine() folded a line of %d octets", len(tt.line))
+			}
+		})
+	}
+}
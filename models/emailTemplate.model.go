@@ -0,0 +1,87 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var CollectionEmailTemplateName = "emailTemplates"
+
+/* Names of the mail templates, the outbox kinds use the same names */
+var (
+	EmailTemplateInvitation    = "INVITATION"
+	EmailTemplateReminder      = "REMINDER"
+	EmailTemplatePromotion     = "PROMOTION"
+	EmailTemplateCancellation  = "CANCELLATION"
+	EmailTemplateApproval      = "APPROVAL"
+	EmailTemplatePasswordReset = "PASSWORD_RESET"
+	EmailTemplateTaskReminder  = "TASK_REMINDER"
+)
+
+/* EmailTemplateNames: every template a mail can be built from */
+var EmailTemplateNames = []string{
+	EmailTemplateInvitation,
+	EmailTemplateReminder,
+	EmailTemplatePromotion,
+	EmailTemplateCancellation,
+	EmailTemplateApproval,
+	EmailTemplatePasswordReset,
+	EmailTemplateTaskReminder,
+}
+
+/* Languages the mail templates are written in, english is the fallback */
+var (
+	MailLanguageEnglish    = "en"
+	MailLanguageVietnamese = "vi"
+)
+
+/* MailLanguages: the languages a template can be stored for */
+var MailLanguages = []string{MailLanguageEnglish, MailLanguageVietnamese}
+
+/* EmailTemplate: a template stored by an admin, it overrides the default one of the same name and language */
+type EmailTemplate struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+	Name      string             `bson:"name" json:"name"`
+	Language  string             `bson:"language" json:"language"`
+	Subject   string             `bson:"subject" json:"subject"`
+	Html      string             `bson:"html" json:"html"`
+	Text      string             `bson:"text" json:"text"`
+}
+
+/* MailTemplateData: the values a mail template can use */
+type MailTemplateData struct {
+	Name       string
+	EventName  string
+	EventStart string
+	Location   string
+	Link       string
+	TaskName   string
+	TaskStart  string
+}
+
+//mailWeekdays: the names of the days of the week in vietnamese, sunday first like time.Weekday
+var mailWeekdays = []string{"Chủ Nhật", "Thứ Hai", "Thứ Ba", "Thứ Tư", "Thứ Năm", "Thứ Sáu", "Thứ Bảy"}
+
+/* FormatMailDate: a date written for the readers of a template language, in the zone of the date */
+func FormatMailDate(t time.Time, language string) string {
+	offset := t.Format("-07:00")
+	if language == MailLanguageVietnamese {
+		return fmt.Sprintf("%s, %s, %s (GMT%s)", t.Format("15:04"), mailWeekdays[t.Weekday()], t.Format("02/01/2006"), offset)
+	}
+	return fmt.Sprintf("%s (GMT%s)", t.Format("Monday, 2 January 2006, 15:04"), offset)
+}
+
+/* MailLanguage: the template language of an event language such as "Vietnamese" or "vi-VN" */
+func MailLanguage(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	switch {
+	case strings.HasPrefix(language, "vi"), language == "tiếng việt":
+		return MailLanguageVietnamese
+	}
+	return MailLanguageEnglish
+}
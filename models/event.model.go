@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -8,11 +9,59 @@ import (
 
 var CollectionEventName = "events"
 
+/* Types of customize fields */
+var (
+	FieldTypeText         = "TEXT"
+	FieldTypeNumber       = "NUMBER"
+	FieldTypeEmail        = "EMAIL"
+	FieldTypePhone        = "PHONE"
+	FieldTypeDate         = "DATE"
+	FieldTypeSingleSelect = "SINGLE_SELECT"
+	FieldTypeMultiSelect  = "MULTI_SELECT"
+	FieldTypeCheckbox     = "CHECKBOX"
+	FieldTypeFile         = "FILE"
+)
+
+/* CustomizeField: an extra question asked on registration */
 type CustomizeField struct {
-	Name     string
-	Type     string
-	Values   []string
-	Required bool
+	Name      string
+	Type      string
+	Values    []string
+	Required  bool
+	Order     int
+	Min       *float64
+	Max       *float64
+	Pattern   string
+	MaxLength *int
+}
+
+/* FieldType: the type of the field, free text types stored before types were defined are mapped */
+func (f *CustomizeField) FieldType() string {
+	switch f.Type {
+	case FieldTypeText, FieldTypeNumber, FieldTypeEmail, FieldTypePhone, FieldTypeDate,
+		FieldTypeSingleSelect, FieldTypeMultiSelect, FieldTypeCheckbox, FieldTypeFile:
+		return f.Type
+	}
+	switch strings.ToLower(f.Type) {
+	case "select", "radio":
+		return FieldTypeSingleSelect
+	case "checkbox":
+		//old checkbox fields with options allowed several of them
+		if len(f.Values) > 0 {
+			return FieldTypeMultiSelect
+		}
+		return FieldTypeCheckbox
+	case "number", "email", "phone", "date", "file":
+		return strings.ToUpper(f.Type)
+	}
+	return FieldTypeText
+}
+
+/* CustomizeFieldVersion: the customize fields of an event as they were at one version */
+type CustomizeFieldVersion struct {
+	Version   int               `bson:"version" json:"version"`
+	CreatedAt time.Time         `bson:"createdAt" json:"createdAt"`
+	Fields    []*CustomizeField `bson:"fields" json:"fields"`
 }
 
 /* Model Type */
@@ -22,6 +71,7 @@ type Event struct {
 	UpdatedAt             time.Time            `bson:"updatedAt" json:"updatedAt"`
 	Tags                  []string             `bson:"tags" json:"tags"`
 	IsApproved            bool                 `bson:"isApproved" json:"isApproved"`
+	ApprovedAt            *time.Time           `bson:"approvedAt,omitempty" json:"approvedAt"`
 	Reviewer              *primitive.ObjectID  `bson:"reviewer" json:"reviewer"`
 	IsFinished            bool                 `bson:"isFinished" json:"isFinished"`
 	Tasks                 []primitive.ObjectID `bson:"tasks,omitempty" json:"tasks"`
@@ -36,10 +86,27 @@ type Event struct {
 	StartDate             time.Time            `bson:"startDate" json:"startDate"`
 	EndDate               time.Time            `bson:"endDate" json:"endDate"`
 	MaxParticipants       int                  `bson:"maxParticipants" json:"maxParticipants"`
+	RegisteredCount       int                  `bson:"registeredCount,omitempty" json:"registeredCount"`
 	Description           string               `bson:"description" json:"description"`
 	Owner                 primitive.ObjectID   `bson:"owner" json:"owner"`
 	Budget                float64              `bson:"budget" json:"budget"`
 	Image                 string               `bson:"image" json:"image"`
 	IsDeleted             bool                 `bson:"isDeleted" json:"isDeleted"`
 	CustomizeFields       []*CustomizeField    `bson:"customizeField" json:"customizeField"`
+	//answers keep the version they were given against
+	CustomizeFieldsVersion int                      `bson:"customizeFieldsVersion" json:"customizeFieldsVersion"`
+	CustomizeFieldVersions []*CustomizeFieldVersion `bson:"customizeFieldVersions" json:"customizeFieldVersions"`
+	//events created from a recurrence rule keep their series and the start the rule gave them
+	Series          *primitive.ObjectID `bson:"series,omitempty" json:"series"`
+	OccurrenceStart *time.Time          `bson:"occurrenceStart,omitempty" json:"occurrenceStart"`
+}
+
+/* CustomizeFieldsAt: the customize fields at a version, the current ones when the version is unknown */
+func (e *Event) CustomizeFieldsAt(version int) []*CustomizeField {
+	for _, v := range e.CustomizeFieldVersions {
+		if v.Version == version {
+			return v.Fields
+		}
+	}
+	return e.CustomizeFields
 }
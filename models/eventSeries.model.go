@@ -0,0 +1,279 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var CollectionEventSeriesName = "eventSeries"
+
+/* Frequencies of a recurrence rule */
+var (
+	RecurrenceDaily   = "DAILY"
+	RecurrenceWeekly  = "WEEKLY"
+	RecurrenceMonthly = "MONTHLY"
+)
+
+/* Scopes of a change made to an occurrence of a series */
+var (
+	RecurrenceScopeThis             = "THIS"
+	RecurrenceScopeThisAndFollowing = "THIS_AND_FOLLOWING"
+	RecurrenceScopeAll              = "ALL"
+)
+
+//MaxOccurrences: the most events a single rule can create
+var MaxOccurrences = 200
+
+//recurrenceWeekdays: the BYDAY codes of RFC 5545
+var recurrenceWeekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+/* EventSeries: the events created from one recurrence rule */
+type EventSeries struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+	Rule      string             `bson:"rule" json:"rule"`
+	//start of the first occurrence, the rule is expanded from it
+	Start time.Time `bson:"start" json:"start"`
+	//occurrences skipped on creation or deleted on their own afterwards
+	Exceptions []time.Time `bson:"exceptions" json:"exceptions"`
+}
+
+/* RecurrenceRule: the supported subset of an RFC 5545 RRULE */
+type RecurrenceRule struct {
+	Frequency string
+	Interval  int
+	Count     int
+	Until     *time.Time
+	//weekly rules only, the weekday of the start when empty
+	ByDay []time.Weekday
+}
+
+/*
+ParseRecurrenceRule: read a rule such as FREQ=WEEKLY;BYDAY=TU,TH;COUNT=10.
+FREQ is DAILY, WEEKLY or MONTHLY and the rule must end with COUNT or UNTIL.
+An UNTIL without a zone, or given as a date, is read in the location
+*/
+func ParseRecurrenceRule(rule string, location *time.Location) (*RecurrenceRule, error) {
+	rule = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(rule)), "RRULE:")
+	r := &RecurrenceRule{Interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		pair := strings.SplitN(part, "=", 2)
+		if len(pair) != 2 || pair[1] == "" {
+			return nil, fmt.Errorf("invalid rule part %s", part)
+		}
+		name, value := pair[0], pair[1]
+		if seen[name] {
+			return nil, fmt.Errorf("%s is given twice", name)
+		}
+		seen[name] = true
+		switch name {
+		case "FREQ":
+			if value != RecurrenceDaily && value != RecurrenceWeekly && value != RecurrenceMonthly {
+				return nil, fmt.Errorf("unsupported frequency %s, use DAILY, WEEKLY or MONTHLY", value)
+			}
+			r.Frequency = value
+		case "INTERVAL", "COUNT":
+			number, err := strconv.Atoi(value)
+			if err != nil || number < 1 {
+				return nil, fmt.Errorf("%s must be a positive number", name)
+			}
+			if name == "INTERVAL" {
+				r.Interval = number
+			} else {
+				r.Count = number
+			}
+		case "UNTIL":
+			until, err := parseRecurrenceUntil(value, location)
+			if err != nil {
+				return nil, err
+			}
+			r.Until = &until
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				weekday, ok := recurrenceWeekdays[code]
+				if !ok {
+					return nil, fmt.Errorf("unsupported day %s in BYDAY", code)
+				}
+				r.ByDay = append(r.ByDay, weekday)
+			}
+		case "WKST":
+			//weeks start on monday
+			if value != "MO" {
+				return nil, errors.New("only WKST=MO is supported")
+			}
+		default:
+			return nil, fmt.Errorf("unsupported rule part %s", name)
+		}
+	}
+	if r.Frequency == "" {
+		return nil, errors.New("FREQ must not be blanked")
+	}
+	if len(r.ByDay) > 0 && r.Frequency != RecurrenceWeekly {
+		return nil, errors.New("BYDAY is only supported on weekly rules")
+	}
+	if r.Count > 0 && r.Until != nil {
+		return nil, errors.New("COUNT and UNTIL cannot be used together")
+	}
+	if r.Count == 0 && r.Until == nil {
+		return nil, errors.New("the rule must end with COUNT or UNTIL")
+	}
+	//the order of BYDAY follows the week
+	sort.SliceStable(r.ByDay, func(i, j int) bool {
+		return weekdayOffset(r.ByDay[i]) < weekdayOffset(r.ByDay[j])
+	})
+	return r, nil
+}
+
+/* parseRecurrenceUntil: an UTC time, a local time or a local date which is included until its end */
+func parseRecurrenceUntil(value string, location *time.Location) (time.Time, error) {
+	if until, err := time.Parse("20060102T150405Z", value); err == nil {
+		return until, nil
+	}
+	if until, err := time.ParseInLocation("20060102T150405", value, location); err == nil {
+		return until, nil
+	}
+	if until, err := time.ParseInLocation("20060102", value, location); err == nil {
+		return until.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid UNTIL %s, use YYYYMMDD or YYYYMMDDTHHMMSSZ", value)
+}
+
+/* String: the rule in RFC 5545 form */
+func (r *RecurrenceRule) String() string {
+	parts := []string{"FREQ=" + r.Frequency}
+	if r.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, 0)
+		for _, weekday := range r.ByDay {
+			for code, v := range recurrenceWeekdays {
+				if v == weekday {
+					codes = append(codes, code)
+				}
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+/*
+Occurrences: the starts generated by the rule from the first one, which always counts as an occurrence.
+Occurrences keep the wall clock time of the first one in the location, so they do not move with daylight saving.
+Days missing from a month are skipped, and exceptions remove occurrences on the same local date after COUNT applied
+*/
+func (r *RecurrenceRule) Occurrences(start time.Time, location *time.Location, exceptions []time.Time) ([]time.Time, error) {
+	local := start.In(location)
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, local.Hour(), local.Minute(), local.Second(), local.Nanosecond(), location)
+	}
+	starts := []time.Time{start}
+	done := func(candidate time.Time) bool {
+		return (r.Count > 0 && len(starts) >= r.Count) || (r.Until != nil && candidate.After(*r.Until))
+	}
+	//a monthly rule on the 29th can skip most months, so the periods are bounded beyond the occurrences
+	for period := 0; period <= MaxOccurrences*12 && !done(start); period++ {
+		step := period * r.Interval
+		candidates := make([]time.Time, 0)
+		switch r.Frequency {
+		case RecurrenceDaily:
+			candidates = append(candidates, at(local.Year(), local.Month(), local.Day()+step))
+		case RecurrenceWeekly:
+			if len(r.ByDay) == 0 {
+				candidates = append(candidates, at(local.Year(), local.Month(), local.Day()+7*step))
+			}
+			monday := local.Day() - weekdayOffset(local.Weekday()) + 7*step
+			for _, weekday := range r.ByDay {
+				candidates = append(candidates, at(local.Year(), local.Month(), monday+weekdayOffset(weekday)))
+			}
+		case RecurrenceMonthly:
+			candidate := at(local.Year(), local.Month()+time.Month(step), local.Day())
+			if candidate.Day() == local.Day() {
+				candidates = append(candidates, candidate)
+			}
+		}
+		for _, candidate := range candidates {
+			if !candidate.After(start) {
+				continue
+			}
+			if done(candidate) {
+				break
+			}
+			starts = append(starts, candidate)
+			if len(starts) > MaxOccurrences {
+				return nil, fmt.Errorf("the rule cannot repeat more than %d times", MaxOccurrences)
+			}
+		}
+		if len(candidates) > 0 && done(candidates[len(candidates)-1]) {
+			break
+		}
+	}
+
+	occurrences := make([]time.Time, 0)
+	for _, v := range starts {
+		if !IsRecurrenceException(v, location, exceptions) {
+			occurrences = append(occurrences, v)
+		}
+	}
+	return occurrences, nil
+}
+
+/* IsRecurrenceException: whether an occurrence falls on the local date of an exception */
+func IsRecurrenceException(occurrence time.Time, location *time.Location, exceptions []time.Time) bool {
+	for _, exception := range exceptions {
+		if SameLocalDate(occurrence, exception, location) {
+			return true
+		}
+	}
+	return false
+}
+
+/* SameLocalDate: whether two times fall on the same date in a location */
+func SameLocalDate(a time.Time, b time.Time, location *time.Location) bool {
+	ay, am, ad := a.In(location).Date()
+	by, bm, bd := b.In(location).Date()
+	return ay == by && am == bm && ad == bd
+}
+
+/* LocalDaysBetween: the number of dates between two times in a location, whatever their clock */
+func LocalDaysBetween(from time.Time, to time.Time, location *time.Location) int {
+	f, t := from.In(location), to.In(location)
+	return int(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Sub(time.Date(f.Year(), f.Month(), f.Day(), 0, 0, 0, 0, time.UTC)).Hours() / 24)
+}
+
+/* AddLocalDays: move a time by a number of dates, keeping its wall clock time in the location across daylight saving */
+func AddLocalDays(t time.Time, days int, location *time.Location) time.Time {
+	l := t.In(location)
+	return time.Date(l.Year(), l.Month(), l.Day()+days, l.Hour(), l.Minute(), l.Second(), l.Nanosecond(), location)
+}
+
+/* weekdayOffset: days since the monday of the week */
+func weekdayOffset(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
@@ -0,0 +1,174 @@
+package models
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParseRecurrenceRule(t *testing.T) {
+	location := time.UTC
+	tests := []struct {
+		name    string
+		rule    string
+		want    string
+		wantErr bool
+	}{
+		{name: "weekly count", rule: "FREQ=WEEKLY;COUNT=4", want: "FREQ=WEEKLY;COUNT=4"},
+		{name: "prefix and case", rule: " rrule:freq=daily;interval=2;count=3 ", want: "FREQ=DAILY;INTERVAL=2;COUNT=3"},
+		{name: "byday in week order", rule: "FREQ=WEEKLY;BYDAY=TH,TU;COUNT=10", want: "FREQ=WEEKLY;BYDAY=TU,TH;COUNT=10"},
+		{name: "until utc", rule: "FREQ=MONTHLY;UNTIL=20260630T000000Z", want: "FREQ=MONTHLY;UNTIL=20260630T000000Z"},
+		{name: "until date runs to its end", rule: "FREQ=DAILY;UNTIL=20260630", want: "FREQ=DAILY;UNTIL=20260630T235959Z"},
+		{name: "week starting monday", rule: "FREQ=WEEKLY;WKST=MO;COUNT=2", want: "FREQ=WEEKLY;COUNT=2"},
+		{name: "no frequency", rule: "COUNT=2", wantErr: true},
+		{name: "yearly", rule: "FREQ=YEARLY;COUNT=2", wantErr: true},
+		{name: "no end", rule: "FREQ=DAILY", wantErr: true},
+		{name: "count and until", rule: "FREQ=DAILY;COUNT=2;UNTIL=20260630", wantErr: true},
+		{name: "byday on daily", rule: "FREQ=DAILY;BYDAY=MO;COUNT=2", wantErr: true},
+		{name: "unknown day", rule: "FREQ=WEEKLY;BYDAY=XX;COUNT=2", wantErr: true},
+		{name: "zero interval", rule: "FREQ=DAILY;INTERVAL=0;COUNT=2", wantErr: true},
+		{name: "part twice", rule: "FREQ=DAILY;COUNT=2;COUNT=3", wantErr: true},
+		{name: "unsupported part", rule: "FREQ=DAILY;COUNT=2;BYMONTH=1", wantErr: true},
+		{name: "week starting sunday", rule: "FREQ=WEEKLY;WKST=SU;COUNT=2", wantErr: true},
+		{name: "bad until", rule: "FREQ=DAILY;UNTIL=tomorrow", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRecurrenceRule(tt.rule, location)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseRecurrenceRule(%q) = %s, want an error", tt.rule, rule)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRecurrenceRule(%q): %v", tt.rule, err)
+			}
+			if got := rule.String(); got != tt.want {
+				t.Errorf("ParseRecurrenceRule(%q).String() = %q, want %q", tt.rule, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecurrenceRuleOccurrences(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	date := func(year int, month time.Month, day int, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, berlin)
+	}
+	tests := []struct {
+		name       string
+		rule       string
+		start      time.Time
+		exceptions []time.Time
+		want       []time.Time
+		wantErr    bool
+	}{
+		{
+			name:  "daily with interval",
+			rule:  "FREQ=DAILY;INTERVAL=2;COUNT=3",
+			start: date(2026, time.March, 2, 9),
+			want:  []time.Time{date(2026, time.March, 2, 9), date(2026, time.March, 4, 9), date(2026, time.March, 6, 9)},
+		},
+		{
+			name:  "weekly keeps the wall clock across daylight saving",
+			rule:  "FREQ=WEEKLY;COUNT=3",
+			start: date(2026, time.March, 19, 9),
+			want:  []time.Time{date(2026, time.March, 19, 9), date(2026, time.March, 26, 9), date(2026, time.April, 2, 9)},
+		},
+		{
+			name:  "weekly by day starts from the first one",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4",
+			start: date(2026, time.March, 4, 18),
+			want:  []time.Time{date(2026, time.March, 4, 18), date(2026, time.March, 9, 18), date(2026, time.March, 11, 18), date(2026, time.March, 16, 18)},
+		},
+		{
+			name:  "every other week by day",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;COUNT=4",
+			start: date(2026, time.March, 3, 10),
+			want:  []time.Time{date(2026, time.March, 3, 10), date(2026, time.March, 5, 10), date(2026, time.March, 17, 10), date(2026, time.March, 19, 10)},
+		},
+		{
+			name:  "monthly skips months without the day",
+			rule:  "FREQ=MONTHLY;COUNT=3",
+			start: date(2026, time.January, 31, 9),
+			want:  []time.Time{date(2026, time.January, 31, 9), date(2026, time.March, 31, 9), date(2026, time.May, 31, 9)},
+		},
+		{
+			name:  "until is included",
+			rule:  "FREQ=DAILY;UNTIL=20260304",
+			start: date(2026, time.March, 2, 9),
+			want:  []time.Time{date(2026, time.March, 2, 9), date(2026, time.March, 3, 9), date(2026, time.March, 4, 9)},
+		},
+		{
+			name:       "exceptions apply after the count",
+			rule:       "FREQ=DAILY;COUNT=3",
+			start:      date(2026, time.March, 2, 9),
+			exceptions: []time.Time{date(2026, time.March, 3, 0)},
+			want:       []time.Time{date(2026, time.March, 2, 9), date(2026, time.March, 4, 9)},
+		},
+		{
+			name:    "too many occurrences",
+			rule:    "FREQ=DAILY;COUNT=500",
+			start:   date(2026, time.March, 2, 9),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRecurrenceRule(tt.rule, berlin)
+			if err != nil {
+				t.Fatalf("ParseRecurrenceRule(%q): %v", tt.rule, err)
+			}
+			got, err := rule.Occurrences(tt.start, berlin, tt.exceptions)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Occurrences() gave %d starts, want an error", len(got))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Occurrences(): %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Occurrences() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("occurrence %d = %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAddLocalDays(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		t    time.Time
+		days int
+		want time.Time
+	}{
+		{name: "across spring forward", t: time.Date(2026, time.March, 28, 9, 0, 0, 0, berlin), days: 1, want: time.Date(2026, time.March, 29, 9, 0, 0, 0, berlin)},
+		{name: "back across fall back", t: time.Date(2026, time.October, 26, 9, 0, 0, 0, berlin), days: -1, want: time.Date(2026, time.October, 25, 9, 0, 0, 0, berlin)},
+		{name: "utc time read in the location", t: time.Date(2026, time.March, 1, 23, 30, 0, 0, time.UTC), days: 7, want: time.Date(2026, time.March, 9, 0, 30, 0, 0, berlin)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AddLocalDays(tt.t, tt.days, berlin)
+			if !got.Equal(tt.want) {
+				t.Errorf("AddLocalDays(%s, %d) = %s, want %s", tt.t, tt.days, got, tt.want)
+			}
+			if days := LocalDaysBetween(tt.t, got, berlin); days != tt.days {
+				t.Errorf("LocalDaysBetween() = %d, want %d", days, tt.days)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var CollectionEventTemplateName = "eventTemplates"
+
+/* TemplateTask: a task of a template, placed in minutes from the start of the event */
+type TemplateTask struct {
+	Name        string             `bson:"name" json:"name"`
+	User        primitive.ObjectID `bson:"user" json:"user"`
+	Type        string             `bson:"type" json:"type"`
+	StartOffset int                `bson:"startOffset" json:"startOffset"`
+	EndOffset   int                `bson:"endOffset" json:"endOffset"`
+}
+
+/* TemplateFacility: a facility booking of a template, placed in minutes from the start of the event */
+type TemplateFacility struct {
+	Facility     primitive.ObjectID `bson:"facility" json:"facility"`
+	BorrowOffset int                `bson:"borrowOffset" json:"borrowOffset"`
+	ReturnOffset int                `bson:"returnOffset" json:"returnOffset"`
+	Quantity     int                `bson:"quantity" json:"quantity"`
+}
+
+/* EventTemplate: an event without dates, its events are placed with a new start */
+type EventTemplate struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CreatedAt       time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt       time.Time          `bson:"updatedAt" json:"updatedAt"`
+	Name            string             `bson:"name" json:"name"`
+	Tags            []string           `bson:"tags" json:"tags"`
+	Language        string             `bson:"language" json:"language"`
+	EventType       primitive.ObjectID `bson:"eventType" json:"eventType"`
+	Mode            string             `bson:"mode" json:"mode"`
+	Location        string             `bson:"location" json:"location"`
+	Accommodation   string             `bson:"accommodation" json:"accommodation"`
+	MaxParticipants int                `bson:"maxParticipants" json:"maxParticipants"`
+	Description     string             `bson:"description" json:"description"`
+	Owner           primitive.ObjectID `bson:"owner" json:"owner"`
+	Budget          float64            `bson:"budget" json:"budget"`
+	Image           string             `bson:"image" json:"image"`
+	CustomizeFields []*CustomizeField  `bson:"customizeFields" json:"customizeFields"`
+	//length of the event in minutes
+	Duration int `bson:"duration" json:"duration"`
+	//minutes the registration closes before the start
+	RegistrationCloseLead int                 `bson:"registrationCloseLead" json:"registrationCloseLead"`
+	Tasks                 []*TemplateTask     `bson:"tasks" json:"tasks"`
+	Facilities            []*TemplateFacility `bson:"facilities" json:"facilities"`
+}
+
+/* NewEventTemplate: the template of an event, the dates of the event, its tasks and its bookings become offsets from its start */
+func NewEventTemplate(name string, event *Event, tasks []*Task, facilityHistories []*FacilityHistory) *EventTemplate {
+	template := &EventTemplate{
+		Name:                  name,
+		Tags:                  event.Tags,
+		Language:              event.Language,
+		EventType:             event.EventType,
+		Mode:                  event.Mode,
+		Location:              event.Location,
+		Accommodation:         event.Accommodation,
+		MaxParticipants:       event.MaxParticipants,
+		Description:           event.Description,
+		Owner:                 event.Owner,
+		Budget:                event.Budget,
+		Image:                 event.Image,
+		CustomizeFields:       event.CustomizeFields,
+		Duration:              minutesBetween(event.StartDate, event.EndDate),
+		RegistrationCloseLead: minutesBetween(event.RegistrationCloseDate, event.StartDate),
+		Tasks:                 make([]*TemplateTask, 0),
+		Facilities:            make([]*TemplateFacility, 0),
+	}
+	for _, task := range tasks {
+		template.Tasks = append(template.Tasks, &TemplateTask{
+			Name:        task.Name,
+			User:        task.User,
+			Type:        task.Type,
+			StartOffset: minutesBetween(event.StartDate, task.StartDate),
+			EndOffset:   minutesBetween(event.StartDate, task.EndDate),
+		})
+	}
+	for _, facilityHistory := range facilityHistories {
+		template.Facilities = append(template.Facilities, &TemplateFacility{
+			Facility:     facilityHistory.Facility,
+			BorrowOffset: minutesBetween(event.StartDate, facilityHistory.BorrowDate),
+			ReturnOffset: minutesBetween(event.StartDate, facilityHistory.ReturnDate),
+			Quantity:     facilityHistory.Units(),
+		})
+	}
+	return template
+}
+
+/* TemplateDate: the date a number of minutes away from the start of an event */
+func TemplateDate(start time.Time, offset int) time.Time {
+	return start.Add(time.Duration(offset) * time.Minute)
+}
+
+/* minutesBetween: whole minutes from one time to another, negative when it is before */
+func minutesBetween(from time.Time, to time.Time) int {
+	return int(to.Sub(from) / time.Minute)
+}
@@ -15,6 +15,15 @@ type Facility struct {
 	Status    bool               `bson:"status" json:"status"`
 	Name      string             `bson:"name" json:"name"`
 	Code      string             `bson:"code" json:"code"`
-	Type      string             `bson:"type" json:"type"`
+	Type      primitive.ObjectID `bson:"type" json:"type"`
+	Quantity  int                `bson:"quantity" json:"quantity"`
 	IsDeleted bool               `bson:"isDeleted" json:"isDeleted"`
 }
+
+/* Stock: the number of units owned, facilities created before quantities existed count as one */
+func (f *Facility) Stock() int {
+	if f.Quantity <= 0 {
+		return 1
+	}
+	return f.Quantity
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"sort"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -8,6 +9,22 @@ import (
 
 var CollectionFacilityHistoryName = "facilityHistories"
 
+/* Damage status recorded when a facility changes hands */
+var (
+	DamageStatusNone  = "NONE"
+	DamageStatusMinor = "MINOR"
+	DamageStatusMajor = "MAJOR"
+	DamageStatusLost  = "LOST"
+)
+
+/* FacilityMovement: what actually happened when a facility was checked out or returned */
+type FacilityMovement struct {
+	At           time.Time          `bson:"at" json:"at"`
+	Staff        primitive.ObjectID `bson:"staff" json:"staff"`
+	Condition    string             `bson:"condition" json:"condition"`
+	DamageStatus string             `bson:"damageStatus" json:"damageStatus"`
+}
+
 type FacilityHistory struct {
 	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
@@ -16,4 +33,58 @@ type FacilityHistory struct {
 	BorrowDate time.Time          `bson:"borrowDate" json:"borrowDate"`
 	ReturnDate time.Time          `bson:"returnDate" json:"returnDate"`
 	Event      primitive.ObjectID `bson:"event,omitempty" json:"event"`
+	Quantity   int                `bson:"quantity" json:"quantity"`
+	CheckedOut *FacilityMovement  `bson:"checkedOut" json:"checkedOut"`
+	Returned   *FacilityMovement  `bson:"returned" json:"returned"`
+}
+
+/* Units: the number of units booked, bookings made before quantities existed count as one */
+func (f *FacilityHistory) Units() int {
+	if f.Quantity <= 0 {
+		return 1
+	}
+	return f.Quantity
+}
+
+/* IsOpen: the facility has been checked out and has not come back yet */
+func (f *FacilityHistory) IsOpen() bool {
+	return f.CheckedOut != nil && f.Returned == nil
+}
+
+/* IsLate: the facility came back after the planned return date, or is still out past it */
+func (f *FacilityHistory) IsLate(now time.Time) bool {
+	if f.Returned != nil {
+		return f.Returned.At.After(f.ReturnDate)
+	}
+	return f.CheckedOut != nil && now.After(f.ReturnDate)
+}
+
+/* PeakBookedUnits: the highest number of units the bookings hold at the same time within the period */
+func PeakBookedUnits(bookings []*FacilityHistory, from time.Time, to time.Time) int {
+	type change struct {
+		at       time.Time
+		quantity int
+	}
+	changes := make([]change, 0)
+	for _, v := range bookings {
+		if !v.BorrowDate.Before(to) || !v.ReturnDate.After(from) {
+			continue
+		}
+		changes = append(changes, change{at: v.BorrowDate, quantity: v.Units()}, change{at: v.ReturnDate, quantity: -v.Units()})
+	}
+	//a unit returned at the moment another booking starts can be handed over again
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].at.Equal(changes[j].at) {
+			return changes[i].quantity < changes[j].quantity
+		}
+		return changes[i].at.Before(changes[j].at)
+	})
+	booked, peak := 0, 0
+	for _, v := range changes {
+		booked += v.quantity
+		if booked > peak {
+			peak = booked
+		}
+	}
+	return peak
 }
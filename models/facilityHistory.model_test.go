@@ -0,0 +1,40 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPeakBookedUnits(t *testing.T) {
+	day := func(hour int) time.Time {
+		return time.Date(2026, time.March, 2, hour, 0, 0, 0, time.UTC)
+	}
+	booking := func(from int, to int, quantity int) *FacilityHistory {
+		return &FacilityHistory{BorrowDate: day(from), ReturnDate: day(to), Quantity: quantity}
+	}
+	tests := []struct {
+		name     string
+		bookings []*FacilityHistory
+		from     time.Time
+		to       time.Time
+		want     int
+	}{
+		{name: "no bookings", from: day(0), to: day(24), want: 0},
+		{name: "one booking", bookings: []*FacilityHistory{booking(9, 12, 3)}, from: day(0), to: day(24), want: 3},
+		{name: "quantity before quantities existed counts as one", bookings: []*FacilityHistory{booking(9, 12, 0), booking(10, 11, 0)}, from: day(0), to: day(24), want: 2},
+		{name: "overlapping bookings add up", bookings: []*FacilityHistory{booking(9, 12, 2), booking(11, 14, 3)}, from: day(0), to: day(24), want: 5},
+		{name: "separate bookings do not add up", bookings: []*FacilityHistory{booking(9, 10, 2), booking(11, 14, 3)}, from: day(0), to: day(24), want: 3},
+		{name: "handover at the same time", bookings: []*FacilityHistory{booking(9, 12, 2), booking(12, 14, 2)}, from: day(0), to: day(24), want: 2},
+		{name: "peak in the middle", bookings: []*FacilityHistory{booking(8, 18, 1), booking(9, 11, 1), booking(10, 12, 1), booking(11, 13, 1)}, from: day(0), to: day(24), want: 3},
+		{name: "bookings outside the period are left out", bookings: []*FacilityHistory{booking(9, 12, 4), booking(14, 16, 1)}, from: day(13), to: day(24), want: 1},
+		{name: "a booking ending when the period starts is left out", bookings: []*FacilityHistory{booking(9, 12, 4)}, from: day(12), to: day(24), want: 0},
+		{name: "a booking starting when the period ends is left out", bookings: []*FacilityHistory{booking(12, 14, 4)}, from: day(0), to: day(12), want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PeakBookedUnits(tt.bookings, tt.from, tt.to); got != tt.want {
+				t.Errorf("PeakBookedUnits() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var CollectionFacilityTypeName = "facilityTypes"
+
+type FacilityType struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+	Name      string             `bson:"name" json:"name"`
+	IsDeleted bool               `bson:"isDeleted" json:"isDeleted"`
+}
@@ -5,25 +5,36 @@ import (
 	"encoding/base64"
 	"fmt"
 	"html/template"
+	"io"
+	"mime"
 	"mime/multipart"
+	"mime/quotedprintable"
 	"net/http"
-	"net/smtp"
+	"net/textproto"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
-)
+	"time"
 
-type Sender struct {
-	auth smtp.Auth
-}
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 type Mail struct {
-	To           []string
-	CC           []string
-	BCC          []string
-	Subject      string
+	From    string
+	To      []string
+	CC      []string
+	BCC     []string
+	Subject string
+	//Date and MessageID are set when the message is built unless they are given
+	Date      time.Time
+	MessageID string
+	//prefix of the multipart boundaries, random boundaries are used when it is empty
+	Boundary string
+	//plain text alternative of the html body
 	Body         string
 	Attachments  map[string][]byte
+	Inlines      map[string]*InlineFile
 	MailTemplate *MailTemplate
 }
 
@@ -32,18 +43,16 @@ type MailTemplate struct {
 	Data     interface{}
 }
 
-func NewSender() *Sender {
-	auth := smtp.PlainAuth("", os.Getenv("PROJECT_EMAIL"), os.Getenv("PROJECT_EMAIL_PASSWORD"), os.Getenv("PROJECT_EMAIL_HOST"))
-	return &Sender{auth}
-}
-
-func (s *Sender) Send(m *Mail) error {
-	return smtp.SendMail(fmt.Sprintf("%s:%s", os.Getenv("PROJECT_EMAIL_HOST"), os.Getenv("PROJECT_EMAIL_PORT")), s.auth, os.Getenv("PROJECT_EMAIL"), m.To, m.ToBytes())
+/* InlineFile: a file shown inside the html body, referenced as cid:<content id> */
+type InlineFile struct {
+	FileName string
+	Content  []byte
 }
 
 func NewMail() *Mail {
 	return &Mail{
 		Attachments:  make(map[string][]byte),
+		Inlines:      make(map[string]*InlineFile),
 		MailTemplate: nil,
 	}
 }
@@ -63,40 +72,216 @@ func (m *Mail) AttachByteFile(fileName string, fileByte []byte) error {
 	return nil
 }
 
-func (m *Mail) ToBytes() []byte {
+/* AttachInline: add a file the html body shows with <img src="cid:contentId"> */
+func (m *Mail) AttachInline(contentId string, fileName string, fileByte []byte) {
+	m.Inlines[contentId] = &InlineFile{FileName: fileName, Content: fileByte}
+}
+
+/* ToBytes: build the RFC 5322 message, the Bcc receivers are left out of the headers */
+func (m *Mail) ToBytes() ([]byte, error) {
+	html := ""
+	if m.MailTemplate != nil {
+		body := bytes.NewBuffer(nil)
+		if err := m.MailTemplate.Template.Execute(body, m.MailTemplate.Data); err != nil {
+			return nil, err
+		}
+		html = body.String()
+	}
+	if m.Date.IsZero() {
+		m.Date = time.Now()
+	}
+	if m.MessageID == "" {
+		m.MessageID = fmt.Sprintf("<%s@%s>", primitive.NewObjectID().Hex(), mailDomain(m.From))
+	}
+
 	buf := bytes.NewBuffer(nil)
-	withAttachments := len(m.Attachments) > 0
-	buf.WriteString(fmt.Sprintf("Subject: %s\n", m.Subject))
-	buf.WriteString(fmt.Sprintf("To: %s\n", strings.Join(m.To, ",")))
+	if m.From != "" {
+		writeHeader(buf, "From", m.From)
+	}
+	writeHeader(buf, "To", strings.Join(m.To, ", "))
 	if len(m.CC) > 0 {
-		buf.WriteString(fmt.Sprintf("Cc: %s\n", strings.Join(m.CC, ",")))
+		writeHeader(buf, "Cc", strings.Join(m.CC, ", "))
 	}
+	writeHeader(buf, "Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	writeHeader(buf, "Date", m.Date.Format(time.RFC1123Z))
+	writeHeader(buf, "Message-ID", m.MessageID)
+	writeHeader(buf, "MIME-Version", "1.0")
 
-	if len(m.BCC) > 0 {
-		buf.WriteString(fmt.Sprintf("Bcc: %s\n", strings.Join(m.BCC, ",")))
+	//the body is nested from the outside in: mixed > related > alternative
+	content := m.alternativePart(html)
+	if len(m.Inlines) > 0 {
+		content = m.relatedPart(content)
+	}
+	if len(m.Attachments) > 0 {
+		content = m.mixedPart(content)
+	}
+	if err := content(buf, nil); err != nil {
+		return nil, err
 	}
+	return buf.Bytes(), nil
+}
 
-	buf.WriteString("MIME-Version: 1.0\n")
-	writer := multipart.NewWriter(buf)
-	boundary := writer.Boundary()
-	buf.WriteString(fmt.Sprintf("Content-Type: multipart/mixed; boundary=%s\n", boundary))
-	buf.WriteString(fmt.Sprintf("--%s\n", boundary))
-	if m.MailTemplate != nil {
-		buf.WriteString("Content-Type: text/html; charset=utf-8\n")
-		m.MailTemplate.Template.Execute(buf, m.MailTemplate.Data)
-	}
-	if withAttachments {
-		for k, v := range m.Attachments {
-			buf.WriteString(fmt.Sprintf("\n\n--%s\n", boundary))
-			buf.WriteString(fmt.Sprintf("Content-Type: %s\n", http.DetectContentType(v)))
-			buf.WriteString("Content-Transfer-Encoding: base64\n")
-			buf.WriteString(fmt.Sprintf("Content-Disposition: attachment; filename=%s\n", k))
-			b := make([]byte, base64.StdEncoding.EncodedLen(len(v)))
-			base64.StdEncoding.Encode(b, v)
-			buf.Write(b)
-			buf.WriteString(fmt.Sprintf("\n--%s", boundary))
+/* mimePart: writes the headers and the body of one part, into a multipart writer when w is not nil */
+type mimePart func(buf *bytes.Buffer, w *multipart.Writer) error
+
+/* alternativePart: the plain text and the html body */
+func (m *Mail) alternativePart(html string) mimePart {
+	text := textPart("text/plain; charset=utf-8", m.Body)
+	if html == "" {
+		return text
+	}
+	htmlPart := textPart("text/html; charset=utf-8", html)
+	if m.Body == "" {
+		return htmlPart
+	}
+	return multipartOf("alternative", m.boundary("alternative"), []mimePart{text, htmlPart})
+}
+
+/* relatedPart: the body and the inline files it refers to */
+func (m *Mail) relatedPart(body mimePart) mimePart {
+	parts := []mimePart{body}
+	for _, contentId := range sortedKeys(m.Inlines) {
+		inline := m.Inlines[contentId]
+		parts = append(parts, filePart(inline.FileName, inline.Content, textproto.MIMEHeader{
+			"Content-Id":          {fmt.Sprintf("<%s>", contentId)},
+			"Content-Disposition": {mime.FormatMediaType("inline", map[string]string{"filename": inline.FileName})},
+		}))
+	}
+	return multipartOf("related", m.boundary("related"), parts)
+}
+
+/* mixedPart: the body and the attachments */
+func (m *Mail) mixedPart(body mimePart) mimePart {
+	parts := []mimePart{body}
+	for _, fileName := range sortedKeys(m.Attachments) {
+		parts = append(parts, filePart(fileName, m.Attachments[fileName], textproto.MIMEHeader{
+			"Content-Disposition": {mime.FormatMediaType("attachment", map[string]string{"filename": fileName})},
+		}))
+	}
+	return multipartOf("mixed", m.boundary("mixed"), parts)
+}
+
+/* boundary: the boundary of the multipart of a subtype, empty for a random one */
+func (m *Mail) boundary(subtype string) string {
+	if m.Boundary == "" {
+		return ""
+	}
+	return m.Boundary + "-" + subtype
+}
+
+/* multipartOf: a multipart part of the given subtype, separated by a random boundary when none is given */
+func multipartOf(subtype string, boundary string, parts []mimePart) mimePart {
+	return func(buf *bytes.Buffer, w *multipart.Writer) error {
+		body := bytes.NewBuffer(nil)
+		writer := multipart.NewWriter(body)
+		if boundary != "" {
+			if err := writer.SetBoundary(boundary); err != nil {
+				return err
+			}
+		}
+		for _, part := range parts {
+			if err := part(body, writer); err != nil {
+				return err
+			}
+		}
+		if err := writer.Close(); err != nil {
+			return err
+		}
+		header := textproto.MIMEHeader{
+			"Content-Type": {mime.FormatMediaType("multipart/"+subtype, map[string]string{"boundary": writer.Boundary()})},
+		}
+		return writePart(buf, w, header, body.Bytes())
+	}
+}
+
+/* textPart: a text body in quoted printable */
+func textPart(contentType string, text string) mimePart {
+	return func(buf *bytes.Buffer, w *multipart.Writer) error {
+		body := bytes.NewBuffer(nil)
+		qp := quotedprintable.NewWriter(body)
+		if _, err := qp.Write([]byte(strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\n", "\r\n"))); err != nil {
+			return err
+		}
+		if err := qp.Close(); err != nil {
+			return err
+		}
+		header := textproto.MIMEHeader{
+			"Content-Type":              {contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		}
+		return writePart(buf, w, header, body.Bytes())
+	}
+}
+
+/* filePart: a file in base64 with lines of 76 characters, typed by its extension or else by its content */
+func filePart(fileName string, content []byte, header textproto.MIMEHeader) mimePart {
+	return func(buf *bytes.Buffer, w *multipart.Writer) error {
+		contentType := mime.TypeByExtension(filepath.Ext(fileName))
+		if contentType == "" {
+			contentType = http.DetectContentType(content)
+		}
+		header.Set("Content-Type", contentType)
+		header.Set("Content-Transfer-Encoding", "base64")
+		encoded := base64.StdEncoding.EncodeToString(content)
+		body := bytes.NewBuffer(nil)
+		for len(encoded) > 76 {
+			body.WriteString(encoded[:76] + "\r\n")
+			encoded = encoded[76:]
+		}
+		body.WriteString(encoded)
+		return writePart(buf, w, header, body.Bytes())
+	}
+}
+
+/* writePart: write a part into its parent multipart, or as the top level body after the message headers */
+func writePart(buf *bytes.Buffer, w *multipart.Writer, header textproto.MIMEHeader, body []byte) error {
+	var out io.Writer = buf
+	if w != nil {
+		part, err := w.CreatePart(header)
+		if err != nil {
+			return err
+		}
+		out = part
+	} else {
+		for _, key := range sortedKeys(header) {
+			writeHeader(buf, key, header.Get(key))
+		}
+		buf.WriteString("\r\n")
+	}
+	_, err := out.Write(body)
+	return err
+}
+
+/* writeHeader: write one header line ended by CRLF */
+func writeHeader(buf *bytes.Buffer, key string, value string) {
+	buf.WriteString(fmt.Sprintf("%s: %s\r\n", key, value))
+}
+
+/* mailDomain: the domain of an address, used to build the Message-ID */
+func mailDomain(address string) string {
+	if at := strings.LastIndex(address, "@"); at != -1 {
+		return strings.Trim(address[at+1:], "> ")
+	}
+	return "netevent"
+}
+
+/* sortedKeys: the keys of a map in order so the message is the same on every build */
+func sortedKeys(m interface{}) []string {
+	keys := make([]string, 0)
+	switch v := m.(type) {
+	case map[string][]byte:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	case map[string]*InlineFile:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	case textproto.MIMEHeader:
+		for key := range v {
+			keys = append(keys, key)
 		}
-		buf.WriteString("--")
 	}
-	return buf.Bytes()
+	sort.Strings(keys)
+	return keys
 }
@@ -0,0 +1,232 @@
+package models
+
+import (
+	"bytes"
+	"flag"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+/* goldenMail: a mail with fixed Date, Message-ID and boundaries so it builds to the same bytes every time */
+func goldenMail() *Mail {
+	m := NewMail()
+	m.From = "NetEvent <noreply@netevent.test>"
+	m.To = []string{"lan.nguyen@example.com"}
+	m.BCC = []string{"audit@netevent.test"}
+	m.Subject = "Thư mời: Hội thảo Go"
+	m.Date = time.Date(2026, time.March, 2, 9, 30, 0, 0, time.FixedZone("ICT", 7*60*60))
+	m.MessageID = "<golden@netevent.test>"
+	m.Boundary = "golden"
+	m.Body = "Xin chào Lan,\nhẹn gặp bạn tại hội thảo."
+	return m
+}
+
+func TestMailToBytesGolden(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func() *Mail
+	}{
+		{
+			name:  "plain",
+			build: goldenMail,
+		},
+		{
+			name: "alternative",
+			build: func() *Mail {
+				m := goldenMail()
+				m.MailTemplate = &MailTemplate{
+					Template: template.Must(template.New("reminder").Parse(`<p>Xin chào {{.Name}},</p>`)),
+					Data:     map[string]string{"Name": "Lan"},
+				}
+				return m
+			},
+		},
+		{
+			name: "invitation",
+			build: func() *Mail {
+				m := goldenMail()
+				m.MailTemplate = &MailTemplate{
+					Template: template.Must(template.New("invitation").Parse(`<p>Xin chào {{.Name}},</p><img src="cid:qrCode">`)),
+					Data:     map[string]string{"Name": "Lan"},
+				}
+				m.AttachInline("qrCode", "qrCode.png", []byte("\x89PNG\r\n\x1a\nqr"))
+				m.AttachByteFile("event.ics", []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"))
+				return m
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.build().ToBytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden := filepath.Join("testdata", test.name+".eml")
+			if *update {
+				if err := ioutil.WriteFile(golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("message differs from %s, run go test -update to rewrite it\ngot:\n%s", golden, got)
+			}
+		})
+	}
+}
+
+func TestMailToBytesLineEndings(t *testing.T) {
+	m := goldenMail()
+	m.Body = "first line\nsecond line\r\nthird line"
+	got, err := m.ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, line := range bytes.Split(got, []byte("\r\n")) {
+		if bytes.ContainsAny(line, "\r\n") {
+			t.Errorf("line %d has a bare CR or LF: %q", i+1, line)
+		}
+	}
+}
+
+func TestMailToBytesHeaders(t *testing.T) {
+	got, err := goldenMail().ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(got))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bcc, ok := msg.Header["Bcc"]; ok {
+		t.Errorf("Bcc header is written: %v", bcc)
+	}
+	if bytes.Contains(got, []byte("audit@netevent.test")) {
+		t.Error("Bcc receiver appears in the message")
+	}
+	subject := msg.Header.Get("Subject")
+	if !strings.HasPrefix(subject, "=?utf-8?q?") {
+		t.Errorf("subject is not Q-encoded: %s", subject)
+	}
+	decoded, err := new(mime.WordDecoder).DecodeHeader(subject)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded != "Thư mời: Hội thảo Go" {
+		t.Errorf("subject decodes to %q", decoded)
+	}
+	if date := msg.Header.Get("Date"); date != "Mon, 02 Mar 2026 09:30:00 +0700" {
+		t.Errorf("Date is %q", date)
+	}
+	if id := msg.Header.Get("Message-Id"); id != "<golden@netevent.test>" {
+		t.Errorf("Message-ID is %q", id)
+	}
+}
+
+func TestMailToBytesNesting(t *testing.T) {
+	m := goldenMail()
+	m.MailTemplate = &MailTemplate{
+		Template: template.Must(template.New("invitation").Parse(`<img src="cid:qrCode">`)),
+	}
+	m.AttachInline("qrCode", "qrCode.png", []byte("qr"))
+	m.AttachByteFile("event.ics", []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"))
+	got, err := m.ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(got))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mixed := readParts(t, msg.Header.Get("Content-Type"), msg.Body, "multipart/mixed")
+	if len(mixed) != 2 {
+		t.Fatalf("mixed has %d parts, want the body and one attachment", len(mixed))
+	}
+	if disposition := mixed[1].header.Get("Content-Disposition"); !strings.HasPrefix(disposition, "attachment") {
+		t.Errorf("second mixed part is not an attachment: %s", disposition)
+	}
+
+	related := readParts(t, mixed[0].header.Get("Content-Type"), bytes.NewReader(mixed[0].body), "multipart/related")
+	if len(related) != 2 {
+		t.Fatalf("related has %d parts, want the body and one inline file", len(related))
+	}
+	if id := related[1].header.Get("Content-Id"); id != "<qrCode>" {
+		t.Errorf("inline part has Content-Id %q", id)
+	}
+	if disposition := related[1].header.Get("Content-Disposition"); !strings.HasPrefix(disposition, "inline") {
+		t.Errorf("inline part has disposition %q", disposition)
+	}
+
+	alternative := readParts(t, related[0].header.Get("Content-Type"), bytes.NewReader(related[0].body), "multipart/alternative")
+	if len(alternative) != 2 {
+		t.Fatalf("alternative has %d parts, want text and html", len(alternative))
+	}
+	for i, want := range []string{"text/plain", "text/html"} {
+		if mediaType, _, _ := mime.ParseMediaType(alternative[i].header.Get("Content-Type")); mediaType != want {
+			t.Errorf("alternative part %d is %s, want %s", i+1, mediaType, want)
+		}
+	}
+}
+
+func TestMailToBytesWithoutAttachments(t *testing.T) {
+	got, err := goldenMail().ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(got))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(msg.Header.Get("Content-Type")); mediaType != "text/plain" {
+		t.Errorf("a text only mail is %s, want text/plain without multipart", mediaType)
+	}
+}
+
+/* rawPart: the headers and the undecoded body of one part */
+type rawPart struct {
+	header textproto.MIMEHeader
+	body   []byte
+}
+
+/* readParts: the parts of a multipart body, failing when it is not of the wanted type */
+func readParts(t *testing.T, contentType string, body io.Reader, want string) []rawPart {
+	t.Helper()
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mediaType != want {
+		t.Fatalf("part is %s, want %s", mediaType, want)
+	}
+	parts := make([]rawPart, 0)
+	reader := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			return parts
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := ioutil.ReadAll(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts = append(parts, rawPart{header: part.Header, body: content})
+	}
+}
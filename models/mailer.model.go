@@ -0,0 +1,207 @@
+package models
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/* Mailer: a transport that delivers mails */
+type Mailer interface {
+	Send(m *Mail) error
+}
+
+/* Transports a mailer can be built for */
+var (
+	MailTransportSmtp   = "smtp"
+	MailTransportFile   = "file"
+	MailTransportMemory = "memory"
+)
+
+/* Security of the SMTP connection */
+var (
+	SmtpStartTLS = "starttls"
+	SmtpTLS      = "tls"
+	SmtpNone     = "none"
+)
+
+/* MailerConfig: which transport to use and how to reach it */
+type MailerConfig struct {
+	Transport string
+	Host      string
+	Port      string
+	Username  string
+	Password  string
+	From      string
+	Security  string
+	Dir       string
+}
+
+/* MailerConfigFromEnv: read the mailer config, SMTP with STARTTLS is used by default */
+func MailerConfigFromEnv() MailerConfig {
+	config := MailerConfig{
+		Transport: os.Getenv("MAIL_TRANSPORT"),
+		Host:      os.Getenv("PROJECT_EMAIL_HOST"),
+		Port:      os.Getenv("PROJECT_EMAIL_PORT"),
+		Username:  os.Getenv("PROJECT_EMAIL"),
+		Password:  os.Getenv("PROJECT_EMAIL_PASSWORD"),
+		From:      os.Getenv("PROJECT_EMAIL"),
+		Security:  os.Getenv("PROJECT_EMAIL_SECURITY"),
+		Dir:       os.Getenv("MAIL_DIR"),
+	}
+	if config.Transport == "" {
+		config.Transport = MailTransportSmtp
+	}
+	if config.Security == "" {
+		config.Security = SmtpStartTLS
+	}
+	if config.Dir == "" {
+		config.Dir = "mails"
+	}
+	return config
+}
+
+/* NewMailer: build the mailer of the configured transport */
+func NewMailer(config MailerConfig) (Mailer, error) {
+	switch config.Transport {
+	case MailTransportSmtp:
+		if config.Host == "" || config.Port == "" {
+			return nil, errors.New("smtp mailer needs a host and a port")
+		}
+		if config.Security != SmtpStartTLS && config.Security != SmtpTLS && config.Security != SmtpNone {
+			return nil, fmt.Errorf("unknown smtp security %s", config.Security)
+		}
+		return &SmtpMailer{Config: config}, nil
+	case MailTransportFile:
+		if err := os.MkdirAll(config.Dir, 0755); err != nil {
+			return nil, err
+		}
+		return &FileMailer{Dir: config.Dir, From: config.From}, nil
+	case MailTransportMemory:
+		return &MemoryMailer{}, nil
+	}
+	return nil, fmt.Errorf("unknown mail transport %s", config.Transport)
+}
+
+/* SmtpMailer: deliver mails to an SMTP server over STARTTLS, implicit TLS or plain text */
+type SmtpMailer struct {
+	Config MailerConfig
+}
+
+/* Send: deliver one mail to every To, Cc and Bcc receiver */
+func (s *SmtpMailer) Send(m *Mail) error {
+	if m.From == "" {
+		m.From = s.Config.From
+	}
+	message, err := m.ToBytes()
+	if err != nil {
+		return err
+	}
+	addr := net.JoinHostPort(s.Config.Host, s.Config.Port)
+	tlsConfig := &tls.Config{ServerName: s.Config.Host}
+	var conn net.Conn
+	if s.Config.Security == SmtpTLS {
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: 10 * time.Second}, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = net.DialTimeout("tcp", addr, 10*time.Second)
+	}
+	if err != nil {
+		return err
+	}
+	client, err := smtp.NewClient(conn, s.Config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if s.Config.Security == SmtpStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("smtp server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if s.Config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.Config.Username, s.Config.Password, s.Config.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(s.Config.From); err != nil {
+		return err
+	}
+	for _, receivers := range [][]string{m.To, m.CC, m.BCC} {
+		for _, receiver := range receivers {
+			if err := client.Rcpt(receiver); err != nil {
+				return err
+			}
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+/* FileMailer: drop every mail as an .eml file into a directory, for local development */
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+/* Send: write the mail to a new file */
+func (f *FileMailer) Send(m *Mail) error {
+	if m.From == "" {
+		m.From = f.From
+	}
+	message, err := m.ToBytes()
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102T150405"), primitive.NewObjectID().Hex())
+	return os.WriteFile(filepath.Join(f.Dir, name), message, 0644)
+}
+
+/* MemoryMailer: keep the mails in memory so tests can inspect them */
+type MemoryMailer struct {
+	mu    sync.Mutex
+	mails []*Mail
+}
+
+/* Send: record the mail */
+func (r *MemoryMailer) Send(m *Mail) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mails = append(r.mails, m)
+	return nil
+}
+
+/* Sent: the mails recorded so far */
+func (r *MemoryMailer) Sent() []*Mail {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append(make([]*Mail, 0), r.mails...)
+}
+
+/* Reset: forget the recorded mails */
+func (r *MemoryMailer) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mails = nil
+}
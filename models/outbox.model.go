@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var CollectionOutboxName = "outbox"
+
+/* Kinds of mail sent through the outbox */
+var (
+	OutboxInvitation   = "INVITATION"
+	OutboxPromotion    = "PROMOTION"
+	OutboxReminder     = "REMINDER"
+	OutboxTaskReminder = "TASK_REMINDER"
+	OutboxCancellation = "CANCELLATION"
+	OutboxApproval     = "APPROVAL"
+)
+
+/* OutboxWithQrCode: whether the mails of a kind carry the QR code of the participant */
+func OutboxWithQrCode(kind string) bool {
+	return kind == OutboxInvitation || kind == OutboxPromotion || kind == OutboxReminder
+}
+
+/* Delivery status of an outbox message */
+var (
+	OutboxQueued = "QUEUED"
+	OutboxSent   = "SENT"
+	OutboxFailed = "FAILED"
+	OutboxDead   = "DEAD"
+)
+
+/* OutboxMessage: a mail waiting to be sent by the outbox worker */
+type OutboxMessage struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt     time.Time          `bson:"updatedAt" json:"updatedAt"`
+	Kind          string             `bson:"kind" json:"kind"`
+	Participant   primitive.ObjectID `bson:"participant,omitempty" json:"participant"`
+	Event         primitive.ObjectID `bson:"event,omitempty" json:"event"`
+	User          primitive.ObjectID `bson:"user,omitempty" json:"user"`
+	Task          primitive.ObjectID `bson:"task,omitempty" json:"task"`
+	To            string             `bson:"to" json:"to"`
+	Status        string             `bson:"status" json:"status"`
+	Attempts      int                `bson:"attempts" json:"attempts"`
+	NextAttemptAt time.Time          `bson:"nextAttemptAt" json:"nextAttemptAt"`
+	LockedUntil   *time.Time         `bson:"lockedUntil" json:"lockedUntil"`
+	//claim of the worker holding the lease, its writes are ignored once another worker claimed the message
+	LockedBy  string     `bson:"lockedBy" json:"lockedBy"`
+	LastError string     `bson:"lastError" json:"lastError"`
+	SentAt    *time.Time `bson:"sentAt" json:"sentAt"`
+}
+
+/* NewOutboxMessage: a message for a participant, ready to be sent right away */
+func NewOutboxMessage(kind string, participant *Participant) *OutboxMessage {
+	currentTime := time.Now()
+	return &OutboxMessage{
+		ID:            primitive.NewObjectID(),
+		CreatedAt:     currentTime,
+		UpdatedAt:     currentTime,
+		Kind:          kind,
+		Participant:   participant.ID,
+		Event:         participant.Event,
+		To:            participant.Email,
+		Status:        OutboxQueued,
+		NextAttemptAt: currentTime,
+	}
+}
+
+/* NewUserOutboxMessage: a message about an event for a user such as its owner, ready to be sent right away */
+func NewUserOutboxMessage(kind string, event *Event, user *User) *OutboxMessage {
+	currentTime := time.Now()
+	return &OutboxMessage{
+		ID:            primitive.NewObjectID(),
+		CreatedAt:     currentTime,
+		UpdatedAt:     currentTime,
+		Kind:          kind,
+		Event:         event.ID,
+		User:          user.ID,
+		To:            user.Email,
+		Status:        OutboxQueued,
+		NextAttemptAt: currentTime,
+	}
+}
+
+/* NewTaskOutboxMessage: a message for the user assigned to a task, ready to be sent right away */
+func NewTaskOutboxMessage(kind string, task *Task, user *User) *OutboxMessage {
+	currentTime := time.Now()
+	return &OutboxMessage{
+		ID:            primitive.NewObjectID(),
+		CreatedAt:     currentTime,
+		UpdatedAt:     currentTime,
+		Kind:          kind,
+		Event:         task.Event,
+		User:          user.ID,
+		Task:          task.ID,
+		To:            user.Email,
+		Status:        OutboxQueued,
+		NextAttemptAt: currentTime,
+	}
+}
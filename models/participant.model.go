@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -8,12 +9,19 @@ import (
 
 var CollectionParticipantName = "participants"
 
+/* Registration status of a participant, records without a status are registered */
+var (
+	ParticipantRegistered = "REGISTERED"
+	ParticipantWaitlisted = "WAITLISTED"
+)
+
 type Participant struct {
 	ID                   primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	CreatedAt            time.Time          `bson:"createdAt" json:"createdAt"`
 	UpdatedAt            time.Time          `bson:"updatedAt" json:"updatedAt"`
 	IsValid              bool               `bson:"isValid" json:"isValid"`
 	IsAttended           bool               `bson:"isAttended" json:"isAttended"`
+	Status               string             `bson:"status" json:"status"`
 	Event                primitive.ObjectID `bson:"event" json:"event"`
 	Email                string             `bson:"email" json:"email"`
 	Name                 string             `bson:"name" json:"name"`
@@ -23,4 +31,53 @@ type Participant struct {
 	Phone                string             `bson:"phone" json:"phone"`
 	DOB                  time.Time          `bson:"dob" json:"dob"`
 	ExpectedGraduateDate time.Time          `bson:"expectedGraduateDate" json:"expectedGraduateDate"`
+	Answers              []*FieldAnswer     `bson:"answers" json:"answers"`
+	CheckIn              *CheckIn           `bson:"checkIn" json:"checkIn"`
+	//version of the event customize fields the answers were given against
+	CustomizeFieldsVersion int `bson:"customizeFieldsVersion" json:"customizeFieldsVersion"`
+}
+
+/* CheckIn: when and by whom the QR code of a participant was scanned */
+type CheckIn struct {
+	At     time.Time          `bson:"at" json:"at"`
+	Staff  primitive.ObjectID `bson:"staff" json:"staff"`
+	Device string             `bson:"device" json:"device"`
+}
+
+/* FieldAnswer: the answer of a participant to one customize field of the event */
+type FieldAnswer struct {
+	Name   string   `bson:"name" json:"name"`
+	Values []string `bson:"values" json:"values"`
+}
+
+/* IsWaitlisted: check whether the participant is still waiting for a seat */
+func (p *Participant) IsWaitlisted() bool {
+	return p.Status == ParticipantWaitlisted
+}
+
+/* RegistrationStatus: the status of the participant, registered when none was stored */
+func (p *Participant) RegistrationStatus() string {
+	if p.Status == "" {
+		return ParticipantRegistered
+	}
+	return p.Status
+}
+
+/* NormalizeEmail: the stored form of an email, emails differing only in case or spaces are the same */
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+/*
+PreferredRegistration: whether a registration is kept over another one of the same email and event,
+a checked in registration first, then one holding a seat, then the earliest
+*/
+func PreferredRegistration(a *Participant, b *Participant) bool {
+	if (a.CheckIn != nil) != (b.CheckIn != nil) {
+		return a.CheckIn != nil
+	}
+	if a.IsWaitlisted() != b.IsWaitlisted() {
+		return !a.IsWaitlisted()
+	}
+	return a.CreatedAt.Before(b.CreatedAt)
 }
@@ -0,0 +1,24 @@
+package models
+
+/* Modes a participant import runs in */
+var (
+	ImportDryRun = "DRY_RUN"
+	ImportCommit = "COMMIT"
+)
+
+/* ImportRowError: why a row of an import was rejected, rows are numbered like the lines of the file */
+type ImportRowError struct {
+	Row      int
+	Email    string
+	Messages []string
+}
+
+/* ParticipantImport: the outcome of an import, a dry run checks every row without saving any */
+type ParticipantImport struct {
+	Mode           string
+	Total          int
+	Valid          int
+	Imported       []*Participant
+	Errors         []*ImportRowError
+	IgnoredColumns []string
+}
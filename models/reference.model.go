@@ -0,0 +1,81 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+/* ReferenceAction: what happens to the referencing records when a referenced record is deleted */
+type ReferenceAction string
+
+const (
+	//ReferenceRestrict: refuse the delete while any record still references the target
+	ReferenceRestrict ReferenceAction = "RESTRICT"
+	//ReferenceCascade: delete the referencing records together with the target
+	ReferenceCascade ReferenceAction = "CASCADE"
+	//ReferenceNullify: clear the reference (or pull it out of an array field)
+	ReferenceNullify ReferenceAction = "NULLIFY"
+)
+
+/* ReferenceRule: a declared relation between a referenced collection and the field that points to it */
+type ReferenceRule struct {
+	Collection    string
+	RefCollection string
+	Field         string
+	Many          bool
+	Action        ReferenceAction
+}
+
+/* ReferenceRules: every relation between collections and how a delete is propagated through it */
+var ReferenceRules = []ReferenceRule{
+	{Collection: CollectionEventName, RefCollection: CollectionTaskName, Field: "event", Action: ReferenceCascade},
+	{Collection: CollectionEventName, RefCollection: CollectionFacilityHistoryName, Field: "event", Action: ReferenceCascade},
+	{Collection: CollectionEventName, RefCollection: CollectionSessionName, Field: "event", Action: ReferenceCascade},
+	{Collection: CollectionEventName, RefCollection: CollectionParticipantName, Field: "event", Action: ReferenceCascade},
+	//expenses come before their budget items so they do not block the cascade
+	{Collection: CollectionEventName, RefCollection: CollectionExpenseName, Field: "event", Action: ReferenceCascade},
+	{Collection: CollectionEventName, RefCollection: CollectionBudgetItemName, Field: "event", Action: ReferenceCascade},
+	{Collection: CollectionBudgetItemName, RefCollection: CollectionExpenseName, Field: "budgetItem", Action: ReferenceRestrict},
+	{Collection: CollectionParticipantName, RefCollection: CollectionOutboxName, Field: "participant", Action: ReferenceCascade},
+	{Collection: CollectionTaskName, RefCollection: CollectionOutboxName, Field: "task", Action: ReferenceCascade},
+	{Collection: CollectionUserName, RefCollection: CollectionOutboxName, Field: "user", Action: ReferenceCascade},
+	{Collection: CollectionUserName, RefCollection: CollectionEventName, Field: "owner", Action: ReferenceRestrict},
+	{Collection: CollectionUserName, RefCollection: CollectionEventName, Field: "reviewer", Action: ReferenceNullify},
+	{Collection: CollectionUserName, RefCollection: CollectionTaskName, Field: "user", Action: ReferenceRestrict},
+	{Collection: CollectionEventTypeName, RefCollection: CollectionEventName, Field: "eventType", Action: ReferenceRestrict},
+	{Collection: CollectionFacilityName, RefCollection: CollectionFacilityHistoryName, Field: "facility", Action: ReferenceRestrict},
+	{Collection: CollectionFacilityTypeName, RefCollection: CollectionFacilityName, Field: "type", Action: ReferenceRestrict},
+	{Collection: CollectionUserName, RefCollection: CollectionFacilityHistoryName, Field: "checkedOut.staff", Action: ReferenceRestrict},
+	{Collection: CollectionUserName, RefCollection: CollectionFacilityHistoryName, Field: "returned.staff", Action: ReferenceRestrict},
+	{Collection: CollectionUserName, RefCollection: CollectionParticipantName, Field: "checkIn.staff", Action: ReferenceRestrict},
+	{Collection: CollectionUserName, RefCollection: CollectionExpenseName, Field: "submittedBy", Action: ReferenceRestrict},
+	{Collection: CollectionUserName, RefCollection: CollectionExpenseName, Field: "review.reviewer", Action: ReferenceRestrict},
+	{Collection: CollectionTaskName, RefCollection: CollectionEventName, Field: "tasks", Many: true, Action: ReferenceNullify},
+	{Collection: CollectionFacilityHistoryName, RefCollection: CollectionEventName, Field: "facilityHistories", Many: true, Action: ReferenceNullify},
+	{Collection: CollectionParticipantName, RefCollection: CollectionSessionName, Field: "attendees", Many: true, Action: ReferenceNullify},
+	{Collection: CollectionFacilityName, RefCollection: CollectionSessionName, Field: "room", Action: ReferenceRestrict},
+	{Collection: CollectionUserName, RefCollection: CollectionEventTemplateName, Field: "owner", Action: ReferenceRestrict},
+	{Collection: CollectionUserName, RefCollection: CollectionEventTemplateName, Field: "tasks.user", Action: ReferenceRestrict},
+	{Collection: CollectionEventTypeName, RefCollection: CollectionEventTemplateName, Field: "eventType", Action: ReferenceRestrict},
+	{Collection: CollectionFacilityName, RefCollection: CollectionEventTemplateName, Field: "facilities.facility", Action: ReferenceRestrict},
+}
+
+/* DeleteImpact: the records of one collection affected by a delete through one rule */
+type DeleteImpact struct {
+	Collection string
+	Field      string
+	Many       bool
+	Action     ReferenceAction
+	IDs        []primitive.ObjectID
+	References []primitive.ObjectID
+}
+
+/* DeleteReport: everything a delete would touch, and whether it is allowed at all */
+type DeleteReport struct {
+	Collection string
+	ID         primitive.ObjectID
+	Blockers   []*DeleteImpact
+	Impacts    []*DeleteImpact
+}
+
+/* Allowed: a delete is allowed when no restrict rule is violated */
+func (r *DeleteReport) Allowed() bool {
+	return len(r.Blockers) == 0
+}
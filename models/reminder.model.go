@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var CollectionReminderName = "reminders"
+
+/* Reminder: records that a reminder was queued, so it is queued only once */
+type Reminder struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	Kind      string             `bson:"kind" json:"kind"`
+	//the participant or the task reminded of
+	Target primitive.ObjectID `bson:"target" json:"target"`
+	//how long before the start the reminder is due, like 24h0m0s
+	Offset string             `bson:"offset" json:"offset"`
+	Outbox primitive.ObjectID `bson:"outbox" json:"outbox"`
+}
+
+var CollectionLeaseName = "leases"
+
+/* Lease: a named job owned by one server instance until the lease runs out */
+type Lease struct {
+	ID          string    `bson:"_id" json:"id"`
+	Owner       string    `bson:"owner" json:"owner"`
+	LockedUntil time.Time `bson:"lockedUntil" json:"lockedUntil"`
+}
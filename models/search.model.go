@@ -0,0 +1,66 @@
+package models
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/* Types of search results */
+var (
+	SearchEvent       = "EVENT"
+	SearchParticipant = "PARTICIPANT"
+)
+
+/* SearchField: a searched field and the weight of its matches, in the text index as well as in the ranking */
+type SearchField struct {
+	Name   string
+	Weight int
+}
+
+/* SearchFields: the searched fields of every type of result, the first one gives the title */
+var SearchFields = map[string][]SearchField{
+	SearchEvent:       {{Name: "name", Weight: 10}, {Name: "tags", Weight: 5}, {Name: "location", Weight: 3}, {Name: "description", Weight: 1}},
+	SearchParticipant: {{Name: "name", Weight: 10}, {Name: "email", Weight: 8}, {Name: "school", Weight: 3}},
+}
+
+/* SearchCollections: the collection searched for every type of result */
+var SearchCollections = map[string]string{
+	SearchEvent:       CollectionEventName,
+	SearchParticipant: CollectionParticipantName,
+}
+
+/* SearchHit: an event or a participant found by a search */
+type SearchHit struct {
+	Type string
+	ID   primitive.ObjectID
+	//relevance given by the text index, 0 for records found by prefix or typo only
+	TextScore float64
+	Score     float64
+	Title     string
+	//the field the snippet is taken from
+	Field       string
+	Snippet     string
+	Event       *Event
+	Participant *Participant
+}
+
+/* FieldValues: the text of every searched field of the record */
+func (h *SearchHit) FieldValues() map[string]string {
+	switch {
+	case h.Event != nil:
+		return map[string]string{
+			"name":        h.Event.Name,
+			"tags":        strings.Join(h.Event.Tags, ", "),
+			"location":    h.Event.Location,
+			"description": h.Event.Description,
+		}
+	case h.Participant != nil:
+		return map[string]string{
+			"name":   h.Participant.Name,
+			"email":  h.Participant.Email,
+			"school": h.Participant.School,
+		}
+	}
+	return map[string]string{}
+}
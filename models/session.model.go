@@ -0,0 +1,57 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var CollectionSessionName = "sessions"
+
+/* Speaker: a person presenting a session, not necessarily a user */
+type Speaker struct {
+	Name  string `bson:"name" json:"name"`
+	Email string `bson:"email" json:"email"`
+}
+
+/*
+SameAs: whether two speakers are the same person.
+Two known emails decide, otherwise the names are compared so a speaker entered once with and once without an email still matches
+*/
+func (s *Speaker) SameAs(other *Speaker) bool {
+	email, otherEmail := strings.TrimSpace(s.Email), strings.TrimSpace(other.Email)
+	if email != "" && otherEmail != "" {
+		return strings.EqualFold(email, otherEmail)
+	}
+	return strings.EqualFold(strings.Join(strings.Fields(s.Name), " "), strings.Join(strings.Fields(other.Name), " "))
+}
+
+/* Session: a talk, workshop or other item of the agenda of an event */
+type Session struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	CreatedAt   time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time           `bson:"updatedAt" json:"updatedAt"`
+	Event       primitive.ObjectID  `bson:"event" json:"event"`
+	Title       string              `bson:"title" json:"title"`
+	Description string              `bson:"description" json:"description"`
+	Track       string              `bson:"track" json:"track"`
+	StartDate   time.Time           `bson:"startDate" json:"startDate"`
+	EndDate     time.Time           `bson:"endDate" json:"endDate"`
+	Room        *primitive.ObjectID `bson:"room" json:"room"`
+	Speakers    []*Speaker          `bson:"speakers" json:"speakers"`
+	//0 for no limit
+	Capacity int `bson:"capacity" json:"capacity"`
+	//participants registered for the session
+	Attendees []primitive.ObjectID `bson:"attendees" json:"attendees"`
+}
+
+/* Overlaps: the two sessions share some time, one ending when the other starts does not count */
+func (s *Session) Overlaps(other *Session) bool {
+	return s.StartDate.Before(other.EndDate) && other.StartDate.Before(s.EndDate)
+}
+
+/* IsFull: every seat of the session is taken */
+func (s *Session) IsFull() bool {
+	return s.Capacity > 0 && len(s.Attendees) >= s.Capacity
+}
@@ -0,0 +1,177 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/* DailyCount: the registrations of one day, and of every day up to it */
+type DailyCount struct {
+	Date       time.Time `bson:"_id" json:"date"`
+	Count      int       `bson:"count" json:"count"`
+	Cumulative int       `bson:"-" json:"cumulative"`
+}
+
+/* BreakdownCount: the number of participants sharing a value */
+type BreakdownCount struct {
+	Value string `bson:"_id" json:"value"`
+	Count int    `bson:"count" json:"count"`
+}
+
+/* ParticipantCounts: participants of an event by state, waitlisted ones included in total but not in valid */
+type ParticipantCounts struct {
+	Total      int `bson:"total" json:"total"`
+	Waitlisted int `bson:"waitlisted" json:"waitlisted"`
+	Valid      int `bson:"valid" json:"valid"`
+	Attended   int `bson:"attended" json:"attended"`
+}
+
+/* ParticipantStatistic: the participant part of the statistics of an event */
+type ParticipantStatistic struct {
+	Counts        ParticipantCounts `bson:"counts" json:"counts"`
+	Registrations []*DailyCount     `bson:"registrations" json:"registrations"`
+	BySchool      []*BreakdownCount `bson:"bySchool" json:"bySchool"`
+	ByMajor       []*BreakdownCount `bson:"byMajor" json:"byMajor"`
+	ByAcademic    []*BreakdownCount `bson:"byAcademic" json:"byAcademic"`
+}
+
+/* TaskCounts: tasks have no status, they are counted by their dates, a task has elapsed once its end date has passed */
+type TaskCounts struct {
+	Total      int `bson:"total" json:"total"`
+	Elapsed    int `bson:"elapsed" json:"elapsed"`
+	InProgress int `bson:"inProgress" json:"inProgress"`
+}
+
+/* FacilityUsage: how a facility was booked and moved for an event */
+type FacilityUsage struct {
+	Facility   primitive.ObjectID `bson:"_id" json:"facility"`
+	Bookings   int                `bson:"bookings" json:"bookings"`
+	Quantity   int                `bson:"quantity" json:"quantity"`
+	CheckedOut int                `bson:"checkedOut" json:"checkedOut"`
+	Returned   int                `bson:"returned" json:"returned"`
+}
+
+/* EventStatistic: everything reported about one event */
+type EventStatistic struct {
+	Event        *Event
+	Participants ParticipantStatistic
+	Tasks        TaskCounts
+	Facilities   []*FacilityUsage
+}
+
+/* Registered: the participants holding a seat */
+func (s *EventStatistic) Registered() int {
+	return s.Participants.Counts.Total - s.Participants.Counts.Waitlisted
+}
+
+/* Invalid: the participants holding a seat who are not validated yet */
+func (s *EventStatistic) Invalid() int {
+	return s.Registered() - s.Participants.Counts.Valid
+}
+
+/* AttendanceRate: the share of registered participants who attended */
+func (s *EventStatistic) AttendanceRate() float64 {
+	if s.Registered() == 0 {
+		return 0
+	}
+	return float64(s.Participants.Counts.Attended) / float64(s.Registered())
+}
+
+/* CapacityFill: the share of seats taken, nil when the event has no limit */
+func (s *EventStatistic) CapacityFill() *float64 {
+	if s.Event.MaxParticipants <= 0 {
+		return nil
+	}
+	fill := float64(s.Registered()) / float64(s.Event.MaxParticipants)
+	return &fill
+}
+
+/* TaskElapsedRate: the share of tasks whose end date has passed, it says nothing about the work being done */
+func (s *EventStatistic) TaskElapsedRate() float64 {
+	if s.Tasks.Total == 0 {
+		return 0
+	}
+	return float64(s.Tasks.Elapsed) / float64(s.Tasks.Total)
+}
+
+/* EventTypeMonthCount: the events of one type starting in one month */
+type EventTypeMonthCount struct {
+	EventType primitive.ObjectID `bson:"eventType" json:"eventType"`
+	Month     time.Time          `bson:"month" json:"month"`
+	Count     int                `bson:"count" json:"count"`
+}
+
+/* EventTotals: the number of the events of a period */
+type EventTotals struct {
+	Count int `bson:"count" json:"count"`
+}
+
+/* CurrencyBudget: the budget items of the events of a period in one currency, in minor units */
+type CurrencyBudget struct {
+	Currency string `bson:"_id" json:"currency"`
+	Total    int64  `bson:"total" json:"total"`
+	Events   int    `bson:"events" json:"events"`
+}
+
+/* Average: the planned budget of one of the events, rounded to the minor unit */
+func (b *CurrencyBudget) Average() int64 {
+	if b.Events == 0 {
+		return 0
+	}
+	return int64(math.Round(float64(b.Total) / float64(b.Events)))
+}
+
+/* ApprovalTurnaround: how long approved events waited from creation to approval, in hours */
+type ApprovalTurnaround struct {
+	Approved     int     `bson:"approved" json:"approved"`
+	AverageHours float64 `bson:"averageHours" json:"averageHours"`
+	MinHours     float64 `bson:"minHours" json:"minHours"`
+	MaxHours     float64 `bson:"maxHours" json:"maxHours"`
+}
+
+/* AttendanceMonth: registered and attended participants of the events starting in one month */
+type AttendanceMonth struct {
+	Month      time.Time `bson:"_id" json:"month"`
+	Registered int       `bson:"registered" json:"registered"`
+	Attended   int       `bson:"attended" json:"attended"`
+}
+
+/* Rate: the share of registered participants who attended */
+func (a *AttendanceMonth) Rate() float64 {
+	if a.Registered == 0 {
+		return 0
+	}
+	return float64(a.Attended) / float64(a.Registered)
+}
+
+/* StaffAssignment: the number of tasks assigned to a user */
+type StaffAssignment struct {
+	User  primitive.ObjectID `bson:"_id" json:"user"`
+	Tasks int                `bson:"tasks" json:"tasks"`
+}
+
+/* Dashboard: the numbers of every event starting in a period */
+type Dashboard struct {
+	From               time.Time
+	To                 time.Time
+	Events             EventTotals
+	Budgets            []*CurrencyBudget
+	EventsPerType      []*EventTypeMonthCount
+	ApprovalTurnaround ApprovalTurnaround
+	Attendance         []*AttendanceMonth
+	BusiestFacilities  []*FacilityUsage
+	TopStaff           []*StaffAssignment
+}
+
+var CollectionCacheVersionName = "cacheVersions"
+
+//CacheStatistics: the cache of the dashboard
+var CacheStatistics = "statistics"
+
+/* CacheVersion: a counter shared by every instance, a cached value of an older version is outdated */
+type CacheVersion struct {
+	ID      string `bson:"_id" json:"id"`
+	Version int64  `bson:"version" json:"version"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -8,6 +9,13 @@ import (
 
 var CollectionUserName = "users"
 
+/* Roles of a user, an admin holds every role */
+var (
+	UserRoleAdmin    = "ADMIN"
+	UserRoleStaff    = "STAFF"
+	UserRoleReviewer = "REVIEWER"
+)
+
 /* Model Type */
 type User struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
@@ -16,4 +24,21 @@ type User struct {
 	Email     string             `bson:"email" json:"email"`
 	Password  string             `bson:"password" json:"password,omitempty"`
 	Roles     []string           `bson:"roles" json:"roles"`
+	//sha256 of the token of the task feed, the token itself is only shown once
+	CalendarToken string `bson:"calendarToken,omitempty" json:"-"`
+}
+
+/* HasRole: check whether the user holds one of the roles, roles are compared without case */
+func (u *User) HasRole(roles ...string) bool {
+	for _, held := range u.Roles {
+		if strings.EqualFold(held, UserRoleAdmin) {
+			return true
+		}
+		for _, role := range roles {
+			if strings.EqualFold(held, role) {
+				return true
+			}
+		}
+	}
+	return false
 }
@@ -9,6 +9,9 @@ import (
 func SetupServerRoutes(app *gin.Engine) {
 	api := app.Group("/api")
 	setUserRoutes(api)
+	setParticipantRoutes(api)
+	setExportRoutes(api)
+	setCalendarRoutes(api)
 }
 
 /* User Routes */
@@ -16,3 +19,23 @@ func setUserRoutes(api *gin.RouterGroup) {
 	eventRoute := api.Group("/event")
 	eventRoute.GET("/eventStatistic/:id", controllers.GetEventStatistic)
 }
+
+/* Participant Routes */
+func setParticipantRoutes(api *gin.RouterGroup) {
+	participantRoute := api.Group("/participant")
+	participantRoute.POST("/checkIn", controllers.CheckIn)
+}
+
+/* Export Routes */
+func setExportRoutes(api *gin.RouterGroup) {
+	exportRoute := api.Group("/export")
+	exportRoute.GET("/:dataset", controllers.Export)
+}
+
+/* Calendar Routes */
+func setCalendarRoutes(api *gin.RouterGroup) {
+	calendarRoute := api.Group("/calendar")
+	calendarRoute.GET("/events", controllers.PublishedCalendar)
+	calendarRoute.GET("/events/:id", controllers.EventCalendar)
+	calendarRoute.GET("/tasks/:token", controllers.TaskCalendar)
+}
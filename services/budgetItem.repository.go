@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/khanhvtn/netevent-go/database"
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var BudgetItemRepositoryName = "BudgetItemRepositoryName"
+
+type BudgetItemRepository struct {
+	MongoCN *database.MongoInstance
+}
+
+/* createContextAndTargetCol: create and return targeted collection based on collection name */
+func (u *BudgetItemRepository) createContextAndTargetCol(colName string) (col *mongo.Collection,
+	ctx context.Context,
+	cancel context.CancelFunc) {
+	col = u.MongoCN.Db.Collection(colName)
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	return
+}
+
+/* FindAll: get all data based on condition*/
+func (u *BudgetItemRepository) FindAll(condition bson.M) ([]*models.BudgetItem, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionBudgetItemName)
+	defer cancel()
+
+	//create an empty array to store all fields from collection
+	var budgetItems []*models.BudgetItem = make([]*models.BudgetItem, 0)
+
+	//get all record
+	cur, err := collection.Find(ctx, condition)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	//map data to target variable
+	for cur.Next(ctx) {
+		var budgetItem models.BudgetItem
+		cur.Decode(&budgetItem)
+		budgetItems = append(budgetItems, &budgetItem)
+	}
+	//response data to client
+	if budgetItems == nil {
+		return make([]*models.BudgetItem, 0), nil
+	}
+	return budgetItems, nil
+}
+
+/*FindOne: get one record from a collection  */
+func (u *BudgetItemRepository) FindOne(filter bson.M) (*models.BudgetItem, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionBudgetItemName)
+	defer cancel()
+
+	budgetItem := models.BudgetItem{}
+	//Decode record into result
+	if err := collection.FindOne(ctx, filter).Decode(&budgetItem); err != nil {
+		if err == mongo.ErrNoDocuments {
+			//return nil data when id is not existed.
+			return nil, helpers.NewErrNotFound("budget item id is not found")
+		}
+		//return err if there is a system error
+		return nil, err
+	}
+
+	return &budgetItem, nil
+}
+
+/*Create: create a new record to a collection*/
+func (u *BudgetItemRepository) Create(budgetItem *models.BudgetItem) (*models.BudgetItem, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionBudgetItemName)
+	defer cancel()
+
+	if _, err := collection.InsertOne(ctx, budgetItem); err != nil {
+		return nil, err
+	}
+	return budgetItem, nil
+}
+
+/*UpdateOne: update one record from a collection*/
+func (u BudgetItemRepository) UpdateOne(filter bson.M, update bson.M) (*models.BudgetItem, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionBudgetItemName)
+	defer cancel()
+
+	//update budget item information
+	newUpdate := bson.M{"$set": update}
+	updateResult, err := collection.UpdateOne(ctx, filter, newUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return nil, helpers.NewErrNotFound("budget item id is not found")
+	}
+
+	//query the new update
+	budgetItem, errQuery := u.FindOne(filter)
+	if errQuery != nil {
+		return nil, errQuery
+	}
+
+	return budgetItem, nil
+}
+
+//DeleteOne func is to update one record from a collection
+func (u BudgetItemRepository) DeleteOne(filter bson.M) (*models.BudgetItem, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionBudgetItemName)
+	defer cancel()
+
+	budgetItem, errFind := u.FindOne(filter)
+	if errFind != nil {
+		return nil, errFind
+	}
+
+	//delete budget item from database
+	deleteResult, err := collection.DeleteOne(ctx, filter)
+	if err != nil {
+		//response to client if there is an error.
+		return nil, err
+	}
+
+	if deleteResult.DeletedCount == 0 {
+		return nil, helpers.NewErrNotFound("budget item id is not found")
+	}
+
+	return budgetItem, nil
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"regexp"
+	"sort"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var BudgetItemServiceName = "BudgetItemServiceName"
+
+//currencyPattern: an ISO 4217 code
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+type BudgetItemService struct {
+	BudgetItemRepository *BudgetItemRepository
+	ExpenseRepository    *ExpenseRepository
+	EventRepository      *EventRepository
+	ReferenceService     *ReferenceService
+}
+
+/* GetAll: get all data based on condition*/
+func (u *BudgetItemService) GetAll(condition bson.M) ([]*models.BudgetItem, error) {
+	return u.BudgetItemRepository.FindAll(condition)
+}
+
+/*GetOne: get one record from a collection  */
+func (u *BudgetItemService) GetOne(filter bson.M) (*models.BudgetItem, error) {
+	return u.BudgetItemRepository.FindOne(filter)
+}
+
+/*Create: add a budget item to an event*/
+func (u *BudgetItemService) Create(eventId primitive.ObjectID, newBudgetItem model.NewBudgetItem) (*models.BudgetItem, error) {
+	if _, err := u.EventRepository.FindOne(bson.M{"_id": eventId}); err != nil {
+		return nil, err
+	}
+	currentTime := time.Now()
+	budgetItem := &models.BudgetItem{
+		ID:        primitive.NewObjectID(),
+		CreatedAt: currentTime,
+		UpdatedAt: currentTime,
+		Event:     eventId,
+		Category:  newBudgetItem.Category,
+		Planned:   newBudgetItem.Planned,
+		Currency:  newBudgetItem.Currency,
+	}
+	if newBudgetItem.Description != nil {
+		budgetItem.Description = *newBudgetItem.Description
+	}
+	return u.BudgetItemRepository.Create(budgetItem)
+}
+
+/*UpdateOne: update a budget item, its currency is fixed once expenses are recorded against it*/
+func (u *BudgetItemService) UpdateOne(id primitive.ObjectID, update model.UpdateBudgetItem) (*models.BudgetItem, error) {
+	budgetItem, err := u.BudgetItemRepository.FindOne(bson.M{"_id": id})
+	if err != nil {
+		return nil, err
+	}
+	if update.Currency != budgetItem.Currency {
+		expenses, err := u.ExpenseRepository.FindAll(bson.M{"budgetItem": id})
+		if err != nil {
+			return nil, err
+		}
+		if len(expenses) > 0 {
+			return nil, helpers.NewErrConflict("currency cannot change once expenses are recorded")
+		}
+	}
+	description := ""
+	if update.Description != nil {
+		description = *update.Description
+	}
+	return u.BudgetItemRepository.UpdateOne(bson.M{"_id": id}, bson.M{
+		"updatedAt":   time.Now(),
+		"category":    update.Category,
+		"description": description,
+		"planned":     update.Planned,
+		"currency":    update.Currency,
+	})
+}
+
+//DeleteOne func is to delete one record from a collection once its references are resolved
+func (u *BudgetItemService) DeleteOne(filter bson.M) (*models.BudgetItem, error) {
+	budgetItem, err := u.BudgetItemRepository.FindOne(filter)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.ReferenceService.Delete(models.CollectionBudgetItemName, budgetItem.ID, nil); err != nil {
+		return nil, err
+	}
+	return budgetItem, nil
+}
+
+/*Report: the budget items of an event against their expenses, with the totals per currency*/
+func (u *BudgetItemService) Report(eventId primitive.ObjectID) (*models.BudgetReport, error) {
+	budgetItems, err := u.BudgetItemRepository.FindAll(bson.M{"event": eventId})
+	if err != nil {
+		return nil, err
+	}
+	sums, err := u.ExpenseRepository.Sums(bson.M{"event": eventId})
+	if err != nil {
+		return nil, err
+	}
+	report := &models.BudgetReport{Items: make([]*models.BudgetItemReport, 0), Totals: make([]*models.BudgetVariance, 0)}
+	totals := make(map[string]*models.BudgetVariance)
+	for _, budgetItem := range budgetItems {
+		itemReport := u.itemReport(budgetItem, sums)
+		report.Items = append(report.Items, itemReport)
+		//amounts of different currencies are never added together
+		total, ok := totals[budgetItem.Currency]
+		if !ok {
+			total = &models.BudgetVariance{Currency: budgetItem.Currency}
+			totals[budgetItem.Currency] = total
+			report.Totals = append(report.Totals, total)
+		}
+		total.Planned += itemReport.Variance.Planned
+		total.Spent += itemReport.Variance.Spent
+		total.Pending += itemReport.Variance.Pending
+	}
+	sort.Slice(report.Totals, func(i, j int) bool { return report.Totals[i].Currency < report.Totals[j].Currency })
+	return report, nil
+}
+
+/*ReportItem: one budget item against its expenses*/
+func (u *BudgetItemService) ReportItem(budgetItem *models.BudgetItem) (*models.BudgetItemReport, error) {
+	sums, err := u.ExpenseRepository.Sums(bson.M{"budgetItem": budgetItem.ID})
+	if err != nil {
+		return nil, err
+	}
+	return u.itemReport(budgetItem, sums), nil
+}
+
+/* itemReport: count the sums of the expenses of a budget item */
+func (u *BudgetItemService) itemReport(budgetItem *models.BudgetItem, sums []*models.ExpenseSum) *models.BudgetItemReport {
+	itemReport := &models.BudgetItemReport{
+		Item:     budgetItem,
+		Variance: models.BudgetVariance{Currency: budgetItem.Currency, Planned: budgetItem.Planned},
+	}
+	for _, sum := range sums {
+		if sum.BudgetItem == budgetItem.ID {
+			itemReport.Variance.Add(sum)
+		}
+	}
+	return itemReport
+}
+
+//validation
+func (u *BudgetItemService) ValidateNewBudgetItem(newBudgetItem model.NewBudgetItem) error {
+	return validation.ValidateStruct(&newBudgetItem,
+		validation.Field(&newBudgetItem.Category, validation.Required.Error("category must not be blanked")),
+		validation.Field(&newBudgetItem.Planned, validation.Min(0).Error("planned amount must not be negative")),
+		validation.Field(&newBudgetItem.Currency, validation.Required.Error("currency must not be blanked"), validation.Match(currencyPattern).Error("currency must be an ISO 4217 code like VND")),
+	)
+}
+
+func (u *BudgetItemService) ValidateUpdateBudgetItem(updateBudgetItem model.UpdateBudgetItem) error {
+	return validation.ValidateStruct(&updateBudgetItem,
+		validation.Field(&updateBudgetItem.Category, validation.Required.Error("category must not be blanked")),
+		validation.Field(&updateBudgetItem.Planned, validation.Min(0).Error("planned amount must not be negative")),
+		validation.Field(&updateBudgetItem.Currency, validation.Required.Error("currency must not be blanked"), validation.Match(currencyPattern).Error("currency must be an ISO 4217 code like VND")),
+	)
+}
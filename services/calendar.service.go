@@ -0,0 +1,122 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var CalendarServiceName = "CalendarServiceName"
+
+//DefaultCalendarTimezone: the zone calendar times are written in when CALENDAR_TIMEZONE is not set
+var DefaultCalendarTimezone = "Asia/Ho_Chi_Minh"
+
+/*LoadCalendarLocation: the zone of a timezone name, the default one when it is empty*/
+func LoadCalendarLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		timezone = DefaultCalendarTimezone
+	}
+	return time.LoadLocation(timezone)
+}
+
+//CalendarFeedHistory: how long finished events stay in the public feed
+var CalendarFeedHistory = 365 * 24 * time.Hour
+
+type CalendarService struct {
+	EventRepository *EventRepository
+	TaskRepository  *TaskRepository
+	UserRepository  *UserRepository
+	Location        *time.Location
+}
+
+/* published: the condition of the events shown outside of the organization */
+func published() bson.M {
+	return bson.M{"isApproved": true, "isDeleted": false}
+}
+
+/*EventCalendar: the calendar of one published event*/
+func (u *CalendarService) EventCalendar(eventId primitive.ObjectID) (*models.Calendar, error) {
+	condition := published()
+	condition["_id"] = eventId
+	event, err := u.EventRepository.FindOne(condition)
+	if err != nil {
+		return nil, err
+	}
+	return u.ForEvent(event), nil
+}
+
+/*ForEvent: the calendar of an event, attached to the mails of its participants*/
+func (u *CalendarService) ForEvent(event *models.Event) *models.Calendar {
+	calendar := models.NewCalendar(event.Name, u.Location)
+	calendar.AddEvent(event)
+	return calendar
+}
+
+/*PublishedCalendar: the feed of the published events, the ones finished for long left out*/
+func (u *CalendarService) PublishedCalendar() (*models.Calendar, error) {
+	condition := published()
+	condition["endDate"] = bson.M{"$gte": time.Now().Add(-CalendarFeedHistory)}
+	events, err := u.EventRepository.FindAll(condition)
+	if err != nil {
+		return nil, err
+	}
+	calendar := models.NewCalendar("NetEvent", u.Location)
+	for _, event := range events {
+		calendar.AddEvent(event)
+	}
+	return calendar, nil
+}
+
+/*TaskCalendar: the feed of the tasks assigned to the user owning a calendar token*/
+func (u *CalendarService) TaskCalendar(token string) (*models.Calendar, error) {
+	user, err := u.UserRepository.FindOne(bson.M{"calendarToken": hashToken(token)})
+	if err != nil {
+		//the token is the only credential of the feed, do not tell which part is wrong
+		return nil, helpers.NewErrNotFound("calendar is not found")
+	}
+	tasks, err := u.TaskRepository.FindAll(bson.M{"user": user.ID})
+	if err != nil {
+		return nil, err
+	}
+	calendar := models.NewCalendar("NetEvent tasks", u.Location)
+	events := make(map[primitive.ObjectID]*models.Event)
+	for _, task := range tasks {
+		//a task can exist without an event
+		var event *models.Event
+		if !task.Event.IsZero() {
+			if _, ok := events[task.Event]; !ok {
+				if events[task.Event], err = u.EventRepository.FindOne(bson.M{"_id": task.Event}); err != nil {
+					return nil, err
+				}
+			}
+			event = events[task.Event]
+		}
+		calendar.AddTask(task, event)
+	}
+	return calendar, nil
+}
+
+/*ResetCalendarToken: give a user a new token for their task feed, the previous one stops working*/
+func (u *CalendarService) ResetCalendarToken(userId primitive.ObjectID) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+	if _, err := u.UserRepository.UpdateOne(bson.M{"_id": userId}, bson.M{"calendarToken": hashToken(token)}); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+/* hashToken: only the hash of a secret token is stored */
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
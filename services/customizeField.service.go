@@ -0,0 +1,292 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/models"
+)
+
+//phone numbers may start with a country code and contain spaces, dashes and brackets
+var phonePattern = regexp.MustCompile(`^\+?[0-9 ()\-]{6,20}$`)
+
+/*MapCustomizeFields: convert graphql customize fields to stored ones, sorted by their order*/
+func MapCustomizeFields(inputFields []*model.InputCustomizeField) []*models.CustomizeField {
+	customizeFields := make([]*models.CustomizeField, 0)
+	for i, v := range inputFields {
+		//fields without order keep their position in the list
+		order := i
+		if v.Order != nil {
+			order = *v.Order
+		}
+		pattern := ""
+		if v.Pattern != nil {
+			pattern = *v.Pattern
+		}
+		customizeFields = append(customizeFields, &models.CustomizeField{
+			Name:      v.Name,
+			Type:      v.Type.String(),
+			Values:    v.Value,
+			Required:  v.Required,
+			Order:     order,
+			Min:       v.Min,
+			Max:       v.Max,
+			Pattern:   pattern,
+			MaxLength: v.MaxLength,
+		})
+	}
+	sort.SliceStable(customizeFields, func(i, j int) bool {
+		return customizeFields[i].Order < customizeFields[j].Order
+	})
+	return customizeFields
+}
+
+/*NextCustomizeFieldVersions: keep a snapshot of the customize fields whenever their definitions change*/
+func NextCustomizeFieldVersions(event *models.Event, customizeFields []*models.CustomizeField) (int, []*models.CustomizeFieldVersion) {
+	versions := append(make([]*models.CustomizeFieldVersion, 0), event.CustomizeFieldVersions...)
+	if len(versions) > 0 && sameCustomizeFields(event.CustomizeFields, customizeFields) {
+		return event.CustomizeFieldsVersion, versions
+	}
+	//events created before versions existed keep their old fields as the first snapshot
+	if len(versions) == 0 && len(event.CustomizeFields) > 0 {
+		versions = append(versions, &models.CustomizeFieldVersion{
+			Version:   event.CustomizeFieldsVersion,
+			CreatedAt: event.UpdatedAt,
+			Fields:    event.CustomizeFields,
+		})
+	}
+	version := event.CustomizeFieldsVersion + 1
+	return version, append(versions, &models.CustomizeFieldVersion{
+		Version:   version,
+		CreatedAt: time.Now(),
+		Fields:    customizeFields,
+	})
+}
+
+/*sameCustomizeFields: compare the definitions of two field lists, their order does not matter*/
+func sameCustomizeFields(a []*models.CustomizeField, b []*models.CustomizeField) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	definitions := make(map[string]models.CustomizeField)
+	for _, v := range a {
+		definition := *v
+		definition.Order = 0
+		definitions[v.Name] = definition
+	}
+	for _, v := range b {
+		definition := *v
+		definition.Order = 0
+		if current, ok := definitions[v.Name]; !ok || !reflect.DeepEqual(current, definition) {
+			return false
+		}
+	}
+	return true
+}
+
+/*ValidateCustomizeFields: check the field definitions of an event and their options*/
+func ValidateCustomizeFields(inputFields []*model.InputCustomizeField) error {
+	names := make(map[string]bool)
+	for _, field := range inputFields {
+		if field.Name == "" {
+			return errors.New("field name must not be blanked")
+		}
+		if names[field.Name] {
+			return fmt.Errorf("%s is defined more than once", field.Name)
+		}
+		names[field.Name] = true
+
+		fieldType := field.Type.String()
+		isSelect := fieldType == models.FieldTypeSingleSelect || fieldType == models.FieldTypeMultiSelect
+		if isSelect {
+			if len(field.Value) == 0 {
+				return fmt.Errorf("%s must have at least one option", field.Name)
+			}
+			options := make(map[string]bool)
+			for _, v := range field.Value {
+				if v == "" || options[v] {
+					return fmt.Errorf("options of %s must be unique and not blanked", field.Name)
+				}
+				options[v] = true
+			}
+		} else if len(field.Value) > 0 {
+			return fmt.Errorf("%s cannot have options", field.Name)
+		}
+		if (field.Min != nil || field.Max != nil) && fieldType != models.FieldTypeNumber {
+			return fmt.Errorf("min and max only apply to number fields")
+		}
+		if field.Min != nil && field.Max != nil && *field.Min > *field.Max {
+			return fmt.Errorf("min of %s must not be greater than max", field.Name)
+		}
+		if field.Pattern != nil {
+			if fieldType != models.FieldTypeText && fieldType != models.FieldTypePhone {
+				return fmt.Errorf("pattern only applies to text and phone fields")
+			}
+			if _, err := regexp.Compile(*field.Pattern); err != nil {
+				return fmt.Errorf("pattern of %s is invalid", field.Name)
+			}
+		}
+		if field.MaxLength != nil {
+			if fieldType != models.FieldTypeText && fieldType != models.FieldTypeEmail && fieldType != models.FieldTypePhone {
+				return fmt.Errorf("max length only applies to text, email and phone fields")
+			}
+			if *field.MaxLength < 1 {
+				return fmt.Errorf("max length of %s must be at least 1", field.Name)
+			}
+		}
+	}
+	return nil
+}
+
+/*ValidateFieldAnswers: every answer must belong to a field, match its type and every required field must be answered*/
+func ValidateFieldAnswers(fields []*models.CustomizeField, answers []*model.InputFieldAnswer) error {
+	answered := make(map[string]*model.InputFieldAnswer)
+	for _, answer := range answers {
+		if _, ok := answered[answer.Name]; ok {
+			return fmt.Errorf("%s is answered more than once", answer.Name)
+		}
+		answered[answer.Name] = answer
+	}
+	known := make(map[string]bool)
+	for _, field := range fields {
+		known[field.Name] = true
+		answer, ok := answered[field.Name]
+		//an answer of blank values is no answer
+		if !ok || blankValues(answer.Values) {
+			if field.Required {
+				return fmt.Errorf("%s must not be blanked", field.Name)
+			}
+			continue
+		}
+		if err := validateFieldValues(field, answer.Values); err != nil {
+			return err
+		}
+	}
+	for _, answer := range answers {
+		if !known[answer.Name] {
+			return fmt.Errorf("%s is not a field of this event", answer.Name)
+		}
+	}
+	return nil
+}
+
+/*blankValues: whether none of the values has more than spaces*/
+func blankValues(values []string) bool {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+/*validateFieldValues: check the values of one answer against the type and options of the field*/
+func validateFieldValues(field *models.CustomizeField, values []string) error {
+	fieldType := field.FieldType()
+	//only multi select fields take several values
+	if len(values) > 1 && fieldType != models.FieldTypeMultiSelect {
+		return fmt.Errorf("%s takes a single value", field.Name)
+	}
+	chosen := make(map[string]bool)
+	for _, value := range values {
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("%s must not have a blank value", field.Name)
+		}
+		if field.MaxLength != nil && len([]rune(value)) > *field.MaxLength {
+			return fmt.Errorf("%s must not be longer than %d characters", field.Name, *field.MaxLength)
+		}
+		if field.Pattern != "" {
+			if matched, err := regexp.MatchString(field.Pattern, value); err != nil || !matched {
+				return fmt.Errorf("%s has an invalid format", field.Name)
+			}
+		}
+		switch fieldType {
+		case models.FieldTypeSingleSelect, models.FieldTypeMultiSelect:
+			if chosen[value] {
+				return fmt.Errorf("%s is chosen more than once in %s", value, field.Name)
+			}
+			chosen[value] = true
+			allowed := false
+			for _, v := range field.Values {
+				if v == value {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				return fmt.Errorf("%s is not an option of %s", value, field.Name)
+			}
+		case models.FieldTypeNumber:
+			//ParseFloat accepts NaN and Inf, which no min or max can bound
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+				return fmt.Errorf("%s must be a number", field.Name)
+			}
+			if field.Min != nil && number < *field.Min {
+				return fmt.Errorf("%s must be at least %v", field.Name, *field.Min)
+			}
+			if field.Max != nil && number > *field.Max {
+				return fmt.Errorf("%s must be at most %v", field.Name, *field.Max)
+			}
+		case models.FieldTypeDate:
+			if _, err := time.Parse("2006-01-02", value); err != nil {
+				return fmt.Errorf("%s must be a date (YYYY-MM-DD)", field.Name)
+			}
+		case models.FieldTypeEmail:
+			if err := is.Email.Validate(value); err != nil {
+				return fmt.Errorf("%s must be an email", field.Name)
+			}
+		case models.FieldTypePhone:
+			if !phonePattern.MatchString(value) {
+				return fmt.Errorf("%s must be a phone number", field.Name)
+			}
+		case models.FieldTypeCheckbox:
+			checked, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("%s must be true or false", field.Name)
+			}
+			//a required checkbox has to be ticked
+			if field.Required && !checked {
+				return fmt.Errorf("%s must be checked", field.Name)
+			}
+		case models.FieldTypeFile:
+			if err := is.URL.Validate(value); err != nil {
+				return fmt.Errorf("%s must be a link to the uploaded file", field.Name)
+			}
+		}
+	}
+	return nil
+}
+
+/*InputCustomizeFields: convert stored customize fields back to graphql ones, to define them on another event*/
+func InputCustomizeFields(customizeFields []*models.CustomizeField) []*model.InputCustomizeField {
+	inputFields := make([]*model.InputCustomizeField, 0)
+	for _, v := range customizeFields {
+		order := v.Order
+		var pattern *string
+		if v.Pattern != "" {
+			pattern = &v.Pattern
+		}
+		inputFields = append(inputFields, &model.InputCustomizeField{
+			Name:      v.Name,
+			Type:      model.CustomizeFieldType(v.FieldType()),
+			Value:     v.Values,
+			Required:  v.Required,
+			Order:     &order,
+			Min:       v.Min,
+			Max:       v.Max,
+			Pattern:   pattern,
+			MaxLength: v.MaxLength,
+		})
+	}
+	return inputFields
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+)
+
+var DashboardServiceName = "DashboardServiceName"
+
+//DashboardCacheTTL: how long a dashboard is served from the cache, whatever the writes
+var DashboardCacheTTL = 10 * time.Minute
+
+//DashboardMaxPeriod: the longest period a dashboard can cover
+var DashboardMaxPeriod = 5 * 366 * 24 * time.Hour
+
+//DashboardTopLimit: the number of facilities and staff ranked
+var DashboardTopLimit = 10
+
+type DashboardService struct {
+	StatisticRepository *StatisticRepository
+	mutex               sync.Mutex
+	cache               map[string]*cachedDashboard
+}
+
+type cachedDashboard struct {
+	version   int64
+	expiresAt time.Time
+	dashboard *models.Dashboard
+}
+
+/*GetDashboard: the numbers of every event starting from from until to, served from the cache while no write outdated it*/
+func (u *DashboardService) GetDashboard(from time.Time, to time.Time) (*models.Dashboard, error) {
+	if !from.Before(to) {
+		return nil, helpers.NewErrValidation("from must be before to")
+	}
+	if to.Sub(from) > DashboardMaxPeriod {
+		return nil, helpers.NewErrValidation("the period cannot be longer than 5 years")
+	}
+	from, to = from.UTC(), to.UTC()
+
+	//the version is shared by every instance, a write on any of them outdates the cache of all
+	version, err := u.StatisticRepository.CacheVersion()
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("%d-%d", from.UnixNano(), to.UnixNano())
+	now := time.Now()
+	u.mutex.Lock()
+	cached, ok := u.cache[key]
+	u.mutex.Unlock()
+	if ok && cached.version == version && now.Before(cached.expiresAt) {
+		return cached.dashboard, nil
+	}
+
+	dashboard, err := u.build(from, to)
+	if err != nil {
+		return nil, err
+	}
+	u.mutex.Lock()
+	if u.cache == nil {
+		u.cache = make(map[string]*cachedDashboard)
+	}
+	//drop the outdated entries so the cache does not grow with every period asked
+	for k, c := range u.cache {
+		if c.version != version || !now.Before(c.expiresAt) {
+			delete(u.cache, k)
+		}
+	}
+	u.cache[key] = &cachedDashboard{version: version, expiresAt: now.Add(DashboardCacheTTL), dashboard: dashboard}
+	u.mutex.Unlock()
+	return dashboard, nil
+}
+
+/*Invalidate: outdate the cached dashboards after a write*/
+func (u *DashboardService) Invalidate() error {
+	return u.StatisticRepository.BumpCacheVersion()
+}
+
+/*build: run the aggregations of a dashboard*/
+func (u *DashboardService) build(from time.Time, to time.Time) (*models.Dashboard, error) {
+	totals, err := u.StatisticRepository.EventTotals(from, to)
+	if err != nil {
+		return nil, err
+	}
+	budgets, err := u.StatisticRepository.BudgetTotals(from, to)
+	if err != nil {
+		return nil, err
+	}
+	perType, err := u.StatisticRepository.EventsPerTypeMonth(from, to)
+	if err != nil {
+		return nil, err
+	}
+	turnaround, err := u.StatisticRepository.ApprovalTurnaround(from, to)
+	if err != nil {
+		return nil, err
+	}
+	attendance, err := u.StatisticRepository.AttendanceTrend(from, to)
+	if err != nil {
+		return nil, err
+	}
+	facilities, err := u.StatisticRepository.BusiestFacilities(from, to, DashboardTopLimit)
+	if err != nil {
+		return nil, err
+	}
+	staff, err := u.StatisticRepository.TopStaff(from, to, DashboardTopLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Dashboard{
+		From:               from,
+		To:                 to,
+		Events:             *totals,
+		Budgets:            budgets,
+		EventsPerType:      perType,
+		ApprovalTurnaround: *turnaround,
+		Attendance:         attendance,
+		BusiestFacilities:  facilities,
+		TopStaff:           staff,
+	}, nil
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/khanhvtn/netevent-go/database"
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"github.com/khanhvtn/netevent-go/utilities"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var EmailTemplateRepositoryName = "EmailTemplateRepositoryName"
+
+type EmailTemplateRepository struct {
+	MongoCN *database.MongoInstance
+}
+
+/* createContextAndTargetCol: create and return targeted collection based on collection name */
+func (u *EmailTemplateRepository) createContextAndTargetCol(colName string) (col *mongo.Collection,
+	ctx context.Context,
+	cancel context.CancelFunc) {
+	col = u.MongoCN.Db.Collection(colName)
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	return
+}
+
+/* FindAll: get all data based on condition*/
+func (u *EmailTemplateRepository) FindAll(condition bson.M) ([]*models.EmailTemplate, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionEmailTemplateName)
+	defer cancel()
+
+	//create an empty array to store all fields from collection
+	var emailTemplates []*models.EmailTemplate = make([]*models.EmailTemplate, 0)
+
+	//get all record
+	cur, err := collection.Find(ctx, condition, options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "language", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	//map data to target variable
+	for cur.Next(ctx) {
+		var emailTemplate models.EmailTemplate
+		cur.Decode(&emailTemplate)
+		emailTemplates = append(emailTemplates, &emailTemplate)
+	}
+	return emailTemplates, nil
+}
+
+/*FindOne: get one record from a collection  */
+func (u *EmailTemplateRepository) FindOne(filter bson.M) (*models.EmailTemplate, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionEmailTemplateName)
+	defer cancel()
+
+	emailTemplate := models.EmailTemplate{}
+	//Decode record into result
+	if err := collection.FindOne(ctx, filter).Decode(&emailTemplate); err != nil {
+		if err == mongo.ErrNoDocuments {
+			//return nil data when id is not existed.
+			return nil, helpers.NewErrNotFound("email template is not found")
+		}
+		//return err if there is a system error
+		return nil, err
+	}
+
+	return &emailTemplate, nil
+}
+
+/*Create: create a new record to a collection*/
+func (u *EmailTemplateRepository) Create(newEmailTemplate model.NewEmailTemplate) (*models.EmailTemplate, error) {
+
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionEmailTemplateName)
+	defer cancel()
+
+	//convert to bson.M
+	currentTime := time.Now()
+	emailTemplate := models.EmailTemplate{
+		CreatedAt: currentTime,
+		UpdatedAt: currentTime,
+		Name:      newEmailTemplate.Name.String(),
+		Language:  newEmailTemplate.Language.String(),
+		Subject:   newEmailTemplate.Subject,
+		Html:      newEmailTemplate.HTML,
+		Text:      newEmailTemplate.Text,
+	}
+	newData, err := utilities.InterfaceToBsonM(emailTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	//create email template in database
+	insertResult, err := collection.InsertOne(ctx, newData)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, helpers.NewErrConflict("this template is already overridden for this language")
+		}
+		return nil, err
+	}
+
+	emailTemplate.ID = insertResult.InsertedID.(primitive.ObjectID)
+	return &emailTemplate, nil
+}
+
+/*UpdateOne: update one record from a collection*/
+func (u EmailTemplateRepository) UpdateOne(filter bson.M, update bson.M) (*models.EmailTemplate, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionEmailTemplateName)
+	defer cancel()
+
+	//update email template information
+	newUpdate := bson.M{"$set": update}
+	updateResult, err := collection.UpdateOne(ctx, filter, newUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return nil, helpers.NewErrNotFound("email template id is not found")
+	}
+
+	//query the new update
+	emailTemplate, errQuery := u.FindOne(filter)
+	if errQuery != nil {
+		return nil, errQuery
+	}
+
+	return emailTemplate, nil
+}
+
+//DeleteOne func is to delete one record from a collection
+func (u EmailTemplateRepository) DeleteOne(filter bson.M) (*models.EmailTemplate, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionEmailTemplateName)
+	defer cancel()
+
+	emailTemplate, errFind := u.FindOne(filter)
+	if errFind != nil {
+		return nil, errFind
+	}
+
+	//delete email template from database
+	deleteResult, err := collection.DeleteOne(ctx, filter)
+	if err != nil {
+		//response to client if there is an error.
+		return nil, err
+	}
+
+	if deleteResult.DeletedCount == 0 {
+		return nil, helpers.NewErrNotFound("email template id is not found")
+	}
+
+	return emailTemplate, nil
+}
+
+/*CreateIndexes: a template can be overridden only once per language*/
+func (u *EmailTemplateRepository) CreateIndexes() error {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionEmailTemplateName)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}, {Key: "language", Value: 1}},
+		Options: options.Index().SetName("name_language_unique").SetUnique(true),
+	})
+	return err
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmlTemplate "html/template"
+	"strings"
+	textTemplate "text/template"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"github.com/khanhvtn/netevent-go/templates"
+	"github.com/khanhvtn/netevent-go/utilities"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var EmailTemplateServiceName = "EmailTemplateServiceName"
+
+//DefaultAppURL: the address of the web app the links of the mails point to when APP_URL is not set
+var DefaultAppURL = "http://localhost:3000"
+
+type EmailTemplateService struct {
+	EmailTemplateRepository *EmailTemplateRepository
+	//zone the dates of the mails are written in
+	Location *time.Location
+	AppURL   string
+}
+
+/* GetAll: get all data based on condition*/
+func (u *EmailTemplateService) GetAll(condition bson.M) ([]*models.EmailTemplate, error) {
+	return u.EmailTemplateRepository.FindAll(condition)
+}
+
+/*GetOne: get one record from a collection  */
+func (u *EmailTemplateService) GetOne(filter bson.M) (*models.EmailTemplate, error) {
+	return u.EmailTemplateRepository.FindOne(filter)
+}
+
+/*Create: create a new record to a collection*/
+func (u *EmailTemplateService) Create(newEmailTemplate model.NewEmailTemplate) (*models.EmailTemplate, error) {
+	return u.EmailTemplateRepository.Create(newEmailTemplate)
+}
+
+/*UpdateOne: update one record from a collection*/
+func (u *EmailTemplateService) UpdateOne(filter bson.M, update model.UpdateEmailTemplate) (*models.EmailTemplate, error) {
+	bsonUpdate, err := utilities.InterfaceToBsonM(update)
+	if err != nil {
+		return nil, err
+	}
+	bsonUpdate["updatedAt"] = time.Now()
+	return u.EmailTemplateRepository.UpdateOne(filter, bsonUpdate)
+}
+
+//DeleteOne func is to delete one record from a collection, the default template is used again
+func (u *EmailTemplateService) DeleteOne(filter bson.M) (*models.EmailTemplate, error) {
+	return u.EmailTemplateRepository.DeleteOne(filter)
+}
+
+/*
+Compose: build a mail from a template in the language of an event.
+A template stored by an admin wins over the default one of its language, english is used when the language has none
+*/
+func (u *EmailTemplateService) Compose(name string, language string, data models.MailTemplateData) (*models.Mail, error) {
+	subject, html, text, err := u.lookup(name, models.MailLanguage(language))
+	if err != nil {
+		return nil, err
+	}
+	subjectTemplate, htmlT, textT, err := parseEmailTemplate(name, subject, html, text)
+	if err != nil {
+		return nil, err
+	}
+
+	m := models.NewMail()
+	body := bytes.NewBuffer(nil)
+	if err := subjectTemplate.Execute(body, data); err != nil {
+		return nil, err
+	}
+	//a subject is a single line
+	m.Subject = strings.Join(strings.Fields(body.String()), " ")
+	body.Reset()
+	if err := textT.Execute(body, data); err != nil {
+		return nil, err
+	}
+	m.Body = body.String()
+	m.MailTemplate = &models.MailTemplate{Template: htmlT, Data: data}
+	return m, nil
+}
+
+/* lookup: the sources of a template, the stored then the embedded one of the language, then the same in english */
+func (u *EmailTemplateService) lookup(name string, language string) (subject string, html string, text string, err error) {
+	languages := []string{language}
+	if language != models.MailLanguageEnglish {
+		languages = append(languages, models.MailLanguageEnglish)
+	}
+	for _, language := range languages {
+		stored, err := u.GetOne(bson.M{"name": name, "language": language})
+		if err == nil {
+			return stored.Subject, stored.Html, stored.Text, nil
+		}
+		if _, ok := err.(*helpers.ErrNotFound); !ok {
+			return "", "", "", err
+		}
+		if subject, html, text, err := defaultEmailTemplate(name, language); err == nil {
+			return subject, html, text, nil
+		}
+	}
+	return "", "", "", fmt.Errorf("no template named %s", name)
+}
+
+/*FormatDate: a date of a mail in the zone of the calendars, written for the language of an event*/
+func (u *EmailTemplateService) FormatDate(t time.Time, language string) string {
+	return models.FormatMailDate(t.In(u.Location), models.MailLanguage(language))
+}
+
+/*Link: the address of a page of the web app*/
+func (u *EmailTemplateService) Link(format string, a ...interface{}) string {
+	return strings.TrimRight(u.AppURL, "/") + fmt.Sprintf(format, a...)
+}
+
+/* defaultEmailTemplate: the embedded template, its subject is the "subject" block of the text */
+func defaultEmailTemplate(name string, language string) (subject string, html string, text string, err error) {
+	path := fmt.Sprintf("mails/%s.%s", strings.ToLower(name), language)
+	htmlBytes, err := templates.Mails.ReadFile(path + ".html")
+	if err != nil {
+		return "", "", "", err
+	}
+	textBytes, err := templates.Mails.ReadFile(path + ".txt")
+	if err != nil {
+		return "", "", "", err
+	}
+	return `{{template "subject" .}}`, string(htmlBytes), string(textBytes), nil
+}
+
+/* parseEmailTemplate: parse the three parts of a template, the subject can use the blocks of the text */
+func parseEmailTemplate(name string, subject string, html string, text string) (*textTemplate.Template, *htmlTemplate.Template, *textTemplate.Template, error) {
+	textT, err := textTemplate.New(name).Parse(text)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("text: %s", err.Error())
+	}
+	subjectTemplate, err := textT.New(name + ".subject").Parse(subject)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("subject: %s", err.Error())
+	}
+	htmlT, err := htmlTemplate.New(name).Parse(html)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("html: %s", err.Error())
+	}
+	return subjectTemplate, htmlT, textT, nil
+}
+
+/* validateEmailTemplateSources: the sources must parse and render with sample data */
+func validateEmailTemplateSources(name string, subject string, html string, text string) error {
+	subjectTemplate, htmlT, textT, err := parseEmailTemplate(name, subject, html, text)
+	if err != nil {
+		return err
+	}
+	sample := models.MailTemplateData{Name: "Name", EventName: "Event", EventStart: models.FormatMailDate(time.Now(), models.MailLanguageEnglish), Location: "Location", Link: "https://example.com", TaskName: "Task", TaskStart: models.FormatMailDate(time.Now(), models.MailLanguageEnglish)}
+	body := bytes.NewBuffer(nil)
+	if err := subjectTemplate.Execute(body, sample); err != nil {
+		return fmt.Errorf("subject: %s", err.Error())
+	}
+	if err := htmlT.Execute(body, sample); err != nil {
+		return fmt.Errorf("html: %s", err.Error())
+	}
+	if err := textT.Execute(body, sample); err != nil {
+		return fmt.Errorf("text: %s", err.Error())
+	}
+	return nil
+}
+
+//validation
+func (u *EmailTemplateService) ValidateNewEmailTemplate(newEmailTemplate model.NewEmailTemplate) error {
+	return validation.ValidateStruct(&newEmailTemplate,
+		validation.Field(&newEmailTemplate.Name, validation.Required.Error("name must not be blanked"), validation.By(func(name interface{}) error {
+			if !name.(model.EmailTemplateName).IsValid() {
+				return errors.New("unknown template name")
+			}
+			return nil
+		})),
+		validation.Field(&newEmailTemplate.Language, validation.Required.Error("language must not be blanked")),
+		validation.Field(&newEmailTemplate.Subject, validation.Required.Error("subject must not be blanked")),
+		validation.Field(&newEmailTemplate.HTML, validation.Required.Error("html must not be blanked"), validation.By(func(interface{}) error {
+			return validateEmailTemplateSources(newEmailTemplate.Name.String(), newEmailTemplate.Subject, newEmailTemplate.HTML, newEmailTemplate.Text)
+		})),
+		validation.Field(&newEmailTemplate.Text, validation.Required.Error("text must not be blanked")),
+	)
+}
+
+func (u *EmailTemplateService) ValidateUpdateEmailTemplate(id string, updateEmailTemplate model.UpdateEmailTemplate) error {
+	return validation.ValidateStruct(&updateEmailTemplate,
+		validation.Field(&updateEmailTemplate.Subject, validation.Required.Error("subject must not be blanked")),
+		validation.Field(&updateEmailTemplate.HTML, validation.Required.Error("html must not be blanked"), validation.By(func(interface{}) error {
+			//convert string id to object id
+			objectId, err := utilities.ConvertStringIdToObjectID(id)
+			if err != nil {
+				return err
+			}
+			current, err := u.GetOne(bson.M{"_id": objectId})
+			if err != nil {
+				return err
+			}
+			return validateEmailTemplateSources(current.Name, updateEmailTemplate.Subject, updateEmailTemplate.HTML, updateEmailTemplate.Text)
+		})),
+		validation.Field(&updateEmailTemplate.Text, validation.Required.Error("text must not be blanked")),
+	)
+}
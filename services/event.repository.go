@@ -86,29 +86,34 @@ func (u *EventRepository) Create(newEvent models.Event) (*models.Event, error) {
 	//convert o bson.M
 	currentTime := time.Now()
 	event := models.Event{
-		Tags:                  newEvent.Tags,
-		IsApproved:            false,
-		Reviewer:              nil,
-		IsFinished:            false,
-		Tasks:                 newEvent.Tasks,
-		FacilityHistories:     newEvent.FacilityHistories,
-		Name:                  newEvent.Name,
-		Language:              newEvent.Language,
-		EventType:             newEvent.EventType,
-		Mode:                  newEvent.Mode,
-		Location:              newEvent.Location,
-		Accommodation:         newEvent.Accommodation,
-		RegistrationCloseDate: newEvent.RegistrationCloseDate,
-		StartDate:             newEvent.StartDate,
-		EndDate:               newEvent.EndDate,
-		MaxParticipants:       newEvent.MaxParticipants,
-		Description:           newEvent.Description,
-		Owner:                 newEvent.Owner,
-		Budget:                newEvent.Budget,
-		Image:                 newEvent.Image,
-		IsDeleted:             false,
-		CreatedAt:             currentTime,
-		UpdatedAt:             currentTime,
+		Tags:                   newEvent.Tags,
+		IsApproved:             false,
+		Reviewer:               nil,
+		IsFinished:             false,
+		Tasks:                  newEvent.Tasks,
+		FacilityHistories:      newEvent.FacilityHistories,
+		Name:                   newEvent.Name,
+		Language:               newEvent.Language,
+		EventType:              newEvent.EventType,
+		Mode:                   newEvent.Mode,
+		Location:               newEvent.Location,
+		Accommodation:          newEvent.Accommodation,
+		RegistrationCloseDate:  newEvent.RegistrationCloseDate,
+		StartDate:              newEvent.StartDate,
+		EndDate:                newEvent.EndDate,
+		MaxParticipants:        newEvent.MaxParticipants,
+		Description:            newEvent.Description,
+		Owner:                  newEvent.Owner,
+		Budget:                 newEvent.Budget,
+		Image:                  newEvent.Image,
+		IsDeleted:              false,
+		CreatedAt:              currentTime,
+		UpdatedAt:              currentTime,
+		CustomizeFields:        newEvent.CustomizeFields,
+		CustomizeFieldsVersion: newEvent.CustomizeFieldsVersion,
+		CustomizeFieldVersions: newEvent.CustomizeFieldVersions,
+		Series:                 newEvent.Series,
+		OccurrenceStart:        newEvent.OccurrenceStart,
 	}
 	newData, err := utilities.InterfaceToBsonM(event)
 	if err != nil {
@@ -122,49 +127,65 @@ func (u *EventRepository) Create(newEvent models.Event) (*models.Event, error) {
 	}
 
 	return &models.Event{
-		ID:                    insertResult.InsertedID.(primitive.ObjectID),
-		CreatedAt:             currentTime,
-		UpdatedAt:             currentTime,
-		Tags:                  newEvent.Tags,
-		IsApproved:            false,
-		Reviewer:              nil,
-		IsFinished:            false,
-		Tasks:                 newEvent.Tasks,
-		FacilityHistories:     newEvent.FacilityHistories,
-		Name:                  newEvent.Name,
-		Language:              newEvent.Language,
-		EventType:             newEvent.EventType,
-		Mode:                  newEvent.Mode,
-		Location:              newEvent.Location,
-		Accommodation:         newEvent.Accommodation,
-		RegistrationCloseDate: newEvent.RegistrationCloseDate,
-		StartDate:             newEvent.StartDate,
-		EndDate:               newEvent.EndDate,
-		MaxParticipants:       newEvent.MaxParticipants,
-		Description:           newEvent.Description,
-		Owner:                 newEvent.Owner,
-		Budget:                newEvent.Budget,
-		Image:                 newEvent.Image,
-		IsDeleted:             false}, nil
+		ID:                     insertResult.InsertedID.(primitive.ObjectID),
+		CreatedAt:              currentTime,
+		UpdatedAt:              currentTime,
+		Tags:                   newEvent.Tags,
+		IsApproved:             false,
+		Reviewer:               nil,
+		IsFinished:             false,
+		Tasks:                  newEvent.Tasks,
+		FacilityHistories:      newEvent.FacilityHistories,
+		Name:                   newEvent.Name,
+		Language:               newEvent.Language,
+		EventType:              newEvent.EventType,
+		Mode:                   newEvent.Mode,
+		Location:               newEvent.Location,
+		Accommodation:          newEvent.Accommodation,
+		RegistrationCloseDate:  newEvent.RegistrationCloseDate,
+		StartDate:              newEvent.StartDate,
+		EndDate:                newEvent.EndDate,
+		MaxParticipants:        newEvent.MaxParticipants,
+		Description:            newEvent.Description,
+		Owner:                  newEvent.Owner,
+		Budget:                 newEvent.Budget,
+		Image:                  newEvent.Image,
+		IsDeleted:              false,
+		CustomizeFields:        newEvent.CustomizeFields,
+		CustomizeFieldsVersion: newEvent.CustomizeFieldsVersion,
+		CustomizeFieldVersions: newEvent.CustomizeFieldVersions,
+		Series:                 newEvent.Series,
+		OccurrenceStart:        newEvent.OccurrenceStart,
+	}, nil
 }
 
-/*UpdateOne: update one record from a collection*/
-func (u EventRepository) UpdateOne(filter bson.M, update bson.M) (*models.Event, error) {
+/*UpdateOne: update one record from a collection, the mails it triggers are queued in the same transaction*/
+func (u EventRepository) UpdateOne(filter bson.M, update bson.M, outbox ...*models.OutboxMessage) (*models.Event, error) {
 	//get a collection , context, cancel func
 	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionEventName)
 	defer cancel()
 
 	//update user information
 	newUpdate := bson.M{"$set": update}
-	updateResult, err := collection.UpdateOne(ctx, filter, newUpdate)
+	err := u.MongoCN.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		updateResult, err := collection.UpdateOne(sessCtx, filter, newUpdate)
+		if err != nil {
+			return err
+		}
+		if updateResult.MatchedCount == 0 {
+			return helpers.NewErrNotFound("event id is not found")
+		}
+		for _, message := range outbox {
+			if _, err := u.MongoCN.Db.Collection(models.CollectionOutboxName).InsertOne(sessCtx, message); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if updateResult.MatchedCount == 0 {
-		return nil, helpers.NewErrNotFound("event id is not found")
-	}
-
 	//query the new update
 	event, errQuery := u.FindOne(filter)
 	if errQuery != nil {
@@ -174,6 +195,40 @@ func (u EventRepository) UpdateOne(filter bson.M, update bson.M) (*models.Event,
 	return event, nil
 }
 
+/*
+UpdateEach: apply its own update to each of several events, tasks and facility histories in one transaction,
+none is changed when one fails
+*/
+func (u EventRepository) UpdateEach(events map[primitive.ObjectID]bson.M, tasks map[primitive.ObjectID]bson.M, facilityHistories map[primitive.ObjectID]bson.M) error {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionEventName)
+	defer cancel()
+
+	return u.MongoCN.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		writes := []struct {
+			collection *mongo.Collection
+			updates    map[primitive.ObjectID]bson.M
+			name       string
+		}{
+			{collection, events, "event"},
+			{u.MongoCN.Db.Collection(models.CollectionTaskName), tasks, "task"},
+			{u.MongoCN.Db.Collection(models.CollectionFacilityHistoryName), facilityHistories, "facility history"},
+		}
+		for _, write := range writes {
+			for id, update := range write.updates {
+				updateResult, err := write.collection.UpdateOne(sessCtx, bson.M{"_id": id}, bson.M{"$set": update})
+				if err != nil {
+					return err
+				}
+				if updateResult.MatchedCount == 0 {
+					return helpers.NewErrNotFound(write.name + " id is not found")
+				}
+			}
+		}
+		return nil
+	})
+}
+
 //DeleteOne func is to update one record from a collection
 func (u EventRepository) DeleteOne(filter bson.M) (*models.Event, error) {
 	//get a collection , context, cancel func
@@ -198,3 +253,41 @@ func (u EventRepository) DeleteOne(filter bson.M) (*models.Event, error) {
 
 	return event, nil
 }
+
+/*ReserveSeat: take one seat of an event in a single update, fails when the event is full*/
+func (u *EventRepository) ReserveSeat(eventId primitive.ObjectID) (bool, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionEventName)
+	defer cancel()
+
+	//an event without max participants has no limit
+	filter := bson.M{"_id": eventId, "$expr": bson.M{"$or": bson.A{
+		bson.M{"$lte": bson.A{"$maxParticipants", 0}},
+		bson.M{"$lt": bson.A{bson.M{"$ifNull": bson.A{"$registeredCount", 0}}, "$maxParticipants"}},
+	}}}
+	updateResult, err := collection.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"registeredCount": 1}})
+	if err != nil {
+		return false, err
+	}
+	return updateResult.ModifiedCount == 1, nil
+}
+
+/*ReleaseSeat: give back one seat of an event*/
+func (u *EventRepository) ReleaseSeat(eventId primitive.ObjectID) error {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionEventName)
+	defer cancel()
+
+	_, err := collection.UpdateOne(ctx, bson.M{"_id": eventId, "registeredCount": bson.M{"$gt": 0}}, bson.M{"$inc": bson.M{"registeredCount": -1}})
+	return err
+}
+
+/*SetRegisteredCount: overwrite the number of taken seats of an event*/
+func (u *EventRepository) SetRegisteredCount(eventId primitive.ObjectID, count int) error {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionEventName)
+	defer cancel()
+
+	_, err := collection.UpdateOne(ctx, bson.M{"_id": eventId}, bson.M{"$set": bson.M{"registeredCount": count}})
+	return err
+}
@@ -2,6 +2,8 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -20,6 +22,11 @@ type EventService struct {
 	EventRepository           *EventRepository
 	TaskRepository            *TaskRepository
 	FacilityHistoryRepository *FacilityHistoryRepository
+	FacilityHistoryService    *FacilityHistoryService
+	ReferenceService          *ReferenceService
+	ParticipantService        *ParticipantService
+	UserRepository            *UserRepository
+	SessionRepository         *SessionRepository
 }
 
 /* GetAll: get all data based on condition*/
@@ -34,6 +41,19 @@ func (u *EventService) GetOne(filter bson.M) (*models.Event, error) {
 
 /*Create: create a new record to a collection*/
 func (u *EventService) Create(newEvent model.NewEvent) (*models.Event, error) {
+	//facilities must be available before anything is created
+	if err := u.checkFacilityConflicts(newEvent.FacilityHistories, nil); err != nil {
+		return nil, err
+	}
+	//ids are parsed first so a bad id leaves no tasks or facility histories behind
+	evenTypeID, err := primitive.ObjectIDFromHex(newEvent.EventTypeID)
+	if err != nil {
+		return nil, err
+	}
+	ownerID, err := primitive.ObjectIDFromHex(newEvent.OwnerID)
+	if err != nil {
+		return nil, err
+	}
 
 	//create and collect ids for task and facility history
 	taskIds := make([]primitive.ObjectID, 0)
@@ -72,51 +92,38 @@ func (u *EventService) Create(newEvent model.NewEvent) (*models.Event, error) {
 		}
 	}
 
-	evenTypeID, err := primitive.ObjectIDFromHex(newEvent.EventTypeID)
-	if err != nil {
-		return nil, err
-	}
-	ownerID, err := primitive.ObjectIDFromHex(newEvent.OwnerID)
-	if err != nil {
-		return nil, err
-	}
-	customizeFields := make([]*models.CustomizeField, 0)
-	for _, v := range newEvent.CustomizeFields {
-		customizeFields = append(customizeFields, &models.CustomizeField{
-			Name:     v.Name,
-			Type:     v.Type,
-			Values:   v.Value,
-			Required: v.Required,
-		})
-	}
+	customizeFields := MapCustomizeFields(newEvent.CustomizeFields)
+	customizeFieldsVersion, customizeFieldVersions := NextCustomizeFieldVersions(&models.Event{}, customizeFields)
 
 	//convert to bson.M
 	currentTime := time.Now()
 	event := models.Event{
-		Tags:                  newEvent.Tags,
-		IsApproved:            false,
-		Reviewer:              nil,
-		IsFinished:            false,
-		Tasks:                 taskIds,
-		FacilityHistories:     facilityHistoryIds,
-		Name:                  newEvent.Name,
-		Language:              newEvent.Language,
-		EventType:             evenTypeID,
-		Mode:                  newEvent.Mode,
-		Location:              newEvent.Location,
-		Accommodation:         newEvent.Accommodation,
-		RegistrationCloseDate: newEvent.RegistrationCloseDate,
-		StartDate:             newEvent.StartDate,
-		EndDate:               newEvent.EndDate,
-		MaxParticipants:       newEvent.MaxParticipants,
-		Description:           newEvent.Description,
-		Owner:                 ownerID,
-		Budget:                newEvent.Budget,
-		Image:                 newEvent.Image,
-		IsDeleted:             false,
-		CreatedAt:             currentTime,
-		UpdatedAt:             currentTime,
-		CustomizeFields:       customizeFields,
+		Tags:                   newEvent.Tags,
+		IsApproved:             false,
+		Reviewer:               nil,
+		IsFinished:             false,
+		Tasks:                  taskIds,
+		FacilityHistories:      facilityHistoryIds,
+		Name:                   newEvent.Name,
+		Language:               newEvent.Language,
+		EventType:              evenTypeID,
+		Mode:                   newEvent.Mode,
+		Location:               newEvent.Location,
+		Accommodation:          newEvent.Accommodation,
+		RegistrationCloseDate:  newEvent.RegistrationCloseDate,
+		StartDate:              newEvent.StartDate,
+		EndDate:                newEvent.EndDate,
+		MaxParticipants:        newEvent.MaxParticipants,
+		Description:            newEvent.Description,
+		Owner:                  ownerID,
+		Budget:                 newEvent.Budget,
+		Image:                  newEvent.Image,
+		IsDeleted:              false,
+		CreatedAt:              currentTime,
+		UpdatedAt:              currentTime,
+		CustomizeFields:        customizeFields,
+		CustomizeFieldsVersion: customizeFieldsVersion,
+		CustomizeFieldVersions: customizeFieldVersions,
 	}
 	//create event
 	createdEvent, err := u.EventRepository.Create(event)
@@ -165,6 +172,18 @@ func (u EventService) UpdateOne(filter bson.M, update model.UpdateEvent) (*model
 	if currentEvent == nil {
 		return nil, errors.New("event id not found")
 	}
+	if err := u.checkSessionsFit(currentEvent.ID, update.StartDate, update.EndDate); err != nil {
+		return nil, err
+	}
+	//ids are parsed first so a bad id does not leave tasks or facility histories half updated
+	event, err := u.FromUpdate(currentEvent, update)
+	if err != nil {
+		return nil, err
+	}
+	//the current bookings of the event are replaced by the update, so only other events can clash
+	if err := u.checkFacilityConflicts(update.FacilityHistories, bson.M{"event": currentEvent.ID}); err != nil {
+		return nil, err
+	}
 	backupTasks, err := u.TaskRepository.FindAll(bson.M{"event": currentEvent.ID})
 	if err != nil {
 		return nil, err
@@ -271,6 +290,7 @@ func (u EventService) UpdateOne(filter bson.M, update model.UpdateEvent) (*model
 					Facility:   facilityID,
 					BorrowDate: newFacilityHistory.BorrowDate,
 					ReturnDate: newFacilityHistory.ReturnDate,
+					Quantity:   u.facilityQuantity(newFacilityHistory),
 				})
 				if err != nil {
 					errDB = err
@@ -292,6 +312,7 @@ func (u EventService) UpdateOne(filter bson.M, update model.UpdateEvent) (*model
 				targetFacilityHistory.Facility = facilityID
 				targetFacilityHistory.BorrowDate = newFacilityHistory.BorrowDate
 				targetFacilityHistory.ReturnDate = newFacilityHistory.ReturnDate
+				targetFacilityHistory.Quantity = u.facilityQuantity(newFacilityHistory)
 				targetFacilityHistory.UpdatedAt = time.Now()
 				bsonFacilityHistory, err := utilities.InterfaceToBsonM(targetFacilityHistory)
 				if err != nil {
@@ -343,6 +364,45 @@ func (u EventService) UpdateOne(filter bson.M, update model.UpdateEvent) (*model
 		}
 	}
 
+	currentTime := time.Now()
+	event.Tasks = taskIds
+	event.FacilityHistories = facilityHistoryIds
+	event.UpdatedAt = currentTime
+	if update.IsApproved && event.ApprovedAt == nil {
+		event.ApprovedAt = &currentTime
+	}
+	bsonEvent, err := utilities.InterfaceToBsonM(event)
+	if err != nil {
+		return nil, err
+	}
+	//the id is only needed by the mails, it is not part of the update
+	event.ID = currentEvent.ID
+	outbox, err := u.statusMails(currentEvent, event)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedEvent, err := u.EventRepository.UpdateOne(filter, bsonEvent, outbox...)
+	if err != nil {
+		if err := u.rollbackForUpdateEvent(backupTasks, backupFacilityHistories, taskIds, facilityHistoryIds); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+	//a raised capacity frees seats for the waitlist, a cancelled event keeps them empty
+	if !updatedEvent.IsDeleted {
+		if _, err := u.ParticipantService.PromoteWaitlisted(updatedEvent.ID); err != nil {
+			return nil, err
+		}
+	}
+	return updatedEvent, nil
+}
+
+/*
+FromUpdate: an event with the fields of an update, keeping its creation and approval time.
+Its tasks and facility histories are left empty, the update does not change the stored event
+*/
+func (u *EventService) FromUpdate(currentEvent *models.Event, update model.UpdateEvent) (*models.Event, error) {
 	evenTypeID, err := primitive.ObjectIDFromHex(update.EventTypeID)
 	if err != nil {
 		return nil, err
@@ -360,62 +420,102 @@ func (u EventService) UpdateOne(filter bson.M, update model.UpdateEvent) (*model
 		reviewerID = &objectId
 	}
 
-	customizeFields := make([]*models.CustomizeField, 0)
-	for _, v := range update.CustomizeFields {
-		customizeFields = append(customizeFields, &models.CustomizeField{
-			Name:     v.Name,
-			Type:     v.Type,
-			Values:   v.Value,
-			Required: v.Required,
-		})
-	}
+	customizeFields := MapCustomizeFields(update.CustomizeFields)
+	customizeFieldsVersion, customizeFieldVersions := NextCustomizeFieldVersions(currentEvent, customizeFields)
+	return &models.Event{
+		Tags:                   update.Tags,
+		IsApproved:             update.IsApproved,
+		ApprovedAt:             currentEvent.ApprovedAt,
+		Reviewer:               reviewerID,
+		IsFinished:             update.IsFinished,
+		Tasks:                  make([]primitive.ObjectID, 0),
+		FacilityHistories:      make([]primitive.ObjectID, 0),
+		Name:                   update.Name,
+		Language:               update.Language,
+		EventType:              evenTypeID,
+		Mode:                   update.Mode,
+		Location:               update.Location,
+		Accommodation:          update.Accommodation,
+		RegistrationCloseDate:  update.RegistrationCloseDate,
+		StartDate:              update.StartDate,
+		EndDate:                update.EndDate,
+		MaxParticipants:        update.MaxParticipants,
+		Description:            update.Description,
+		Owner:                  ownerID,
+		Budget:                 update.Budget,
+		Image:                  update.Image,
+		IsDeleted:              update.IsDeleted,
+		CreatedAt:              currentEvent.CreatedAt,
+		UpdatedAt:              currentEvent.UpdatedAt,
+		CustomizeFields:        customizeFields,
+		CustomizeFieldsVersion: customizeFieldsVersion,
+		CustomizeFieldVersions: customizeFieldVersions,
+	}, nil
+}
 
-	//convert to bson.M
-	currentTime := time.Now()
-	event := models.Event{
-		Tags:                  update.Tags,
-		IsApproved:            update.IsApproved,
-		Reviewer:              reviewerID,
-		IsFinished:            update.IsFinished,
-		Tasks:                 taskIds,
-		FacilityHistories:     facilityHistoryIds,
-		Name:                  update.Name,
-		Language:              update.Language,
-		EventType:             evenTypeID,
-		Mode:                  update.Mode,
-		Location:              update.Location,
-		Accommodation:         update.Accommodation,
-		RegistrationCloseDate: update.RegistrationCloseDate,
-		StartDate:             update.StartDate,
-		EndDate:               update.EndDate,
-		MaxParticipants:       update.MaxParticipants,
-		Description:           update.Description,
-		Owner:                 ownerID,
-		Budget:                update.Budget,
-		Image:                 update.Image,
-		IsDeleted:             update.IsDeleted,
-		CreatedAt:             currentEvent.CreatedAt,
-		UpdatedAt:             currentTime,
-		CustomizeFields:       customizeFields,
+/*
+statusMails: the mails an update of an event triggers.
+The owner is told of the first approval, and every participant is told when the event is cancelled
+*/
+func (u *EventService) statusMails(currentEvent *models.Event, event *models.Event) ([]*models.OutboxMessage, error) {
+	outbox := make([]*models.OutboxMessage, 0)
+	if event.ApprovedAt != nil && currentEvent.ApprovedAt == nil {
+		owner, err := u.UserRepository.FindOne(bson.M{"_id": event.Owner})
+		if _, ok := err.(*helpers.ErrNotFound); err != nil && !ok {
+			return nil, err
+		}
+		if owner != nil {
+			outbox = append(outbox, models.NewUserOutboxMessage(models.OutboxApproval, event, owner))
+		}
 	}
-	bsonEvent, err := utilities.InterfaceToBsonM(event)
-	if err != nil {
-		return nil, err
+	if event.IsDeleted && !currentEvent.IsDeleted {
+		participants, err := u.ParticipantService.GetAll(bson.M{"event": currentEvent.ID})
+		if err != nil {
+			return nil, err
+		}
+		for _, participant := range participants {
+			outbox = append(outbox, models.NewOutboxMessage(models.OutboxCancellation, participant))
+		}
 	}
+	return outbox, nil
+}
 
-	updatedEvent, err := u.EventRepository.UpdateOne(filter, bsonEvent)
+/*ReorderCustomizeFields: put the customize fields of an event in the given order, answers are not affected*/
+func (u EventService) ReorderCustomizeFields(filter bson.M, names []string) (*models.Event, error) {
+	event, err := u.EventRepository.FindOne(filter)
 	if err != nil {
-		if err := u.rollbackForUpdateEvent(backupTasks, backupFacilityHistories, taskIds, facilityHistoryIds); err != nil {
-			return nil, err
-		}
 		return nil, err
 	}
-	return updatedEvent, nil
+	fields := make(map[string]*models.CustomizeField)
+	for _, v := range event.CustomizeFields {
+		fields[v.Name] = v
+	}
+	if len(names) != len(fields) {
+		return nil, helpers.NewErrValidation("every customize field must be listed exactly once")
+	}
+	customizeFields := make([]*models.CustomizeField, 0)
+	for i, name := range names {
+		field, ok := fields[name]
+		if !ok {
+			return nil, helpers.NewErrValidation("every customize field must be listed exactly once")
+		}
+		delete(fields, name)
+		field.Order = i
+		customizeFields = append(customizeFields, field)
+	}
+	return u.EventRepository.UpdateOne(bson.M{"_id": event.ID}, bson.M{"customizeField": customizeFields, "updatedAt": time.Now()})
 }
 
-//DeleteOne func is to update one record from a collection
+//DeleteOne func is to delete one record from a collection once its references are resolved
 func (u EventService) DeleteOne(filter bson.M) (*models.Event, error) {
-	return u.EventRepository.DeleteOne(filter)
+	event, err := u.EventRepository.FindOne(filter)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.ReferenceService.Delete(models.CollectionEventName, event.ID, nil); err != nil {
+		return nil, err
+	}
+	return event, nil
 }
 
 //validation
@@ -448,6 +548,9 @@ func (u *EventService) ValidateNewEvent(newEvent model.NewEvent) error {
 		validation.Field(&newEvent.OwnerID, validation.Required.Error("owner must not be blanked")),
 		validation.Field(&newEvent.Budget, validation.Required.Error("budget must not be blanked")),
 		validation.Field(&newEvent.Image, validation.Required.Error("image must not be blanked")),
+		validation.Field(&newEvent.CustomizeFields, validation.By(func(interface{}) error {
+			return ValidateCustomizeFields(newEvent.CustomizeFields)
+		})),
 	)
 }
 
@@ -496,9 +599,73 @@ func (u *EventService) ValidateUpdateEvent(id string, updateEvent model.UpdateEv
 		validation.Field(&updateEvent.OwnerID, validation.Required.Error("owner must not be blanked")),
 		validation.Field(&updateEvent.Budget, validation.Required.Error("budget must not be blanked")),
 		validation.Field(&updateEvent.Image, validation.Required.Error("image must not be blanked")),
+		validation.Field(&updateEvent.CustomizeFields, validation.By(func(interface{}) error {
+			return ValidateCustomizeFields(updateEvent.CustomizeFields)
+		})),
 	)
 }
 
+/*checkSessionsFit: make sure the agenda of an event still takes place between new start and end dates*/
+func (u *EventService) checkSessionsFit(eventId primitive.ObjectID, startDate time.Time, endDate time.Time) error {
+	outside, err := u.SessionRepository.FindAll(bson.M{"event": eventId, "$or": bson.A{
+		bson.M{"startDate": bson.M{"$lt": startDate}},
+		bson.M{"endDate": bson.M{"$gt": endDate}},
+	}})
+	if err != nil {
+		return err
+	}
+	if len(outside) == 0 {
+		return nil
+	}
+	titles := make([]string, 0)
+	for _, session := range outside {
+		titles = append(titles, session.Title)
+	}
+	return helpers.NewErrValidation(fmt.Sprintf("sessions would take place outside the event, move them first: %s", strings.Join(titles, ", ")))
+}
+
+/*checkFacilityConflicts: make sure the stock covers the facility histories of an event next to the other bookings*/
+func (u *EventService) checkFacilityConflicts(facilityHistories []*model.NewFacilityHistory, exclude bson.M) error {
+	//group the bookings per facility so bookings of the same event count together
+	facilityIds := make([]primitive.ObjectID, 0)
+	bookings := make(map[primitive.ObjectID][]*models.FacilityHistory)
+	for _, facilityHistory := range facilityHistories {
+		if err := ValidateBookingPeriod(facilityHistory.BorrowDate, facilityHistory.ReturnDate); err != nil {
+			return helpers.NewErrValidation(err.Error())
+		}
+		if err := validateQuantity(facilityHistory.Quantity); err != nil {
+			return helpers.NewErrValidation(err.Error())
+		}
+		facilityId, err := primitive.ObjectIDFromHex(facilityHistory.FacilityID)
+		if err != nil {
+			return err
+		}
+		if _, ok := bookings[facilityId]; !ok {
+			facilityIds = append(facilityIds, facilityId)
+		}
+		bookings[facilityId] = append(bookings[facilityId], &models.FacilityHistory{
+			Facility:   facilityId,
+			BorrowDate: facilityHistory.BorrowDate,
+			ReturnDate: facilityHistory.ReturnDate,
+			Quantity:   u.facilityQuantity(facilityHistory),
+		})
+	}
+	for _, facilityId := range facilityIds {
+		if err := u.FacilityHistoryService.CheckBookings(facilityId, bookings[facilityId], exclude); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*facilityQuantity: a booking without quantity borrows a single unit*/
+func (u *EventService) facilityQuantity(facilityHistory *model.NewFacilityHistory) int {
+	if facilityHistory.Quantity == nil {
+		return 1
+	}
+	return *facilityHistory.Quantity
+}
+
 /*rollbackForCreateEvent: remove all the tasks and facilityHistories that created to put into event when the event create fail*/
 func (u *EventService) rollbackForCreateEvent(objectIds []primitive.ObjectID, collectionName string) error {
 	if collectionName == models.CollectionTaskName {
@@ -606,6 +773,7 @@ func (u *EventService) createFacilityHistoriesForEvent(facilityHistories []*mode
 			Facility:   facilityId,
 			BorrowDate: facilityHistory.BorrowDate,
 			ReturnDate: facilityHistory.ReturnDate,
+			Quantity:   u.facilityQuantity(facilityHistory),
 		})
 		if err != nil {
 			errDB = err
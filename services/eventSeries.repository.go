@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/khanhvtn/netevent-go/database"
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var EventSeriesRepositoryName = "EventSeriesRepositoryName"
+
+type EventSeriesRepository struct {
+	MongoCN *database.MongoInstance
+}
+
+/* createContextAndTargetCol: create and return targeted collection based on collection name */
+func (u *EventSeriesRepository) createContextAndTargetCol(colName string) (col *mongo.Collection,
+	ctx context.Context,
+	cancel context.CancelFunc) {
+	col = u.MongoCN.Db.Collection(colName)
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	return
+}
+
+/* FindAll: get all data based on condition*/
+func (u *EventSeriesRepository) FindAll(condition bson.M) ([]*models.EventSeries, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionEventSeriesName)
+	defer cancel()
+
+	//create an empty array to store all fields from collection
+	var seriesList []*models.EventSeries = make([]*models.EventSeries, 0)
+
+	//get all record
+	cur, err := collection.Find(ctx, condition)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	//map data to target variable
+	for cur.Next(ctx) {
+		var series models.EventSeries
+		cur.Decode(&series)
+		seriesList = append(seriesList, &series)
+	}
+	//response data to client
+	if seriesList == nil {
+		return make([]*models.EventSeries, 0), nil
+	}
+	return seriesList, nil
+}
+
+/*FindOne: get one record from a collection  */
+func (u *EventSeriesRepository) FindOne(filter bson.M) (*models.EventSeries, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionEventSeriesName)
+	defer cancel()
+
+	series := models.EventSeries{}
+	//Decode record into result
+	if err := collection.FindOne(ctx, filter).Decode(&series); err != nil {
+		if err == mongo.ErrNoDocuments {
+			//return nil data when id is not existed.
+			return nil, helpers.NewErrNotFound("event series id is not found")
+		}
+		//return err if there is a system error
+		return nil, err
+	}
+
+	return &series, nil
+}
+
+/*Create: create a new record to a collection*/
+func (u *EventSeriesRepository) Create(series *models.EventSeries) (*models.EventSeries, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionEventSeriesName)
+	defer cancel()
+
+	if _, err := collection.InsertOne(ctx, series); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+/*UpdateOne: update one record from a collection*/
+func (u EventSeriesRepository) UpdateOne(filter bson.M, update bson.M) (*models.EventSeries, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionEventSeriesName)
+	defer cancel()
+
+	//update event series information
+	newUpdate := bson.M{"$set": update}
+	updateResult, err := collection.UpdateOne(ctx, filter, newUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return nil, helpers.NewErrNotFound("event series id is not found")
+	}
+
+	//query the new update
+	series, errQuery := u.FindOne(filter)
+	if errQuery != nil {
+		return nil, errQuery
+	}
+
+	return series, nil
+}
+
+//DeleteOne func is to update one record from a collection
+func (u EventSeriesRepository) DeleteOne(filter bson.M) (*models.EventSeries, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionEventSeriesName)
+	defer cancel()
+
+	series, errFind := u.FindOne(filter)
+	if errFind != nil {
+		return nil, errFind
+	}
+
+	//delete event series from database
+	deleteResult, err := collection.DeleteOne(ctx, filter)
+	if err != nil {
+		//response to client if there is an error.
+		return nil, err
+	}
+
+	if deleteResult.DeletedCount == 0 {
+		return nil, helpers.NewErrNotFound("event series id is not found")
+	}
+
+	return series, nil
+}
+
+/*AddException: record an occurrence removed from a series*/
+func (u *EventSeriesRepository) AddException(seriesId primitive.ObjectID, occurrence time.Time) (*models.EventSeries, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionEventSeriesName)
+	defer cancel()
+
+	filter := bson.M{"_id": seriesId}
+	updateResult, err := collection.UpdateOne(ctx, filter, bson.M{
+		"$addToSet": bson.M{"exceptions": occurrence},
+		"$set":      bson.M{"updatedAt": time.Now()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if updateResult.MatchedCount == 0 {
+		return nil, helpers.NewErrNotFound("event series id is not found")
+	}
+	return u.FindOne(filter)
+}
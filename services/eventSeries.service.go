@@ -0,0 +1,447 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var EventSeriesServiceName = "EventSeriesServiceName"
+
+/*
+EventSeriesService: creates the occurrences of recurring events and applies changes across them.
+Occurrences are plain events, each one gets its own copy of the tasks and facility histories of the new event
+*/
+type EventSeriesService struct {
+	EventSeriesRepository     *EventSeriesRepository
+	EventRepository           *EventRepository
+	TaskRepository            *TaskRepository
+	FacilityHistoryRepository *FacilityHistoryRepository
+	EventService              *EventService
+	ReferenceService          *ReferenceService
+	ParticipantService        *ParticipantService
+	//rules are expanded in wall clock time of this location
+	Location *time.Location
+}
+
+/*GetOne: get one record from a collection  */
+func (u *EventSeriesService) GetOne(filter bson.M) (*models.EventSeries, error) {
+	return u.EventSeriesRepository.FindOne(filter)
+}
+
+/*Events: the occurrences of a series in start order*/
+func (u *EventSeriesService) Events(seriesId primitive.ObjectID) ([]*models.Event, error) {
+	events, err := u.EventRepository.FindAll(bson.M{"series": seriesId})
+	if err != nil {
+		return nil, err
+	}
+	sortOccurrences(events)
+	return events, nil
+}
+
+/*Occurrences: the occurrences of the series of an event a change in scope applies to, the event alone outside a series*/
+func (u *EventSeriesService) Occurrences(event *models.Event, scope string) ([]*models.Event, error) {
+	if event.Series == nil || event.OccurrenceStart == nil || scope == models.RecurrenceScopeThis {
+		return []*models.Event{event}, nil
+	}
+	condition := bson.M{"series": *event.Series}
+	if scope == models.RecurrenceScopeThisAndFollowing {
+		condition["occurrenceStart"] = bson.M{"$gte": *event.OccurrenceStart}
+	}
+	events, err := u.EventRepository.FindAll(condition)
+	if err != nil {
+		return nil, err
+	}
+	sortOccurrences(events)
+	return events, nil
+}
+
+/*
+Repeat: create the other occurrences of a new event and tie them together in a series.
+Every occurrence is created like a new event, so the facilities it books must be available at its own dates
+*/
+func (u *EventSeriesService) Repeat(event *models.Event, recurrence model.RecurrenceInput) (*models.Event, error) {
+	rule, starts, err := u.expand(event.StartDate, recurrence)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := u.TaskRepository.FindAll(bson.M{"event": event.ID})
+	if err != nil {
+		return nil, err
+	}
+	facilityHistories, err := u.FacilityHistoryRepository.FindAll(bson.M{"event": event.ID})
+	if err != nil {
+		return nil, err
+	}
+	currentTime := time.Now()
+	series, err := u.EventSeriesRepository.Create(&models.EventSeries{
+		ID:         primitive.NewObjectID(),
+		CreatedAt:  currentTime,
+		UpdatedAt:  currentTime,
+		Rule:       rule.String(),
+		Start:      event.StartDate,
+		Exceptions: recurrenceExceptions(recurrence.Exceptions),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	createdIds := make([]primitive.ObjectID, 0)
+	for _, start := range starts[1:] {
+		createdEvent, err := u.EventService.Create(u.occurrence(event, tasks, facilityHistories, start))
+		if err != nil {
+			u.rollbackRepeat(series.ID, createdIds)
+			return nil, occurrenceError(start, u.Location, err)
+		}
+		createdIds = append(createdIds, createdEvent.ID)
+		if _, err := u.EventRepository.UpdateOne(bson.M{"_id": createdEvent.ID}, bson.M{
+			"series":          series.ID,
+			"occurrenceStart": start,
+		}); err != nil {
+			u.rollbackRepeat(series.ID, createdIds)
+			return nil, err
+		}
+	}
+	//the first event joins the series last, a failure leaves it as a single event
+	updatedEvent, err := u.EventRepository.UpdateOne(bson.M{"_id": event.ID}, bson.M{
+		"series":          series.ID,
+		"occurrenceStart": event.StartDate,
+	})
+	if err != nil {
+		u.rollbackRepeat(series.ID, createdIds)
+		return nil, err
+	}
+	return updatedEvent, nil
+}
+
+/*
+UpdateOne: update an occurrence and the other occurrences in scope.
+They take the same fields, and the new dates keep their distance in days to the updated occurrence,
+their tasks and facility histories move with them and the facilities must be available at the new dates.
+Everything is checked first, then the other occurrences change in one transaction before the updated one.
+They are put back when the updated occurrence cannot be saved, its own mails are only queued when it is
+*/
+func (u *EventSeriesService) UpdateOne(id primitive.ObjectID, update model.UpdateEvent, scope string) (*models.Event, error) {
+	currentEvent, err := u.EventRepository.FindOne(bson.M{"_id": id})
+	if err != nil {
+		return nil, err
+	}
+	occurrences, err := u.Occurrences(currentEvent, scope)
+	if err != nil {
+		return nil, err
+	}
+	event, err := u.EventService.FromUpdate(currentEvent, update)
+	if err != nil {
+		return nil, err
+	}
+	currentTime := time.Now()
+	writes, restores := newSeriesWrites(), newSeriesWrites()
+	//the bookings of every moving occurrence are checked together, they are excluded from the stored ones
+	moving := []primitive.ObjectID{currentEvent.ID}
+	bookings := append([]*model.NewFacilityHistory{}, update.FacilityHistories...)
+	others := make([]*models.Event, 0)
+	for _, occurrence := range occurrences {
+		if occurrence.ID == currentEvent.ID {
+			continue
+		}
+		others = append(others, occurrence)
+		days := models.LocalDaysBetween(currentEvent.StartDate, occurrence.StartDate, u.Location)
+		bsonUpdate := u.seriesFields(event, occurrence, days)
+		if err := u.EventService.checkSessionsFit(occurrence.ID, bsonUpdate["startDate"].(time.Time), bsonUpdate["endDate"].(time.Time)); err != nil {
+			return nil, occurrenceError(occurrence.StartDate, u.Location, err)
+		}
+		bsonUpdate["updatedAt"] = currentTime
+		if event.IsApproved && occurrence.ApprovedAt == nil {
+			bsonUpdate["approvedAt"] = currentTime
+		}
+		writes.events[occurrence.ID] = bsonUpdate
+		restores.events[occurrence.ID] = u.restoreFields(occurrence)
+
+		//tasks and facility histories keep their distance to the start of their occurrence
+		shift := bsonUpdate["startDate"].(time.Time).Sub(occurrence.StartDate)
+		if shift == 0 {
+			continue
+		}
+		moving = append(moving, occurrence.ID)
+		tasks, err := u.TaskRepository.FindAll(bson.M{"event": occurrence.ID})
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range tasks {
+			writes.tasks[task.ID] = bson.M{"startDate": task.StartDate.Add(shift), "endDate": task.EndDate.Add(shift), "updatedAt": currentTime}
+			restores.tasks[task.ID] = bson.M{"startDate": task.StartDate, "endDate": task.EndDate, "updatedAt": task.UpdatedAt}
+		}
+		facilityHistories, err := u.FacilityHistoryRepository.FindAll(bson.M{"event": occurrence.ID})
+		if err != nil {
+			return nil, err
+		}
+		for _, facilityHistory := range facilityHistories {
+			quantity := facilityHistory.Units()
+			borrowDate, returnDate := facilityHistory.BorrowDate.Add(shift), facilityHistory.ReturnDate.Add(shift)
+			bookings = append(bookings, &model.NewFacilityHistory{
+				FacilityID: facilityHistory.Facility.Hex(),
+				BorrowDate: borrowDate,
+				ReturnDate: returnDate,
+				Quantity:   &quantity,
+			})
+			writes.facilityHistories[facilityHistory.ID] = bson.M{"borrowDate": borrowDate, "returnDate": returnDate, "updatedAt": currentTime}
+			restores.facilityHistories[facilityHistory.ID] = bson.M{"borrowDate": facilityHistory.BorrowDate, "returnDate": facilityHistory.ReturnDate, "updatedAt": facilityHistory.UpdatedAt}
+		}
+	}
+	if len(moving) > 1 {
+		if err := u.EventService.checkFacilityConflicts(bookings, bson.M{"event": bson.M{"$in": moving}}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.EventRepository.UpdateEach(writes.events, writes.tasks, writes.facilityHistories); err != nil {
+		return nil, err
+	}
+	updatedEvent, err := u.EventService.UpdateOne(bson.M{"_id": id}, update)
+	if err != nil {
+		if restoreErr := u.EventRepository.UpdateEach(restores.events, restores.tasks, restores.facilityHistories); restoreErr != nil {
+			return nil, restoreErr
+		}
+		return nil, err
+	}
+	for _, occurrence := range others {
+		//a raised capacity frees seats for the waitlist
+		if _, err := u.ParticipantService.PromoteWaitlisted(occurrence.ID); err != nil {
+			return nil, err
+		}
+	}
+	return updatedEvent, nil
+}
+
+/* seriesWrites: the updates of the events, tasks and facility histories of a series, applied together */
+type seriesWrites struct {
+	events            map[primitive.ObjectID]bson.M
+	tasks             map[primitive.ObjectID]bson.M
+	facilityHistories map[primitive.ObjectID]bson.M
+}
+
+/* newSeriesWrites: writes with nothing to update yet */
+func newSeriesWrites() *seriesWrites {
+	return &seriesWrites{
+		events:            make(map[primitive.ObjectID]bson.M),
+		tasks:             make(map[primitive.ObjectID]bson.M),
+		facilityHistories: make(map[primitive.ObjectID]bson.M),
+	}
+}
+
+/* restoreFields: the fields putting an occurrence back as it is now */
+func (u *EventSeriesService) restoreFields(occurrence *models.Event) bson.M {
+	restore := u.seriesFields(occurrence, occurrence, 0)
+	restore["customizeFieldsVersion"] = occurrence.CustomizeFieldsVersion
+	restore["customizeFieldVersions"] = occurrence.CustomizeFieldVersions
+	restore["approvedAt"] = occurrence.ApprovedAt
+	restore["updatedAt"] = occurrence.UpdatedAt
+	return restore
+}
+
+/* seriesFields: the fields an occurrence shares with the rest of its series, taken from event and moved by a number of days */
+func (u *EventSeriesService) seriesFields(event *models.Event, occurrence *models.Event, days int) bson.M {
+	customizeFieldsVersion, customizeFieldVersions := NextCustomizeFieldVersions(occurrence, event.CustomizeFields)
+	return bson.M{
+		"tags":                   event.Tags,
+		"isApproved":             event.IsApproved,
+		"reviewer":               event.Reviewer,
+		"name":                   event.Name,
+		"language":               event.Language,
+		"eventType":              event.EventType,
+		"mode":                   event.Mode,
+		"location":               event.Location,
+		"accommodation":          event.Accommodation,
+		"registrationCloseDate":  models.AddLocalDays(event.RegistrationCloseDate, days, u.Location),
+		"startDate":              models.AddLocalDays(event.StartDate, days, u.Location),
+		"endDate":                models.AddLocalDays(event.EndDate, days, u.Location),
+		"maxParticipants":        event.MaxParticipants,
+		"description":            event.Description,
+		"owner":                  event.Owner,
+		"budget":                 event.Budget,
+		"image":                  event.Image,
+		"customizeField":         event.CustomizeFields,
+		"customizeFieldsVersion": customizeFieldsVersion,
+		"customizeFieldVersions": customizeFieldVersions,
+	}
+}
+
+/*
+DeleteOne: delete an occurrence and the other occurrences in scope once all of their references are resolved.
+The series remembers occurrences deleted alone as exceptions and ends before the ones deleted with the following
+*/
+func (u *EventSeriesService) DeleteOne(id primitive.ObjectID, scope string) (*models.Event, error) {
+	event, err := u.EventRepository.FindOne(bson.M{"_id": id})
+	if err != nil {
+		return nil, err
+	}
+	occurrences, err := u.Occurrences(event, scope)
+	if err != nil {
+		return nil, err
+	}
+	//nothing is deleted unless every occurrence can be
+	for _, occurrence := range occurrences {
+		report, err := u.ReferenceService.Plan(models.CollectionEventName, occurrence.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := u.ReferenceService.Conflict(report); err != nil {
+			return nil, helpers.NewErrConflict(fmt.Sprintf("occurrence of %s: %s", occurrence.StartDate.In(u.Location).Format("2006-01-02"), err.Error()))
+		}
+	}
+	for _, occurrence := range occurrences {
+		if _, err := u.EventService.DeleteOne(bson.M{"_id": occurrence.ID}); err != nil {
+			return nil, err
+		}
+	}
+	if event.Series == nil || event.OccurrenceStart == nil {
+		return event, nil
+	}
+	return event, u.trim(*event.Series, *event.OccurrenceStart, scope)
+}
+
+/* trim: record the occurrences removed from a series, the series goes with its last occurrence */
+func (u *EventSeriesService) trim(seriesId primitive.ObjectID, occurrenceStart time.Time, scope string) error {
+	events, err := u.EventRepository.FindAll(bson.M{"series": seriesId})
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		_, err := u.EventSeriesRepository.DeleteOne(bson.M{"_id": seriesId})
+		return err
+	}
+	if scope == models.RecurrenceScopeThis {
+		_, err := u.EventSeriesRepository.AddException(seriesId, occurrenceStart)
+		return err
+	}
+	series, err := u.EventSeriesRepository.FindOne(bson.M{"_id": seriesId})
+	if err != nil {
+		return err
+	}
+	rule, err := models.ParseRecurrenceRule(series.Rule, u.Location)
+	if err != nil {
+		return err
+	}
+	until := occurrenceStart.Add(-time.Second)
+	rule.Count = 0
+	rule.Until = &until
+	_, err = u.EventSeriesRepository.UpdateOne(bson.M{"_id": seriesId}, bson.M{"rule": rule.String(), "updatedAt": time.Now()})
+	return err
+}
+
+/*
+occurrence: a new event copying an event with its tasks and facility histories, moved to another start.
+Every date keeps its wall clock time and moves by the same number of days, registrations are not copied
+*/
+func (u *EventSeriesService) occurrence(event *models.Event, tasks []*models.Task, facilityHistories []*models.FacilityHistory, start time.Time) model.NewEvent {
+	days := models.LocalDaysBetween(event.StartDate, start, u.Location)
+	newEvent := model.NewEvent{
+		Tags:                  event.Tags,
+		Tasks:                 make([]*model.NewTask, 0),
+		FacilityHistories:     make([]*model.NewFacilityHistory, 0),
+		Name:                  event.Name,
+		Language:              event.Language,
+		EventTypeID:           event.EventType.Hex(),
+		Mode:                  event.Mode,
+		Location:              event.Location,
+		Accommodation:         event.Accommodation,
+		RegistrationCloseDate: models.AddLocalDays(event.RegistrationCloseDate, days, u.Location),
+		StartDate:             start,
+		EndDate:               models.AddLocalDays(event.EndDate, days, u.Location),
+		MaxParticipants:       event.MaxParticipants,
+		Description:           event.Description,
+		OwnerID:               event.Owner.Hex(),
+		Budget:                event.Budget,
+		Image:                 event.Image,
+		CustomizeFields:       InputCustomizeFields(event.CustomizeFields),
+	}
+	for _, task := range tasks {
+		newEvent.Tasks = append(newEvent.Tasks, &model.NewTask{
+			Name:      task.Name,
+			UserID:    task.User.Hex(),
+			Type:      task.Type,
+			StartDate: models.AddLocalDays(task.StartDate, days, u.Location),
+			EndDate:   models.AddLocalDays(task.EndDate, days, u.Location),
+		})
+	}
+	for _, facilityHistory := range facilityHistories {
+		quantity := facilityHistory.Units()
+		newEvent.FacilityHistories = append(newEvent.FacilityHistories, &model.NewFacilityHistory{
+			FacilityID: facilityHistory.Facility.Hex(),
+			BorrowDate: models.AddLocalDays(facilityHistory.BorrowDate, days, u.Location),
+			ReturnDate: models.AddLocalDays(facilityHistory.ReturnDate, days, u.Location),
+			Quantity:   &quantity,
+		})
+	}
+	return newEvent
+}
+
+/* rollbackRepeat: remove the occurrences created for a series which could not be completed, with their tasks and facility histories */
+func (u *EventSeriesService) rollbackRepeat(seriesId primitive.ObjectID, createdIds []primitive.ObjectID) {
+	for _, v := range createdIds {
+		u.EventService.DeleteOne(bson.M{"_id": v})
+	}
+	u.EventSeriesRepository.DeleteOne(bson.M{"_id": seriesId})
+}
+
+/* expand: the rule of a recurrence and the starts it gives from the start of the first occurrence */
+func (u *EventSeriesService) expand(start time.Time, recurrence model.RecurrenceInput) (*models.RecurrenceRule, []time.Time, error) {
+	rule, err := models.ParseRecurrenceRule(recurrence.Rule, u.Location)
+	if err != nil {
+		return nil, nil, helpers.NewErrValidation(err.Error())
+	}
+	exceptions := recurrenceExceptions(recurrence.Exceptions)
+	if models.IsRecurrenceException(start, u.Location, exceptions) {
+		return nil, nil, helpers.NewErrValidation("the start of the event cannot be an exception")
+	}
+	starts, err := rule.Occurrences(start, u.Location, exceptions)
+	if err != nil {
+		return nil, nil, helpers.NewErrValidation(err.Error())
+	}
+	return rule, starts, nil
+}
+
+/* occurrenceError: an error about one occurrence of a series, named after its start day */
+func occurrenceError(start time.Time, location *time.Location, err error) error {
+	message := fmt.Sprintf("occurrence of %s: %s", start.In(location).Format("2006-01-02"), err.Error())
+	switch err.(type) {
+	case *helpers.ErrConflict:
+		return helpers.NewErrConflict(message)
+	case *helpers.ErrValidation:
+		return helpers.NewErrValidation(message)
+	}
+	return err
+}
+
+/* recurrenceExceptions: the exceptions given to a recurrence */
+func recurrenceExceptions(exceptions []*time.Time) []time.Time {
+	results := make([]time.Time, 0)
+	for _, v := range exceptions {
+		if v != nil {
+			results = append(results, *v)
+		}
+	}
+	return results
+}
+
+/* sortOccurrences: put occurrences in start order */
+func sortOccurrences(events []*models.Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].StartDate.Before(events[j].StartDate)
+	})
+}
+
+//validation
+func (u *EventSeriesService) ValidateRecurrence(start time.Time, recurrence *model.RecurrenceInput) error {
+	if recurrence == nil {
+		return nil
+	}
+	_, _, err := u.expand(start, *recurrence)
+	return err
+}
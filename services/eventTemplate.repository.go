@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/khanhvtn/netevent-go/database"
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var EventTemplateRepositoryName = "EventTemplateRepositoryName"
+
+type EventTemplateRepository struct {
+	MongoCN *database.MongoInstance
+}
+
+/* createContextAndTargetCol: create and return targeted collection based on collection name */
+func (u *EventTemplateRepository) createContextAndTargetCol(colName string) (col *mongo.Collection,
+	ctx context.Context,
+	cancel context.CancelFunc) {
+	col = u.MongoCN.Db.Collection(colName)
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	return
+}
+
+/* FindAll: get all data based on condition*/
+func (u *EventTemplateRepository) FindAll(condition bson.M) ([]*models.EventTemplate, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionEventTemplateName)
+	defer cancel()
+
+	//create an empty array to store all fields from collection
+	var templates []*models.EventTemplate = make([]*models.EventTemplate, 0)
+
+	//get all record
+	cur, err := collection.Find(ctx, condition)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	//map data to target variable
+	for cur.Next(ctx) {
+		var template models.EventTemplate
+		cur.Decode(&template)
+		templates = append(templates, &template)
+	}
+	//response data to client
+	if templates == nil {
+		return make([]*models.EventTemplate, 0), nil
+	}
+	return templates, nil
+}
+
+/*FindOne: get one record from a collection  */
+func (u *EventTemplateRepository) FindOne(filter bson.M) (*models.EventTemplate, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionEventTemplateName)
+	defer cancel()
+
+	template := models.EventTemplate{}
+	//Decode record into result
+	if err := collection.FindOne(ctx, filter).Decode(&template); err != nil {
+		if err == mongo.ErrNoDocuments {
+			//return nil data when id is not existed.
+			return nil, helpers.NewErrNotFound("event template id is not found")
+		}
+		//return err if there is a system error
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+/*Create: create a new record to a collection*/
+func (u *EventTemplateRepository) Create(template *models.EventTemplate) (*models.EventTemplate, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionEventTemplateName)
+	defer cancel()
+
+	if _, err := collection.InsertOne(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+/*UpdateOne: update one record from a collection*/
+func (u EventTemplateRepository) UpdateOne(filter bson.M, update bson.M) (*models.EventTemplate, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionEventTemplateName)
+	defer cancel()
+
+	//update event template information
+	newUpdate := bson.M{"$set": update}
+	updateResult, err := collection.UpdateOne(ctx, filter, newUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return nil, helpers.NewErrNotFound("event template id is not found")
+	}
+
+	//query the new update
+	template, errQuery := u.FindOne(filter)
+	if errQuery != nil {
+		return nil, errQuery
+	}
+
+	return template, nil
+}
+
+//DeleteOne func is to update one record from a collection
+func (u EventTemplateRepository) DeleteOne(filter bson.M) (*models.EventTemplate, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionEventTemplateName)
+	defer cancel()
+
+	template, errFind := u.FindOne(filter)
+	if errFind != nil {
+		return nil, errFind
+	}
+
+	//delete event template from database
+	deleteResult, err := collection.DeleteOne(ctx, filter)
+	if err != nil {
+		//response to client if there is an error.
+		return nil, err
+	}
+
+	if deleteResult.DeletedCount == 0 {
+		return nil, helpers.NewErrNotFound("event template id is not found")
+	}
+
+	return template, nil
+}
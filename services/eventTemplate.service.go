@@ -0,0 +1,234 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var EventTemplateServiceName = "EventTemplateServiceName"
+
+/*
+EventTemplateService: keeps events as templates and creates events from templates or from other events.
+Tasks and facility histories are copied with the same distance to the start of the new event
+*/
+type EventTemplateService struct {
+	EventTemplateRepository   *EventTemplateRepository
+	EventRepository           *EventRepository
+	TaskRepository            *TaskRepository
+	FacilityHistoryRepository *FacilityHistoryRepository
+	EventService              *EventService
+	ReferenceService          *ReferenceService
+	UserRepository            *UserRepository
+}
+
+/* GetAll: get all data based on condition*/
+func (u *EventTemplateService) GetAll(condition bson.M) ([]*models.EventTemplate, error) {
+	return u.EventTemplateRepository.FindAll(condition)
+}
+
+/*GetOne: get one record from a collection  */
+func (u *EventTemplateService) GetOne(filter bson.M) (*models.EventTemplate, error) {
+	return u.EventTemplateRepository.FindOne(filter)
+}
+
+/*Create: keep an event with its tasks and facility histories as a template*/
+func (u *EventTemplateService) Create(newEventTemplate model.NewEventTemplate) (*models.EventTemplate, error) {
+	eventId, err := primitive.ObjectIDFromHex(newEventTemplate.EventID)
+	if err != nil {
+		return nil, err
+	}
+	template, err := u.fromEvent(eventId, newEventTemplate.Name)
+	if err != nil {
+		return nil, err
+	}
+	currentTime := time.Now()
+	template.ID = primitive.NewObjectID()
+	template.CreatedAt = currentTime
+	template.UpdatedAt = currentTime
+	return u.EventTemplateRepository.Create(template)
+}
+
+//DeleteOne func is to delete one record from a collection once its references are resolved
+func (u *EventTemplateService) DeleteOne(filter bson.M) (*models.EventTemplate, error) {
+	template, err := u.EventTemplateRepository.FindOne(filter)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.ReferenceService.Delete(models.CollectionEventTemplateName, template.ID, nil); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+/*Instantiate: create an event from a template*/
+func (u *EventTemplateService) Instantiate(id primitive.ObjectID, overrides model.EventOverrides) (*models.Event, error) {
+	template, err := u.GetOne(bson.M{"_id": id})
+	if err != nil {
+		return nil, err
+	}
+	return u.createEvent(template, overrides)
+}
+
+/*Clone: create a copy of an event with its tasks, facility histories and customize fields, moved to a new start*/
+func (u *EventTemplateService) Clone(eventId primitive.ObjectID, overrides model.EventOverrides) (*models.Event, error) {
+	template, err := u.fromEvent(eventId, "")
+	if err != nil {
+		return nil, err
+	}
+	return u.createEvent(template, overrides)
+}
+
+/* fromEvent: the template of an event with its current tasks and facility histories */
+func (u *EventTemplateService) fromEvent(eventId primitive.ObjectID, name string) (*models.EventTemplate, error) {
+	event, err := u.EventRepository.FindOne(bson.M{"_id": eventId})
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := u.TaskRepository.FindAll(bson.M{"event": event.ID})
+	if err != nil {
+		return nil, err
+	}
+	facilityHistories, err := u.FacilityHistoryRepository.FindAll(bson.M{"event": event.ID})
+	if err != nil {
+		return nil, err
+	}
+	return models.NewEventTemplate(name, event, tasks, facilityHistories), nil
+}
+
+/*
+createEvent: create an event from a template placed at the start of the overrides.
+The end and the registration close keep their distance to the start unless they are overridden,
+the event goes through the same facility checks as a new event
+*/
+func (u *EventTemplateService) createEvent(template *models.EventTemplate, overrides model.EventOverrides) (*models.Event, error) {
+	start := overrides.StartDate
+	newEvent := model.NewEvent{
+		Tags:                  template.Tags,
+		Tasks:                 make([]*model.NewTask, 0),
+		FacilityHistories:     make([]*model.NewFacilityHistory, 0),
+		Name:                  overrides.Name,
+		Language:              template.Language,
+		EventTypeID:           template.EventType.Hex(),
+		Mode:                  template.Mode,
+		Location:              template.Location,
+		Accommodation:         template.Accommodation,
+		RegistrationCloseDate: models.TemplateDate(start, -template.RegistrationCloseLead),
+		StartDate:             start,
+		EndDate:               models.TemplateDate(start, template.Duration),
+		MaxParticipants:       template.MaxParticipants,
+		Description:           template.Description,
+		OwnerID:               template.Owner.Hex(),
+		Budget:                template.Budget,
+		Image:                 template.Image,
+		CustomizeFields:       InputCustomizeFields(template.CustomizeFields),
+	}
+	if overrides.EndDate != nil {
+		newEvent.EndDate = *overrides.EndDate
+	}
+	if overrides.RegistrationCloseDate != nil {
+		newEvent.RegistrationCloseDate = *overrides.RegistrationCloseDate
+	}
+	if overrides.OwnerID != nil {
+		newEvent.OwnerID = *overrides.OwnerID
+	}
+	if overrides.Location != nil {
+		newEvent.Location = *overrides.Location
+	}
+	if overrides.MaxParticipants != nil {
+		newEvent.MaxParticipants = *overrides.MaxParticipants
+	}
+	for _, task := range template.Tasks {
+		newEvent.Tasks = append(newEvent.Tasks, &model.NewTask{
+			Name:      task.Name,
+			UserID:    task.User.Hex(),
+			Type:      task.Type,
+			StartDate: models.TemplateDate(start, task.StartOffset),
+			EndDate:   models.TemplateDate(start, task.EndOffset),
+		})
+	}
+	for _, facility := range template.Facilities {
+		quantity := facility.Quantity
+		newEvent.FacilityHistories = append(newEvent.FacilityHistories, &model.NewFacilityHistory{
+			FacilityID: facility.Facility.Hex(),
+			BorrowDate: models.TemplateDate(start, facility.BorrowOffset),
+			ReturnDate: models.TemplateDate(start, facility.ReturnOffset),
+			Quantity:   &quantity,
+		})
+	}
+	return u.EventService.Create(newEvent)
+}
+
+//validation
+func (u *EventTemplateService) ValidateNewEventTemplate(newEventTemplate model.NewEventTemplate) error {
+	return validation.ValidateStruct(&newEventTemplate,
+		validation.Field(&newEventTemplate.EventID, validation.Required.Error("event id must not be blanked")),
+		validation.Field(&newEventTemplate.Name, validation.Required.Error("name must not be blanked"), validation.By(func(name interface{}) error {
+			template, err := u.GetOne(bson.M{"name": name.(string)})
+			if _, ok := err.(*helpers.ErrNotFound); err != nil && !ok {
+				return err
+			}
+			if template != nil {
+				return errors.New("name already existed")
+			}
+			return nil
+		})),
+	)
+}
+
+func (u *EventTemplateService) ValidateEventOverrides(overrides model.EventOverrides) error {
+	return validation.ValidateStruct(&overrides,
+		validation.Field(&overrides.Name, validation.Required.Error("name must not be blanked"), validation.By(func(name interface{}) error {
+			event, err := u.EventService.GetOne(bson.M{"name": name.(string)})
+			if _, ok := err.(*helpers.ErrNotFound); err != nil && !ok {
+				return err
+			}
+			if event != nil {
+				return errors.New("name already existed")
+			}
+			return nil
+		})),
+		validation.Field(&overrides.StartDate, validation.Required.Error("start date must not be blanked")),
+		validation.Field(&overrides.EndDate, validation.By(func(interface{}) error {
+			if overrides.EndDate != nil && !overrides.EndDate.After(overrides.StartDate) {
+				return errors.New("end date must be after the start date")
+			}
+			return nil
+		})),
+		validation.Field(&overrides.RegistrationCloseDate, validation.By(func(interface{}) error {
+			if overrides.RegistrationCloseDate != nil && overrides.RegistrationCloseDate.After(overrides.StartDate) {
+				return errors.New("registration close date must not be after the start date")
+			}
+			return nil
+		})),
+		validation.Field(&overrides.OwnerID, validation.By(func(interface{}) error {
+			if overrides.OwnerID == nil {
+				return nil
+			}
+			//the owner is checked before the event creates its tasks and facility histories
+			ownerId, err := primitive.ObjectIDFromHex(*overrides.OwnerID)
+			if err != nil {
+				return errors.New("invalid id")
+			}
+			if _, err := u.UserRepository.FindOne(bson.M{"_id": ownerId}); err != nil {
+				if _, ok := err.(*helpers.ErrNotFound); ok {
+					return errors.New("owner is not found")
+				}
+				return err
+			}
+			return nil
+		})),
+		validation.Field(&overrides.MaxParticipants, validation.By(func(interface{}) error {
+			if overrides.MaxParticipants != nil && *overrides.MaxParticipants < 1 {
+				return errors.New("max participants must be positive")
+			}
+			return nil
+		})),
+	)
+}
@@ -15,6 +15,7 @@ var EventTypeServiceName = "EventTypeServiceName"
 
 type EventTypeService struct {
 	EventTypeRepository *EventTypeRepository
+	ReferenceService    *ReferenceService
 }
 
 /* GetAll: get all data based on condition*/
@@ -41,9 +42,16 @@ func (u EventTypeService) UpdateOne(filter bson.M, update model.UpdateEventType)
 	return u.EventTypeRepository.UpdateOne(filter, bsonUpdate)
 }
 
-//DeleteOne func is to update one record from a collection
+//DeleteOne func is to delete one record from a collection once its references are resolved
 func (u EventTypeService) DeleteOne(filter bson.M) (*models.EventType, error) {
-	return u.EventTypeRepository.DeleteOne(filter)
+	eventType, err := u.EventTypeRepository.FindOne(filter)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.ReferenceService.Delete(models.CollectionEventTypeName, eventType.ID, nil); err != nil {
+		return nil, err
+	}
+	return eventType, nil
 }
 
 //validation
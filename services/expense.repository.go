@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/khanhvtn/netevent-go/database"
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ExpenseRepositoryName = "ExpenseRepositoryName"
+
+type ExpenseRepository struct {
+	MongoCN *database.MongoInstance
+}
+
+/* createContextAndTargetCol: create and return targeted collection based on collection name */
+func (u *ExpenseRepository) createContextAndTargetCol(colName string) (col *mongo.Collection,
+	ctx context.Context,
+	cancel context.CancelFunc) {
+	col = u.MongoCN.Db.Collection(colName)
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	return
+}
+
+/* FindAll: get all data based on condition*/
+func (u *ExpenseRepository) FindAll(condition bson.M) ([]*models.Expense, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionExpenseName)
+	defer cancel()
+
+	//create an empty array to store all fields from collection
+	var expenses []*models.Expense = make([]*models.Expense, 0)
+
+	//get all record
+	cur, err := collection.Find(ctx, condition)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	//map data to target variable
+	for cur.Next(ctx) {
+		var expense models.Expense
+		cur.Decode(&expense)
+		expenses = append(expenses, &expense)
+	}
+	//response data to client
+	if expenses == nil {
+		return make([]*models.Expense, 0), nil
+	}
+	return expenses, nil
+}
+
+/*FindOne: get one record from a collection  */
+func (u *ExpenseRepository) FindOne(filter bson.M) (*models.Expense, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionExpenseName)
+	defer cancel()
+
+	expense := models.Expense{}
+	//Decode record into result
+	if err := collection.FindOne(ctx, filter).Decode(&expense); err != nil {
+		if err == mongo.ErrNoDocuments {
+			//return nil data when id is not existed.
+			return nil, helpers.NewErrNotFound("expense id is not found")
+		}
+		//return err if there is a system error
+		return nil, err
+	}
+
+	return &expense, nil
+}
+
+/*Create: create a new record to a collection*/
+func (u *ExpenseRepository) Create(expense *models.Expense) (*models.Expense, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionExpenseName)
+	defer cancel()
+
+	if _, err := collection.InsertOne(ctx, expense); err != nil {
+		return nil, err
+	}
+	return expense, nil
+}
+
+/*UpdateOne: update one record from a collection*/
+func (u ExpenseRepository) UpdateOne(filter bson.M, update bson.M) (*models.Expense, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionExpenseName)
+	defer cancel()
+
+	//update expense information
+	newUpdate := bson.M{"$set": update}
+	updateResult, err := collection.UpdateOne(ctx, filter, newUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return nil, helpers.NewErrNotFound("expense id is not found")
+	}
+
+	//query the new update
+	expense, errQuery := u.FindOne(filter)
+	if errQuery != nil {
+		return nil, errQuery
+	}
+
+	return expense, nil
+}
+
+/*Review: record the review of an expense if it is still pending, a conflict when another review came first*/
+func (u ExpenseRepository) Review(id primitive.ObjectID, update bson.M) (*models.Expense, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionExpenseName)
+	defer cancel()
+
+	expense := models.Expense{}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if err := collection.FindOneAndUpdate(ctx, bson.M{"_id": id, "status": models.ExpensePending}, bson.M{"$set": update}, opts).Decode(&expense); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, helpers.NewErrConflict("the expense was reviewed by someone else")
+		}
+		return nil, err
+	}
+	return &expense, nil
+}
+
+//DeleteOne func is to update one record from a collection
+func (u ExpenseRepository) DeleteOne(filter bson.M) (*models.Expense, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionExpenseName)
+	defer cancel()
+
+	expense, errFind := u.FindOne(filter)
+	if errFind != nil {
+		return nil, errFind
+	}
+
+	//delete expense from database
+	deleteResult, err := collection.DeleteOne(ctx, filter)
+	if err != nil {
+		//response to client if there is an error.
+		return nil, err
+	}
+
+	if deleteResult.DeletedCount == 0 {
+		return nil, helpers.NewErrNotFound("expense id is not found")
+	}
+
+	return expense, nil
+}
+
+/*Sums: the expenses matching a condition summed per budget item and status*/
+func (u *ExpenseRepository) Sums(condition bson.M) ([]*models.ExpenseSum, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionExpenseName)
+	defer cancel()
+
+	pipeline := bson.A{
+		bson.M{"$match": condition},
+		bson.M{"$group": bson.M{
+			"_id":    bson.M{"budgetItem": "$budgetItem", "status": "$status"},
+			"amount": bson.M{"$sum": "$amount"},
+		}},
+		bson.M{"$project": bson.M{"_id": 0, "budgetItem": "$_id.budgetItem", "status": "$_id.status", "amount": 1}},
+	}
+	cur, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	sums := make([]*models.ExpenseSum, 0)
+	if err := cur.All(ctx, &sums); err != nil {
+		return nil, err
+	}
+	return sums, nil
+}
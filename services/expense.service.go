@@ -0,0 +1,179 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ExpenseServiceName = "ExpenseServiceName"
+
+//DefaultExpenseApprovalThresholds: the highest amount per currency, in minor units, recorded without a review
+var DefaultExpenseApprovalThresholds = map[string]int64{
+	"VND": 5000000,
+	"USD": 20000,
+}
+
+type ExpenseService struct {
+	ExpenseRepository    *ExpenseRepository
+	BudgetItemRepository *BudgetItemRepository
+	ReferenceService     *ReferenceService
+	//expenses in a currency without a threshold are always reviewed
+	Thresholds map[string]int64
+}
+
+/* ParseExpenseApprovalThresholds: read a comma separated list like "VND=5000000,USD=20000", the defaults are used when it is empty */
+func ParseExpenseApprovalThresholds(value string, defaults map[string]int64) (map[string]int64, error) {
+	if strings.TrimSpace(value) == "" {
+		return defaults, nil
+	}
+	thresholds := make(map[string]int64)
+	for _, part := range strings.Split(value, ",") {
+		pair := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(pair) != 2 || !currencyPattern.MatchString(pair[0]) {
+			return nil, fmt.Errorf("expense approval threshold %s must look like VND=5000000", part)
+		}
+		threshold, err := strconv.ParseInt(pair[1], 10, 64)
+		if err != nil || threshold < 0 {
+			return nil, fmt.Errorf("expense approval threshold %s must be a whole amount in minor units", part)
+		}
+		thresholds[pair[0]] = threshold
+	}
+	return thresholds, nil
+}
+
+/* GetAll: get all data based on condition*/
+func (u *ExpenseService) GetAll(condition bson.M) ([]*models.Expense, error) {
+	return u.ExpenseRepository.FindAll(condition)
+}
+
+/*GetOne: get one record from a collection  */
+func (u *ExpenseService) GetOne(filter bson.M) (*models.Expense, error) {
+	return u.ExpenseRepository.FindOne(filter)
+}
+
+/*Create: record an expense against a budget item, it waits for a review when over the threshold*/
+func (u *ExpenseService) Create(newExpense model.NewExpense, submittedBy primitive.ObjectID) (*models.Expense, error) {
+	budgetItemId, err := primitive.ObjectIDFromHex(newExpense.BudgetItemID)
+	if err != nil {
+		return nil, helpers.NewErrValidation("invalid budget item id")
+	}
+	budgetItem, err := u.BudgetItemRepository.FindOne(bson.M{"_id": budgetItemId})
+	if err != nil {
+		return nil, err
+	}
+	currentTime := time.Now()
+	expense := &models.Expense{
+		ID:          primitive.NewObjectID(),
+		CreatedAt:   currentTime,
+		UpdatedAt:   currentTime,
+		Event:       budgetItem.Event,
+		BudgetItem:  budgetItem.ID,
+		Description: newExpense.Description,
+		Amount:      newExpense.Amount,
+		//an expense is spent in the currency it is planned in
+		Currency:    budgetItem.Currency,
+		SpentAt:     newExpense.SpentAt,
+		ReceiptUrl:  newExpense.ReceiptURL,
+		Status:      u.initialStatus(budgetItem.Currency, newExpense.Amount),
+		SubmittedBy: submittedBy,
+	}
+	return u.ExpenseRepository.Create(expense)
+}
+
+/*UpdateOne: change an expense that has not been reviewed, the need for a review is checked again*/
+func (u *ExpenseService) UpdateOne(id primitive.ObjectID, update model.UpdateExpense) (*models.Expense, error) {
+	expense, err := u.ExpenseRepository.FindOne(bson.M{"_id": id})
+	if err != nil {
+		return nil, err
+	}
+	if expense.Review != nil {
+		return nil, helpers.NewErrConflict("a reviewed expense cannot be changed")
+	}
+	return u.ExpenseRepository.UpdateOne(bson.M{"_id": id}, bson.M{
+		"updatedAt":   time.Now(),
+		"description": update.Description,
+		"amount":      update.Amount,
+		"spentAt":     update.SpentAt,
+		"receiptUrl":  update.ReceiptURL,
+		"status":      u.initialStatus(expense.Currency, update.Amount),
+	})
+}
+
+/*Review: approve or reject a pending expense, never by the user who submitted it*/
+func (u *ExpenseService) Review(id primitive.ObjectID, reviewer primitive.ObjectID, approve bool, note string) (*models.Expense, error) {
+	expense, err := u.ExpenseRepository.FindOne(bson.M{"_id": id})
+	if err != nil {
+		return nil, err
+	}
+	if expense.Status != models.ExpensePending {
+		return nil, helpers.NewErrConflict("only a pending expense can be reviewed")
+	}
+	if expense.SubmittedBy == reviewer {
+		return nil, helpers.NewErrValidation("an expense cannot be reviewed by the user who submitted it")
+	}
+	status := models.ExpenseRejected
+	if approve {
+		status = models.ExpenseApproved
+	}
+	currentTime := time.Now()
+	//only a pending expense is updated so two reviewers cannot both decide
+	return u.ExpenseRepository.Review(id, bson.M{
+		"updatedAt": currentTime,
+		"status":    status,
+		"review":    &models.ExpenseReview{At: currentTime, Reviewer: reviewer, Note: note},
+	})
+}
+
+//DeleteOne func is to delete one record from a collection once its references are resolved, approved expenses are kept
+func (u *ExpenseService) DeleteOne(filter bson.M) (*models.Expense, error) {
+	expense, err := u.ExpenseRepository.FindOne(filter)
+	if err != nil {
+		return nil, err
+	}
+	if expense.Status == models.ExpenseApproved {
+		return nil, helpers.NewErrConflict("an approved expense counts as spent and cannot be deleted")
+	}
+	//an expense approved in the meantime is not deleted either
+	if _, err := u.ReferenceService.Delete(models.CollectionExpenseName, expense.ID, bson.M{"status": bson.M{"$ne": models.ExpenseApproved}}); err != nil {
+		return nil, err
+	}
+	return expense, nil
+}
+
+/* initialStatus: an expense up to the threshold of its currency is approved right away */
+func (u *ExpenseService) initialStatus(currency string, amount int64) string {
+	if threshold, ok := u.Thresholds[currency]; ok && amount <= threshold {
+		return models.ExpenseApproved
+	}
+	return models.ExpensePending
+}
+
+//validation
+func (u *ExpenseService) ValidateNewExpense(newExpense model.NewExpense) error {
+	return validation.ValidateStruct(&newExpense,
+		validation.Field(&newExpense.BudgetItemID, validation.Required.Error("budget item id must not be blanked")),
+		validation.Field(&newExpense.Description, validation.Required.Error("description must not be blanked")),
+		validation.Field(&newExpense.Amount, validation.Required.Error("amount must not be blanked"), validation.Min(1).Error("amount must be positive")),
+		validation.Field(&newExpense.SpentAt, validation.Required.Error("spent at must not be blanked")),
+		validation.Field(&newExpense.ReceiptURL, validation.Required.Error("receipt url must not be blanked"), is.URL.Error("receipt url must be a link to the uploaded receipt")),
+	)
+}
+
+func (u *ExpenseService) ValidateUpdateExpense(updateExpense model.UpdateExpense) error {
+	return validation.ValidateStruct(&updateExpense,
+		validation.Field(&updateExpense.Description, validation.Required.Error("description must not be blanked")),
+		validation.Field(&updateExpense.Amount, validation.Required.Error("amount must not be blanked"), validation.Min(1).Error("amount must be positive")),
+		validation.Field(&updateExpense.SpentAt, validation.Required.Error("spent at must not be blanked")),
+		validation.Field(&updateExpense.ReceiptURL, validation.Required.Error("receipt url must not be blanked"), is.URL.Error("receipt url must be a link to the uploaded receipt")),
+	)
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/khanhvtn/netevent-go/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ExportRepositoryName = "ExportRepositoryName"
+
+/* ExportRepository: reads whole collections record by record for the exports */
+type ExportRepository struct {
+	MongoCN *database.MongoInstance
+}
+
+//ExportTimeout: the longest an export may take
+var ExportTimeout = 10 * time.Minute
+
+/* createContextAndTargetCol: create and return targeted collection based on collection name */
+func (u *ExportRepository) createContextAndTargetCol(colName string) (col *mongo.Collection,
+	ctx context.Context,
+	cancel context.CancelFunc) {
+	col = u.MongoCN.Db.Collection(colName)
+	ctx, cancel = context.WithTimeout(context.Background(), ExportTimeout)
+	return
+}
+
+/*Each: call fn with the cursor on every record matching the condition, in creation order, without loading them all*/
+func (u *ExportRepository) Each(colName string, condition bson.M, fn func(cur *mongo.Cursor) error) error {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(colName)
+	defer cancel()
+
+	cur, err := collection.Find(ctx, condition, options.Find().SetSort(bson.M{"createdAt": 1}).SetBatchSize(500))
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		if err := fn(cur); err != nil {
+			return err
+		}
+	}
+	return cur.Err()
+}
@@ -0,0 +1,351 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"github.com/khanhvtn/netevent-go/utilities"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var ExportServiceName = "ExportServiceName"
+
+/* Datasets that can be exported */
+var (
+	ExportParticipants      = "participants"
+	ExportEvents            = "events"
+	ExportTasks             = "tasks"
+	ExportFacilityHistories = "facilityHistories"
+)
+
+/* Formats an export can be written in */
+var (
+	ExportFormatCsv  = "csv"
+	ExportFormatXlsx = "xlsx"
+)
+
+//ExportAnswerPrefix: the columns holding the answers to the customize fields of an event
+var ExportAnswerPrefix = "answer:"
+
+type ExportService struct {
+	ExportRepository    *ExportRepository
+	EventRepository     *EventRepository
+	UserRepository      *UserRepository
+	EventTypeRepository *EventTypeRepository
+	FacilityRepository  *FacilityRepository
+}
+
+/* ExportColumn: a column of an export and how its value is read from a record */
+type ExportColumn struct {
+	Name  string
+	value func(record interface{}) string
+}
+
+/* Export: a prepared export, checked before anything is written */
+type Export struct {
+	Dataset    string
+	Columns    []*ExportColumn
+	collection string
+	condition  bson.M
+	decode     func(cur *mongo.Cursor) (interface{}, error)
+	repository *ExportRepository
+}
+
+/*
+Prepare: check the dataset and the selected columns, every column is exported when none is selected.
+Participants are exported per event, the other datasets can be narrowed to an event
+*/
+func (u *ExportService) Prepare(dataset string, eventId *primitive.ObjectID, columns []string) (*Export, error) {
+	var event *models.Event
+	condition := bson.M{}
+	if eventId != nil {
+		found, err := u.EventRepository.FindOne(bson.M{"_id": *eventId})
+		if err != nil {
+			return nil, err
+		}
+		event = found
+		condition["event"] = event.ID
+	}
+	export := &Export{Dataset: dataset, condition: condition, repository: u.ExportRepository}
+	var available []*ExportColumn
+	switch dataset {
+	case ExportParticipants:
+		if event == nil {
+			return nil, helpers.NewErrValidation("participants are exported per event, eventId must not be blanked")
+		}
+		export.collection = models.CollectionParticipantName
+		export.decode = func(cur *mongo.Cursor) (interface{}, error) {
+			participant := models.Participant{}
+			return &participant, cur.Decode(&participant)
+		}
+		available = u.participantColumns(event)
+	case ExportEvents:
+		if event != nil {
+			export.condition = bson.M{"_id": event.ID}
+		}
+		export.collection = models.CollectionEventName
+		export.decode = func(cur *mongo.Cursor) (interface{}, error) {
+			event := models.Event{}
+			return &event, cur.Decode(&event)
+		}
+		available = u.eventColumns()
+	case ExportTasks:
+		export.collection = models.CollectionTaskName
+		export.decode = func(cur *mongo.Cursor) (interface{}, error) {
+			task := models.Task{}
+			return &task, cur.Decode(&task)
+		}
+		available = u.taskColumns()
+	case ExportFacilityHistories:
+		export.collection = models.CollectionFacilityHistoryName
+		export.decode = func(cur *mongo.Cursor) (interface{}, error) {
+			facilityHistory := models.FacilityHistory{}
+			return &facilityHistory, cur.Decode(&facilityHistory)
+		}
+		available = u.facilityHistoryColumns()
+	default:
+		return nil, helpers.NewErrNotFound(fmt.Sprintf("unknown dataset %s", dataset))
+	}
+
+	if len(columns) == 0 {
+		export.Columns = available
+		return export, nil
+	}
+	byName := make(map[string]*ExportColumn)
+	for _, column := range available {
+		byName[column.Name] = column
+	}
+	for _, name := range columns {
+		column, ok := byName[name]
+		if !ok {
+			return nil, helpers.NewErrValidation(fmt.Sprintf("unknown column %s for %s", name, dataset))
+		}
+		export.Columns = append(export.Columns, column)
+	}
+	return export, nil
+}
+
+/*Write: write the header and a row per record, the records are read while they are written*/
+func (e *Export) Write(writer utilities.RowWriter) error {
+	header := make([]string, 0)
+	for _, column := range e.Columns {
+		header = append(header, column.Name)
+	}
+	if err := writer.WriteRow(header); err != nil {
+		return err
+	}
+	err := e.repository.Each(e.collection, e.condition, func(cur *mongo.Cursor) error {
+		record, err := e.decode(cur)
+		if err != nil {
+			return err
+		}
+		row := make([]string, 0)
+		for _, column := range e.Columns {
+			row = append(row, column.value(record))
+		}
+		return writer.WriteRow(row)
+	})
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+/* participantColumns: the fields of a participant, then one column per customize field ever defined on the event */
+func (u *ExportService) participantColumns(event *models.Event) []*ExportColumn {
+	participant := func(record interface{}) *models.Participant { return record.(*models.Participant) }
+	columns := []*ExportColumn{
+		{"id", func(r interface{}) string { return participant(r).ID.Hex() }},
+		{"event", func(r interface{}) string { return event.Name }},
+		{"email", func(r interface{}) string { return participant(r).Email }},
+		{"name", func(r interface{}) string { return participant(r).Name }},
+		{"academic", func(r interface{}) string { return participant(r).Academic }},
+		{"school", func(r interface{}) string { return participant(r).School }},
+		{"major", func(r interface{}) string { return participant(r).Major }},
+		{"phone", func(r interface{}) string { return participant(r).Phone }},
+		{"dob", func(r interface{}) string { return formatDate(participant(r).DOB) }},
+		{"expectedGraduateDate", func(r interface{}) string { return formatDate(participant(r).ExpectedGraduateDate) }},
+		{"status", func(r interface{}) string { return participant(r).RegistrationStatus() }},
+		{"isValid", func(r interface{}) string { return strconv.FormatBool(participant(r).IsValid) }},
+		{"isAttended", func(r interface{}) string { return strconv.FormatBool(participant(r).IsAttended) }},
+		{"checkedInAt", func(r interface{}) string {
+			if checkIn := participant(r).CheckIn; checkIn != nil {
+				return formatDate(checkIn.At)
+			}
+			return ""
+		}},
+		{"registeredAt", func(r interface{}) string { return formatDate(participant(r).CreatedAt) }},
+	}
+	//fields removed from the form still hold the answers given before
+	seen := make(map[string]bool)
+	fields := append(make([]*models.CustomizeField, 0), event.CustomizeFields...)
+	for i := len(event.CustomizeFieldVersions) - 1; i >= 0; i-- {
+		fields = append(fields, event.CustomizeFieldVersions[i].Fields...)
+	}
+	for _, field := range fields {
+		if seen[field.Name] {
+			continue
+		}
+		seen[field.Name] = true
+		name := field.Name
+		columns = append(columns, &ExportColumn{ExportAnswerPrefix + name, func(r interface{}) string {
+			for _, answer := range participant(r).Answers {
+				if answer.Name == name {
+					return strings.Join(answer.Values, ", ")
+				}
+			}
+			return ""
+		}})
+	}
+	return columns
+}
+
+/* eventColumns: the fields of an event, references are exported by name */
+func (u *ExportService) eventColumns() []*ExportColumn {
+	event := func(record interface{}) *models.Event { return record.(*models.Event) }
+	userEmail := u.userEmails()
+	eventTypeName := lookupNames(func(id primitive.ObjectID) (string, error) {
+		eventType, err := u.EventTypeRepository.FindOne(bson.M{"_id": id})
+		if err != nil {
+			return "", err
+		}
+		return eventType.Name, nil
+	})
+	return []*ExportColumn{
+		{"id", func(r interface{}) string { return event(r).ID.Hex() }},
+		{"name", func(r interface{}) string { return event(r).Name }},
+		{"eventType", func(r interface{}) string { return eventTypeName(event(r).EventType) }},
+		{"owner", func(r interface{}) string { return userEmail(event(r).Owner) }},
+		{"reviewer", func(r interface{}) string {
+			if reviewer := event(r).Reviewer; reviewer != nil {
+				return userEmail(*reviewer)
+			}
+			return ""
+		}},
+		{"tags", func(r interface{}) string { return strings.Join(event(r).Tags, ", ") }},
+		{"language", func(r interface{}) string { return event(r).Language }},
+		{"mode", func(r interface{}) string { return event(r).Mode }},
+		{"location", func(r interface{}) string { return event(r).Location }},
+		{"accommodation", func(r interface{}) string { return event(r).Accommodation }},
+		{"registrationCloseDate", func(r interface{}) string { return formatDate(event(r).RegistrationCloseDate) }},
+		{"startDate", func(r interface{}) string { return formatDate(event(r).StartDate) }},
+		{"endDate", func(r interface{}) string { return formatDate(event(r).EndDate) }},
+		{"maxParticipants", func(r interface{}) string { return strconv.Itoa(event(r).MaxParticipants) }},
+		{"registeredCount", func(r interface{}) string { return strconv.Itoa(event(r).RegisteredCount) }},
+		{"budget", func(r interface{}) string { return strconv.FormatFloat(event(r).Budget, 'f', -1, 64) }},
+		{"description", func(r interface{}) string { return event(r).Description }},
+		{"isApproved", func(r interface{}) string { return strconv.FormatBool(event(r).IsApproved) }},
+		{"isFinished", func(r interface{}) string { return strconv.FormatBool(event(r).IsFinished) }},
+		{"isDeleted", func(r interface{}) string { return strconv.FormatBool(event(r).IsDeleted) }},
+		{"createdAt", func(r interface{}) string { return formatDate(event(r).CreatedAt) }},
+	}
+}
+
+/* taskColumns: the fields of a task */
+func (u *ExportService) taskColumns() []*ExportColumn {
+	task := func(record interface{}) *models.Task { return record.(*models.Task) }
+	userEmail := u.userEmails()
+	eventName := u.eventNames()
+	return []*ExportColumn{
+		{"id", func(r interface{}) string { return task(r).ID.Hex() }},
+		{"event", func(r interface{}) string { return eventName(task(r).Event) }},
+		{"name", func(r interface{}) string { return task(r).Name }},
+		{"type", func(r interface{}) string { return task(r).Type }},
+		{"user", func(r interface{}) string { return userEmail(task(r).User) }},
+		{"startDate", func(r interface{}) string { return formatDate(task(r).StartDate) }},
+		{"endDate", func(r interface{}) string { return formatDate(task(r).EndDate) }},
+	}
+}
+
+/* facilityHistoryColumns: the fields of a booking and what happened to the facility */
+func (u *ExportService) facilityHistoryColumns() []*ExportColumn {
+	facilityHistory := func(record interface{}) *models.FacilityHistory { return record.(*models.FacilityHistory) }
+	eventName := u.eventNames()
+	facilities := make(map[primitive.ObjectID]*models.Facility)
+	facility := func(id primitive.ObjectID) *models.Facility {
+		if _, ok := facilities[id]; !ok {
+			found, err := u.FacilityRepository.FindOne(bson.M{"_id": id})
+			if err != nil {
+				found = &models.Facility{}
+			}
+			facilities[id] = found
+		}
+		return facilities[id]
+	}
+	movement := func(m *models.FacilityMovement, read func(*models.FacilityMovement) string) string {
+		if m == nil {
+			return ""
+		}
+		return read(m)
+	}
+	return []*ExportColumn{
+		{"id", func(r interface{}) string { return facilityHistory(r).ID.Hex() }},
+		{"event", func(r interface{}) string { return eventName(facilityHistory(r).Event) }},
+		{"facility", func(r interface{}) string { return facility(facilityHistory(r).Facility).Name }},
+		{"facilityCode", func(r interface{}) string { return facility(facilityHistory(r).Facility).Code }},
+		{"quantity", func(r interface{}) string { return strconv.Itoa(facilityHistory(r).Units()) }},
+		{"borrowDate", func(r interface{}) string { return formatDate(facilityHistory(r).BorrowDate) }},
+		{"returnDate", func(r interface{}) string { return formatDate(facilityHistory(r).ReturnDate) }},
+		{"checkedOutAt", func(r interface{}) string {
+			return movement(facilityHistory(r).CheckedOut, func(m *models.FacilityMovement) string { return formatDate(m.At) })
+		}},
+		{"returnedAt", func(r interface{}) string {
+			return movement(facilityHistory(r).Returned, func(m *models.FacilityMovement) string { return formatDate(m.At) })
+		}},
+		{"damageStatus", func(r interface{}) string {
+			return movement(facilityHistory(r).Returned, func(m *models.FacilityMovement) string { return m.DamageStatus })
+		}},
+	}
+}
+
+/* userEmails: the email of a user, looked up once per export */
+func (u *ExportService) userEmails() func(id primitive.ObjectID) string {
+	return lookupNames(func(id primitive.ObjectID) (string, error) {
+		user, err := u.UserRepository.FindOne(bson.M{"_id": id})
+		if err != nil {
+			return "", err
+		}
+		return user.Email, nil
+	})
+}
+
+/* eventNames: the name of an event, looked up once per export */
+func (u *ExportService) eventNames() func(id primitive.ObjectID) string {
+	return lookupNames(func(id primitive.ObjectID) (string, error) {
+		event, err := u.EventRepository.FindOne(bson.M{"_id": id})
+		if err != nil {
+			return "", err
+		}
+		return event.Name, nil
+	})
+}
+
+/* lookupNames: cache the names of referenced records, a missing record is exported blank */
+func lookupNames(find func(id primitive.ObjectID) (string, error)) func(id primitive.ObjectID) string {
+	names := make(map[primitive.ObjectID]string)
+	return func(id primitive.ObjectID) string {
+		if id.IsZero() {
+			return ""
+		}
+		if name, ok := names[id]; ok {
+			return name
+		}
+		name, _ := find(id)
+		names[id] = name
+		return name
+	}
+}
+
+/* formatDate: dates are exported in RFC 3339, a zero date is blank */
+func formatDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format(time.RFC3339)
+}
@@ -5,7 +5,6 @@ import (
 	"time"
 
 	"github.com/khanhvtn/netevent-go/database"
-	"github.com/khanhvtn/netevent-go/graph/model"
 	"github.com/khanhvtn/netevent-go/helpers"
 	"github.com/khanhvtn/netevent-go/models"
 	"github.com/khanhvtn/netevent-go/utilities"
@@ -79,7 +78,7 @@ func (u *FacilityRepository) FindOne(filter bson.M) (*models.Facility, error) {
 }
 
 /*Create: create a new record to a collection*/
-func (u *FacilityRepository) Create(newFacility model.NewFacility) (*models.Facility, error) {
+func (u *FacilityRepository) Create(newFacility models.Facility) (*models.Facility, error) {
 
 	//get a collection , context, cancel func
 	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionFacilityName)
@@ -91,6 +90,7 @@ func (u *FacilityRepository) Create(newFacility model.NewFacility) (*models.Faci
 		Name:      newFacility.Name,
 		Code:      newFacility.Code,
 		Type:      newFacility.Type,
+		Quantity:  newFacility.Stock(),
 		CreatedAt: currentTime,
 		UpdatedAt: currentTime,
 		IsDeleted: false,
@@ -114,6 +114,7 @@ func (u *FacilityRepository) Create(newFacility model.NewFacility) (*models.Faci
 		Name:      newFacility.Name,
 		Code:      newFacility.Code,
 		Type:      newFacility.Type,
+		Quantity:  newFacility.Stock(),
 		IsDeleted: false,
 		Status:    false,
 	}, nil
@@ -169,3 +170,35 @@ func (u FacilityRepository) DeleteOne(filter bson.M) (*models.Facility, error) {
 
 	return facility, nil
 }
+
+/*DistinctLegacyTypes: get the free text types stored before facility types were managed records*/
+func (u *FacilityRepository) DistinctLegacyTypes() ([]string, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionFacilityName)
+	defer cancel()
+
+	values, err := collection.Distinct(ctx, "type", bson.M{"type": bson.M{"$type": "string"}})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0)
+	for _, v := range values {
+		if name, ok := v.(string); ok {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+/*UpdateMany: update all records matching the filter*/
+func (u FacilityRepository) UpdateMany(filter bson.M, update bson.M) (int64, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionFacilityName)
+	defer cancel()
+
+	updateResult, err := collection.UpdateMany(ctx, filter, bson.M{"$set": update})
+	if err != nil {
+		return 0, err
+	}
+	return updateResult.ModifiedCount, nil
+}
@@ -2,6 +2,8 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/khanhvtn/netevent-go/graph/model"
@@ -9,12 +11,17 @@ import (
 	"github.com/khanhvtn/netevent-go/models"
 	"github.com/khanhvtn/netevent-go/utilities"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 var FacilityServiceName = "FacilityServiceName"
 
 type FacilityService struct {
-	FacilityRepository *FacilityRepository
+	FacilityRepository        *FacilityRepository
+	FacilityTypeRepository    *FacilityTypeRepository
+	FacilityHistoryRepository *FacilityHistoryRepository
+	FacilityHistoryService    *FacilityHistoryService
+	ReferenceService          *ReferenceService
 }
 
 /* GetAll: get all data based on condition*/
@@ -29,17 +36,80 @@ func (u *FacilityService) GetOne(filter bson.M) (*models.Facility, error) {
 
 /*Create: create a new record to a collection*/
 func (u *FacilityService) Create(newFacility model.NewFacility) (*models.Facility, error) {
-	return u.FacilityRepository.Create(newFacility)
+	//get facility type
+	typeId, err := primitive.ObjectIDFromHex(newFacility.TypeID)
+	if err != nil {
+		return nil, err
+	}
+	quantity := 1
+	if newFacility.Quantity != nil {
+		quantity = *newFacility.Quantity
+	}
+	return u.FacilityRepository.Create(models.Facility{
+		Name:     newFacility.Name,
+		Code:     newFacility.Code,
+		Type:     typeId,
+		Quantity: quantity,
+	})
 }
 
 /*UpdateOne: update one record from a collection*/
-func (u FacilityService) UpdateOne(filter bson.M, update bson.M) (*models.Facility, error) {
-	return u.FacilityRepository.UpdateOne(filter, update)
+func (u FacilityService) UpdateOne(filter bson.M, update model.UpdateFacility) (*models.Facility, error) {
+	//get facility type
+	typeId, err := primitive.ObjectIDFromHex(update.TypeID)
+	if err != nil {
+		return nil, err
+	}
+	bsonUpdate := bson.M{
+		"name":      update.Name,
+		"code":      update.Code,
+		"type":      typeId,
+		"isDeleted": update.IsDeleted,
+		"updatedAt": time.Now(),
+	}
+	//keep the current stock when no quantity is given
+	if update.Quantity != nil {
+		if err := u.checkStock(filter, *update.Quantity); err != nil {
+			return nil, err
+		}
+		bsonUpdate["quantity"] = *update.Quantity
+	}
+	return u.FacilityRepository.UpdateOne(filter, bsonUpdate)
 }
 
-//DeleteOne func is to update one record from a collection
+/*checkStock: a facility cannot have fewer units than its coming bookings hold at the same time*/
+func (u FacilityService) checkStock(filter bson.M, quantity int) error {
+	facility, err := u.FacilityRepository.FindOne(filter)
+	if err != nil {
+		return err
+	}
+	currentTime := time.Now()
+	bookings, err := u.FacilityHistoryRepository.FindAll(bson.M{"facility": facility.ID, "returnDate": bson.M{"$gt": currentTime}})
+	if err != nil {
+		return err
+	}
+	to := currentTime
+	for _, v := range bookings {
+		if v.ReturnDate.After(to) {
+			to = v.ReturnDate
+		}
+	}
+	if booked := u.FacilityHistoryService.BookedQuantity(bookings, currentTime, to); quantity < booked {
+		return helpers.NewErrConflict(fmt.Sprintf("quantity cannot be lower than the %d unit(s) booked at the same time", booked))
+	}
+	return nil
+}
+
+//DeleteOne func is to delete one record from a collection once its references are resolved
 func (u FacilityService) DeleteOne(filter bson.M) (*models.Facility, error) {
-	return u.FacilityRepository.DeleteOne(filter)
+	facility, err := u.FacilityRepository.FindOne(filter)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.ReferenceService.Delete(models.CollectionFacilityName, facility.ID, nil); err != nil {
+		return nil, err
+	}
+	return facility, nil
 }
 
 //validation
@@ -57,7 +127,8 @@ func (u *FacilityService) ValidateNewFacility(newFacility model.NewFacility) err
 
 		})),
 		validation.Field(&newFacility.Code, validation.Required.Error("code must not be blanked")),
-		validation.Field(&newFacility.Type, validation.Required.Error("type must not be blanked")),
+		validation.Field(&newFacility.TypeID, validation.Required.Error("type must not be blanked"), validation.By(u.validateTypeId)),
+		validation.Field(&newFacility.Quantity, validation.By(validateQuantity)),
 	)
 }
 
@@ -86,8 +157,28 @@ func (u *FacilityService) ValidateUpdateFacility(id string, updateFacility model
 
 		})),
 		validation.Field(&updateFacility.Code, validation.Required.Error("code must not be blanked")),
-		validation.Field(&updateFacility.Type, validation.Required.Error("type must not be blanked")),
+		validation.Field(&updateFacility.TypeID, validation.Required.Error("type must not be blanked"), validation.By(u.validateTypeId)),
+		validation.Field(&updateFacility.Quantity, validation.By(validateQuantity)),
 		validation.Field(&updateFacility.IsDeleted, validation.Required.Error("delete status must not be blanked")),
-		validation.Field(&updateFacility.Status, validation.Required.Error("status must not be blanked")),
 	)
 }
+
+/*validateTypeId: the facility type must be an existing one*/
+func (u *FacilityService) validateTypeId(id interface{}) error {
+	objectId, err := utilities.ConvertStringIdToObjectID(id.(string))
+	if err != nil {
+		return err
+	}
+	if _, err := u.FacilityTypeRepository.FindOne(bson.M{"_id": objectId}); err != nil {
+		return err
+	}
+	return nil
+}
+
+/*validateQuantity: an optional quantity must be at least one unit*/
+func validateQuantity(quantity interface{}) error {
+	if value, ok := quantity.(*int); ok && value != nil && *value < 1 {
+		return errors.New("quantity must be at least 1")
+	}
+	return nil
+}
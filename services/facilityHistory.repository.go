@@ -93,6 +93,9 @@ func (u *FacilityHistoryRepository) Create(newFacilityHistory *models.FacilityHi
 		BorrowDate: newFacilityHistory.BorrowDate,
 		ReturnDate: newFacilityHistory.ReturnDate,
 		Event:      newFacilityHistory.Event,
+		Quantity:   newFacilityHistory.Units(),
+		CheckedOut: newFacilityHistory.CheckedOut,
+		Returned:   newFacilityHistory.Returned,
 	}
 	newData, err := utilities.InterfaceToBsonM(facilityHistory)
 	if err != nil {
@@ -113,6 +116,9 @@ func (u *FacilityHistoryRepository) Create(newFacilityHistory *models.FacilityHi
 		BorrowDate: newFacilityHistory.BorrowDate,
 		ReturnDate: newFacilityHistory.ReturnDate,
 		Event:      newFacilityHistory.Event,
+		Quantity:   newFacilityHistory.Units(),
+		CheckedOut: newFacilityHistory.CheckedOut,
+		Returned:   newFacilityHistory.Returned,
 	}, nil
 }
 
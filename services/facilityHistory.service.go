@@ -2,12 +2,14 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/helpers"
 	"github.com/khanhvtn/netevent-go/models"
-	"github.com/khanhvtn/netevent-go/utilities"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -16,6 +18,9 @@ var FacilityHistoryServiceName = "FacilityHistoryServiceName"
 
 type FacilityHistoryService struct {
 	FacilityHistoryRepository *FacilityHistoryRepository
+	FacilityRepository        *FacilityRepository
+	EventRepository           *EventRepository
+	ReferenceService          *ReferenceService
 }
 
 /* GetAll: get all data based on condition*/
@@ -39,6 +44,14 @@ func (u *FacilityHistoryService) Create(newFacilityHistory model.NewFacilityHist
 	if err != nil {
 		return nil, err
 	}
+	quantity := 1
+	if newFacilityHistory.Quantity != nil {
+		quantity = *newFacilityHistory.Quantity
+	}
+	//reject the booking when not enough units are left in that period
+	if err := u.CheckConflicts(facilityId, newFacilityHistory.BorrowDate, newFacilityHistory.ReturnDate, quantity, nil); err != nil {
+		return nil, err
+	}
 
 	//convert to bson.M
 	currentTime := time.Now()
@@ -49,23 +62,241 @@ func (u *FacilityHistoryService) Create(newFacilityHistory model.NewFacilityHist
 		BorrowDate: newFacilityHistory.BorrowDate,
 		ReturnDate: newFacilityHistory.ReturnDate,
 		Event:      eventId,
+		Quantity:   quantity,
 	}
 	return u.FacilityHistoryRepository.Create(&facilityHistory)
 }
 
 /*UpdateOne: update one record from a collection*/
 func (u FacilityHistoryService) UpdateOne(filter bson.M, update model.UpdateFacilityHistory) (*models.FacilityHistory, error) {
-	//convert interface to bson
-	bsonUpdate, err := utilities.InterfaceToBsonM(update)
+	currentFacilityHistory, err := u.FacilityHistoryRepository.FindOne(filter)
+	if err != nil {
+		return nil, err
+	}
+	//get facility, event
+	facilityId, err := primitive.ObjectIDFromHex(update.FacilityID)
+	if err != nil {
+		return nil, err
+	}
+	eventId, err := primitive.ObjectIDFromHex(update.EventID)
+	if err != nil {
+		return nil, err
+	}
+	quantity := currentFacilityHistory.Units()
+	if update.Quantity != nil {
+		quantity = *update.Quantity
+	}
+	//the booking must still fit next to the other bookings of the facility
+	if err := u.CheckConflicts(facilityId, update.BorrowDate, update.ReturnDate, quantity, bson.M{"_id": currentFacilityHistory.ID}); err != nil {
+		return nil, err
+	}
+
+	return u.FacilityHistoryRepository.UpdateOne(bson.M{"_id": currentFacilityHistory.ID}, bson.M{
+		"facility":   facilityId,
+		"event":      eventId,
+		"borrowDate": update.BorrowDate,
+		"returnDate": update.ReturnDate,
+		"quantity":   quantity,
+		"updatedAt":  time.Now(),
+	})
+}
+
+/*CheckOut: record that the facility of a booking has been handed over*/
+func (u *FacilityHistoryService) CheckOut(filter bson.M, staffId primitive.ObjectID, input model.CheckOutFacility) (*models.FacilityHistory, error) {
+	facilityHistory, err := u.FacilityHistoryRepository.FindOne(filter)
+	if err != nil {
+		return nil, err
+	}
+	if facilityHistory.CheckedOut != nil {
+		return nil, helpers.NewErrConflict("facility has already been checked out for this booking")
+	}
+	//the units can only leave when enough of them are in the storage
+	facility, err := u.FacilityRepository.FindOne(bson.M{"_id": facilityHistory.Facility})
+	if err != nil {
+		return nil, err
+	}
+	openFacilityHistories, err := u.FacilityHistoryRepository.FindAll(bson.M{"facility": facilityHistory.Facility, "checkedOut": bson.M{"$ne": nil}, "returned": nil})
+	if err != nil {
+		return nil, err
+	}
+	checkedOutUnits := 0
+	for _, v := range openFacilityHistories {
+		checkedOutUnits += v.Units()
+	}
+	if checkedOutUnits+facilityHistory.Units() > facility.Stock() {
+		return nil, helpers.NewErrConflict(fmt.Sprintf("only %d of %d unit(s) are in the storage", facility.Stock()-checkedOutUnits, facilityHistory.Units()))
+	}
+
+	currentTime := time.Now()
+	checkedOut := models.FacilityMovement{
+		At:           currentTime,
+		Staff:        staffId,
+		Condition:    u.condition(input.Condition),
+		DamageStatus: input.DamageStatus.String(),
+	}
+	updatedFacilityHistory, err := u.FacilityHistoryRepository.UpdateOne(bson.M{"_id": facilityHistory.ID, "checkedOut": nil}, bson.M{"checkedOut": checkedOut, "updatedAt": currentTime})
 	if err != nil {
 		return nil, err
 	}
-	return u.FacilityHistoryRepository.UpdateOne(filter, bsonUpdate)
+	if err := u.refreshFacilityStatus(facilityHistory.Facility); err != nil {
+		return nil, err
+	}
+	return updatedFacilityHistory, nil
 }
 
-//DeleteOne func is to update one record from a collection
+/*Return: record that the facility of a booking came back, and in which state*/
+func (u *FacilityHistoryService) Return(filter bson.M, staffId primitive.ObjectID, input model.ReturnFacility) (*models.FacilityHistory, error) {
+	facilityHistory, err := u.FacilityHistoryRepository.FindOne(filter)
+	if err != nil {
+		return nil, err
+	}
+	if facilityHistory.CheckedOut == nil {
+		return nil, helpers.NewErrConflict("facility has not been checked out for this booking")
+	}
+	if facilityHistory.Returned != nil {
+		return nil, helpers.NewErrConflict("facility has already been returned for this booking")
+	}
+
+	currentTime := time.Now()
+	returned := models.FacilityMovement{
+		At:           currentTime,
+		Staff:        staffId,
+		Condition:    u.condition(input.Condition),
+		DamageStatus: input.DamageStatus.String(),
+	}
+	updatedFacilityHistory, err := u.FacilityHistoryRepository.UpdateOne(bson.M{"_id": facilityHistory.ID, "returned": nil}, bson.M{"returned": returned, "updatedAt": currentTime})
+	if err != nil {
+		return nil, err
+	}
+	if err := u.refreshFacilityStatus(facilityHistory.Facility); err != nil {
+		return nil, err
+	}
+	return updatedFacilityHistory, nil
+}
+
+/*GetOverdue: get the bookings whose facility is still out after the planned return date*/
+func (u *FacilityHistoryService) GetOverdue(now time.Time) ([]*models.FacilityHistory, error) {
+	return u.FacilityHistoryRepository.FindAll(bson.M{
+		"checkedOut": bson.M{"$ne": nil},
+		"returned":   nil,
+		"returnDate": bson.M{"$lt": now},
+	})
+}
+
+/*refreshFacilityStatus: a facility is marked as in use while one of its bookings is checked out*/
+func (u *FacilityHistoryService) refreshFacilityStatus(facilityId primitive.ObjectID) error {
+	openFacilityHistories, err := u.FacilityHistoryRepository.FindAll(bson.M{"facility": facilityId, "checkedOut": bson.M{"$ne": nil}, "returned": nil})
+	if err != nil {
+		return err
+	}
+	_, err = u.FacilityRepository.UpdateOne(bson.M{"_id": facilityId}, bson.M{"status": len(openFacilityHistories) > 0, "updatedAt": time.Now()})
+	return err
+}
+
+/*condition: condition notes are optional*/
+func (u *FacilityHistoryService) condition(condition *string) string {
+	if condition == nil {
+		return ""
+	}
+	return *condition
+}
+
+/*FindConflicts: get the bookings of a facility overlapping the period, except the ones matching exclude*/
+func (u *FacilityHistoryService) FindConflicts(facilityId primitive.ObjectID, from time.Time, to time.Time, exclude bson.M) ([]*models.FacilityHistory, error) {
+	//two periods overlap when each one starts before the other one ends
+	condition := bson.M{
+		"facility":   facilityId,
+		"borrowDate": bson.M{"$lt": to},
+		"returnDate": bson.M{"$gt": from},
+	}
+	if exclude != nil {
+		condition["$nor"] = []bson.M{exclude}
+	}
+	return u.FacilityHistoryRepository.FindAll(condition)
+}
+
+/*CheckConflicts: return a conflict error listing the clashing events when not enough units are left*/
+func (u *FacilityHistoryService) CheckConflicts(facilityId primitive.ObjectID, from time.Time, to time.Time, quantity int, exclude bson.M) error {
+	return u.CheckBookings(facilityId, []*models.FacilityHistory{{
+		Facility:   facilityId,
+		BorrowDate: from,
+		ReturnDate: to,
+		Quantity:   quantity,
+	}}, exclude)
+}
+
+/*CheckBookings: make sure the stock of a facility covers the new bookings on top of the existing ones*/
+func (u *FacilityHistoryService) CheckBookings(facilityId primitive.ObjectID, bookings []*models.FacilityHistory, exclude bson.M) error {
+	if len(bookings) == 0 {
+		return nil
+	}
+	facility, err := u.FacilityRepository.FindOne(bson.M{"_id": facilityId})
+	if err != nil {
+		return err
+	}
+	//the window spans every new booking
+	from, to := bookings[0].BorrowDate, bookings[0].ReturnDate
+	for _, v := range bookings {
+		if v.BorrowDate.Before(from) {
+			from = v.BorrowDate
+		}
+		if v.ReturnDate.After(to) {
+			to = v.ReturnDate
+		}
+	}
+	conflicts, err := u.FindConflicts(facilityId, from, to, exclude)
+	if err != nil {
+		return err
+	}
+	if u.BookedQuantity(append(conflicts, bookings...), from, to) <= facility.Stock() {
+		return nil
+	}
+
+	//list the events holding the facility while one of the new bookings needs it
+	clashes := make([]string, 0)
+	for _, v := range conflicts {
+		if !u.overlapsAny(v, bookings) {
+			continue
+		}
+		eventName := "unknown event"
+		if event, err := u.EventRepository.FindOne(bson.M{"_id": v.Event}); err == nil {
+			eventName = event.Name
+		} else if _, ok := err.(*helpers.ErrNotFound); !ok {
+			return err
+		}
+		clashes = append(clashes, fmt.Sprintf("%s (%s, %d unit(s) from %s to %s)", eventName, v.Event.Hex(), v.Units(), v.BorrowDate.Format(time.RFC3339), v.ReturnDate.Format(time.RFC3339)))
+	}
+	if len(clashes) == 0 {
+		return helpers.NewErrConflict(fmt.Sprintf("facility %s has only %d unit(s) in stock", facilityId.Hex(), facility.Stock()))
+	}
+	return helpers.NewErrConflict(fmt.Sprintf("facility %s has only %d unit(s) in stock and is already booked by %s", facilityId.Hex(), facility.Stock(), strings.Join(clashes, ", ")))
+}
+
+/*BookedQuantity: the highest number of units booked at the same time within the period*/
+func (u *FacilityHistoryService) BookedQuantity(bookings []*models.FacilityHistory, from time.Time, to time.Time) int {
+	return models.PeakBookedUnits(bookings, from, to)
+}
+
+/*overlapsAny: check whether a booking overlaps one of the others*/
+func (u *FacilityHistoryService) overlapsAny(booking *models.FacilityHistory, others []*models.FacilityHistory) bool {
+	for _, v := range others {
+		if booking.BorrowDate.Before(v.ReturnDate) && booking.ReturnDate.After(v.BorrowDate) {
+			return true
+		}
+	}
+	return false
+}
+
+//DeleteOne func is to delete one record from a collection once its references are resolved
 func (u FacilityHistoryService) DeleteOne(filter bson.M) (*models.FacilityHistory, error) {
-	return u.FacilityHistoryRepository.DeleteOne(filter)
+	facilityHistory, err := u.FacilityHistoryRepository.FindOne(filter)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.ReferenceService.Delete(models.CollectionFacilityHistoryName, facilityHistory.ID, nil); err != nil {
+		return nil, err
+	}
+	return facilityHistory, nil
 }
 
 //validation
@@ -78,8 +309,11 @@ func (u *FacilityHistoryService) ValidateNewFacilityHistory(newFacilityHistory m
 			return nil
 
 		})),
+		validation.Field(&newFacilityHistory.Quantity, validation.By(validateQuantity)),
 		validation.Field(&newFacilityHistory.BorrowDate, validation.Required.Error("Borrow date must not be blanked")),
-		validation.Field(&newFacilityHistory.ReturnDate, validation.Required.Error("Return date password must not be blanked")),
+		validation.Field(&newFacilityHistory.ReturnDate, validation.Required.Error("Return date password must not be blanked"), validation.By(func(returnDate interface{}) error {
+			return ValidateBookingPeriod(newFacilityHistory.BorrowDate, returnDate.(time.Time))
+		})),
 	)
 }
 
@@ -92,7 +326,19 @@ func (u *FacilityHistoryService) ValidateUpdateFacilityHistory(id string, update
 			return nil
 
 		})),
+		validation.Field(&updateFacilityHistory.Quantity, validation.By(validateQuantity)),
 		validation.Field(&updateFacilityHistory.BorrowDate, validation.Required.Error("Borrow date must not be blanked")),
-		validation.Field(&updateFacilityHistory.ReturnDate, validation.Required.Error("Return date password must not be blanked")),
+		validation.Field(&updateFacilityHistory.ReturnDate, validation.Required.Error("Return date password must not be blanked"), validation.By(func(returnDate interface{}) error {
+			return ValidateBookingPeriod(updateFacilityHistory.BorrowDate, returnDate.(time.Time))
+		})),
+		validation.Field(&updateFacilityHistory.EventID, validation.Required.Error("event id must not be blanked")),
 	)
 }
+
+/*ValidateBookingPeriod: a facility must be returned after it is borrowed*/
+func ValidateBookingPeriod(borrowDate time.Time, returnDate time.Time) error {
+	if !returnDate.After(borrowDate) {
+		return errors.New("return date must be after borrow date")
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/khanhvtn/netevent-go/database"
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"github.com/khanhvtn/netevent-go/utilities"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var FacilityTypeRepositoryName = "FacilityTypeRepositoryName"
+
+type FacilityTypeRepository struct {
+	MongoCN *database.MongoInstance
+}
+
+/* createContextAndTargetCol: create and return targeted collection based on collection name */
+func (u *FacilityTypeRepository) createContextAndTargetCol(colName string) (col *mongo.Collection,
+	ctx context.Context,
+	cancel context.CancelFunc) {
+	col = u.MongoCN.Db.Collection(colName)
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	return
+}
+
+/* GetAll: get all data based on condition*/
+func (u *FacilityTypeRepository) Find(condition bson.M) ([]*models.FacilityType, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionFacilityTypeName)
+	defer cancel()
+
+	//create an empty array to store all fields from collection
+	var facilityTypes []*models.FacilityType = make([]*models.FacilityType, 0)
+
+	//get all record
+	cur, err := collection.Find(ctx, condition)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	//map data to target variable
+	for cur.Next(ctx) {
+		var facilityType models.FacilityType
+		cur.Decode(&facilityType)
+		facilityTypes = append(facilityTypes, &facilityType)
+	}
+	//response data to client
+	if facilityTypes == nil {
+		return make([]*models.FacilityType, 0), nil
+	}
+	return facilityTypes, nil
+}
+
+/*GetOne: get one record from a collection  */
+func (u *FacilityTypeRepository) FindOne(filter bson.M) (*models.FacilityType, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionFacilityTypeName)
+	defer cancel()
+
+	facilityType := models.FacilityType{}
+	//Decode record into result
+	if err := collection.FindOne(ctx, filter).Decode(&facilityType); err != nil {
+		if err == mongo.ErrNoDocuments {
+			//return nil data when id is not existed.
+			return nil, helpers.NewErrNotFound("facility type id is not found")
+		}
+		//return err if there is a system error
+		return nil, err
+	}
+
+	return &facilityType, nil
+}
+
+/*Create: create a new record to a collection*/
+func (u *FacilityTypeRepository) Create(newFacilityType model.NewFacilityType) (*models.FacilityType, error) {
+
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionFacilityTypeName)
+	defer cancel()
+
+	//convert to bson.M
+	currentTime := time.Now()
+	facilityType := models.FacilityType{
+		Name:      newFacilityType.Name,
+		CreatedAt: currentTime,
+		UpdatedAt: currentTime,
+		IsDeleted: false,
+	}
+	newData, err := utilities.InterfaceToBsonM(facilityType)
+	if err != nil {
+		return nil, err
+	}
+
+	//create user in database
+	insertResult, err := collection.InsertOne(ctx, newData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.FacilityType{
+		ID:        insertResult.InsertedID.(primitive.ObjectID),
+		Name:      newFacilityType.Name,
+		CreatedAt: currentTime,
+		UpdatedAt: currentTime,
+		IsDeleted: false,
+	}, nil
+}
+
+/*UpdateOne: update one record from a collection*/
+func (u FacilityTypeRepository) UpdateOne(filter bson.M, update bson.M) (*models.FacilityType, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionFacilityTypeName)
+	defer cancel()
+
+	//update user information
+	newUpdate := bson.M{"$set": update}
+	updateResult, err := collection.UpdateOne(ctx, filter, newUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return nil, helpers.NewErrNotFound("facility type id is not found")
+	}
+
+	//query the new update
+	facilityType, errQuery := u.FindOne(filter)
+	if errQuery != nil {
+		return nil, errQuery
+	}
+
+	return facilityType, nil
+}
+
+//DeleteOne func is to update one record from a collection
+func (u FacilityTypeRepository) DeleteOne(filter bson.M) (*models.FacilityType, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionFacilityTypeName)
+	defer cancel()
+
+	facilityType, errGet := u.FindOne(filter)
+	if errGet != nil {
+		return nil, errGet
+	}
+
+	//delete user from database
+	deleteResult, err := collection.DeleteOne(ctx, filter)
+	if err != nil {
+		//response to client if there is an error.
+		return nil, err
+	}
+
+	if deleteResult.DeletedCount == 0 {
+		return nil, helpers.NewErrNotFound("facility type id is not found")
+	}
+
+	return facilityType, nil
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"errors"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"github.com/khanhvtn/netevent-go/utilities"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var FacilityTypeServiceName = "FacilityTypeServiceName"
+
+type FacilityTypeService struct {
+	FacilityTypeRepository *FacilityTypeRepository
+	FacilityRepository     *FacilityRepository
+	ReferenceService       *ReferenceService
+}
+
+/* GetAll: get all data based on condition*/
+func (u *FacilityTypeService) GetAll(condition bson.M) ([]*models.FacilityType, error) {
+	return u.FacilityTypeRepository.Find(condition)
+}
+
+/*GetOne: get one record from a collection  */
+func (u *FacilityTypeService) GetOne(filter bson.M) (*models.FacilityType, error) {
+	return u.FacilityTypeRepository.FindOne(filter)
+}
+
+/*Create: create a new record to a collection*/
+func (u *FacilityTypeService) Create(newFacilityType model.NewFacilityType) (*models.FacilityType, error) {
+	return u.FacilityTypeRepository.Create(newFacilityType)
+}
+
+/*UpdateOne: update one record from a collection*/
+func (u FacilityTypeService) UpdateOne(filter bson.M, update model.UpdateFacilityType) (*models.FacilityType, error) {
+	bsonUpdate, err := utilities.InterfaceToBsonM(update)
+	if err != nil {
+		return nil, err
+	}
+	return u.FacilityTypeRepository.UpdateOne(filter, bsonUpdate)
+}
+
+//DeleteOne func is to delete one record from a collection once its references are resolved
+func (u FacilityTypeService) DeleteOne(filter bson.M) (*models.FacilityType, error) {
+	facilityType, err := u.FacilityTypeRepository.FindOne(filter)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.ReferenceService.Delete(models.CollectionFacilityTypeName, facilityType.ID, nil); err != nil {
+		return nil, err
+	}
+	return facilityType, nil
+}
+
+//validation
+func (u *FacilityTypeService) ValidateNewFacilityType(newFacilityType model.NewFacilityType) error {
+	return validation.ValidateStruct(&newFacilityType,
+		validation.Field(&newFacilityType.Name, validation.Required.Error("name must not be blanked"), validation.By(func(name interface{}) error {
+			facilityType, err := u.GetOne(bson.M{"name": name.(string)})
+			if _, ok := err.(*helpers.ErrNotFound); err != nil && !ok {
+				return err
+			}
+			if facilityType != nil {
+				return errors.New("name already existed")
+			}
+			return nil
+
+		})),
+	)
+}
+
+func (u *FacilityTypeService) ValidateUpdateFacilityType(id string, updateFacilityType model.UpdateFacilityType) error {
+	return validation.ValidateStruct(&updateFacilityType,
+		validation.Field(&updateFacilityType.Name, validation.Required.Error("name must not be blanked"), validation.By(func(name interface{}) error {
+			//convert string id to object id
+			objectId, err := utilities.ConvertStringIdToObjectID(id)
+			if err != nil {
+				return err
+			}
+			//get current facilityType
+			currentFacilityType, err := u.GetOne(bson.M{"_id": objectId})
+			if _, ok := err.(*helpers.ErrNotFound); err != nil && !ok {
+				return err
+			}
+			//check email existed or not
+			if facilityType, err := u.GetOne(bson.M{"name": name.(string)}); err != nil {
+				if _, ok := err.(*helpers.ErrNotFound); ok {
+					return nil
+				} else {
+					return err
+				}
+			} else {
+				if facilityType.Name != currentFacilityType.Name {
+					return errors.New("name already existed")
+				} else {
+					return nil
+				}
+			}
+
+		})),
+	)
+}
+
+/*MigrateLegacyTypes: turn the free text types of old facilities into facility type records*/
+func (u *FacilityTypeService) MigrateLegacyTypes() error {
+	names, err := u.FacilityRepository.DistinctLegacyTypes()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		facilityType, err := u.GetOne(bson.M{"name": name})
+		if _, ok := err.(*helpers.ErrNotFound); ok {
+			facilityType, err = u.Create(model.NewFacilityType{Name: name})
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := u.FacilityRepository.UpdateMany(bson.M{"type": name}, bson.M{"type": facilityType.ID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
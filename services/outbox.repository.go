@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/khanhvtn/netevent-go/database"
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var OutboxRepositoryName = "OutboxRepositoryName"
+
+type OutboxRepository struct {
+	MongoCN *database.MongoInstance
+}
+
+/* createContextAndTargetCol: create and return targeted collection based on collection name */
+func (u *OutboxRepository) createContextAndTargetCol(colName string) (col *mongo.Collection,
+	ctx context.Context,
+	cancel context.CancelFunc) {
+	col = u.MongoCN.Db.Collection(colName)
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	return
+}
+
+/* FindAll: get all data based on condition, latest first*/
+func (u *OutboxRepository) FindAll(condition bson.M) ([]*models.OutboxMessage, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionOutboxName)
+	defer cancel()
+
+	messages := make([]*models.OutboxMessage, 0)
+	cur, err := collection.Find(ctx, condition, options.Find().SetSort(bson.M{"createdAt": -1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	//map data to target variable
+	for cur.Next(ctx) {
+		var message models.OutboxMessage
+		if err := cur.Decode(&message); err != nil {
+			return nil, err
+		}
+		messages = append(messages, &message)
+	}
+	return messages, cur.Err()
+}
+
+/*FindOne: get one record from a collection  */
+func (u *OutboxRepository) FindOne(filter bson.M) (*models.OutboxMessage, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionOutboxName)
+	defer cancel()
+
+	message := models.OutboxMessage{}
+	if err := collection.FindOne(ctx, filter).Decode(&message); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, helpers.NewErrNotFound("outbox message id is not found")
+		}
+		return nil, err
+	}
+	return &message, nil
+}
+
+/*Create: queue a new message*/
+func (u *OutboxRepository) Create(message *models.OutboxMessage) (*models.OutboxMessage, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionOutboxName)
+	defer cancel()
+
+	if _, err := collection.InsertOne(ctx, message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+/*CreateMany: queue several messages in one transaction, none is queued when one fails*/
+func (u *OutboxRepository) CreateMany(messages []*models.OutboxMessage) error {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionOutboxName)
+	defer cancel()
+
+	return u.MongoCN.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		for _, message := range messages {
+			if _, err := collection.InsertOne(sessCtx, message); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+/*UpdateOne: update one record from a collection*/
+func (u *OutboxRepository) UpdateOne(filter bson.M, update bson.M) (*models.OutboxMessage, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionOutboxName)
+	defer cancel()
+
+	updateResult, err := collection.UpdateOne(ctx, filter, bson.M{"$set": update})
+	if err != nil {
+		return nil, err
+	}
+	if updateResult.MatchedCount == 0 {
+		return nil, helpers.NewErrNotFound("outbox message id is not found")
+	}
+	return u.FindOne(filter)
+}
+
+/*ClaimNext: lock the next due message for one worker until the lease ends, every claim gets its own owner*/
+func (u *OutboxRepository) ClaimNext(now time.Time, lease time.Duration) (*models.OutboxMessage, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionOutboxName)
+	defer cancel()
+
+	//a message whose lease ended belongs to a worker that stopped while sending
+	filter := bson.M{
+		"status":        bson.M{"$in": bson.A{models.OutboxQueued, models.OutboxFailed}},
+		"nextAttemptAt": bson.M{"$lte": now},
+		"$or":           bson.A{bson.M{"lockedUntil": nil}, bson.M{"lockedUntil": bson.M{"$lt": now}}},
+	}
+	message := models.OutboxMessage{}
+	opts := options.FindOneAndUpdate().SetSort(bson.M{"nextAttemptAt": 1}).SetReturnDocument(options.After)
+	update := bson.M{"$set": bson.M{"lockedUntil": now.Add(lease).Truncate(time.Millisecond), "lockedBy": primitive.NewObjectID().Hex()}}
+	if err := collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&message); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &message, nil
+}
+
+/*ExtendLease: move the end of the lease of a claim, false when the claim was lost to another worker*/
+func (u *OutboxRepository) ExtendLease(message *models.OutboxMessage, lockedUntil time.Time) (bool, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionOutboxName)
+	defer cancel()
+
+	updateResult, err := collection.UpdateOne(ctx,
+		bson.M{"_id": message.ID, "lockedBy": message.LockedBy, "lockedUntil": message.LockedUntil},
+		bson.M{"$set": bson.M{"lockedUntil": lockedUntil}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return updateResult.MatchedCount == 1, nil
+}
+
+/*Release: write the outcome of a claim, false when the claim was lost to another worker and nothing was written*/
+func (u *OutboxRepository) Release(message *models.OutboxMessage, lockedUntil time.Time, update bson.M) (bool, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionOutboxName)
+	defer cancel()
+
+	updateResult, err := collection.UpdateOne(ctx,
+		bson.M{"_id": message.ID, "lockedBy": message.LockedBy, "lockedUntil": lockedUntil},
+		bson.M{"$set": update},
+	)
+	if err != nil {
+		return false, err
+	}
+	return updateResult.MatchedCount == 1, nil
+}
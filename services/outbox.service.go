@@ -0,0 +1,268 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"github.com/khanhvtn/netevent-go/utilities"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var OutboxServiceName = "OutboxServiceName"
+
+var (
+	//OutboxMaxAttempts: a message is dead after this many failed attempts
+	OutboxMaxAttempts = 6
+	//OutboxBaseBackoff: wait before the first retry, doubled on every further failure
+	OutboxBaseBackoff = 30 * time.Second
+	//OutboxMaxBackoff: the longest wait between two attempts
+	OutboxMaxBackoff = time.Hour
+	//OutboxLease: how long a worker owns a message while sending it
+	OutboxLease = time.Minute
+	//OutboxPollInterval: how often an idle worker looks for due messages
+	OutboxPollInterval = 2 * time.Second
+)
+
+/* OutboxService: sends queued mails in the background and retries the failed ones */
+type OutboxService struct {
+	OutboxRepository      *OutboxRepository
+	ParticipantRepository *ParticipantRepository
+	EventRepository       *EventRepository
+	TaskRepository        *TaskRepository
+	UserRepository        *UserRepository
+	Mailer                models.Mailer
+	EmailTemplateService  *EmailTemplateService
+	CalendarService       *CalendarService
+}
+
+/* GetAll: get all data based on condition*/
+func (u *OutboxService) GetAll(condition bson.M) ([]*models.OutboxMessage, error) {
+	return u.OutboxRepository.FindAll(condition)
+}
+
+/*EnqueueAll: queue the same kind of mail for several participants in one transaction*/
+func (u *OutboxService) EnqueueAll(kind string, participants []*models.Participant) error {
+	messages := make([]*models.OutboxMessage, 0)
+	for _, participant := range participants {
+		messages = append(messages, models.NewOutboxMessage(kind, participant))
+	}
+	return u.OutboxRepository.CreateMany(messages)
+}
+
+/*Retry: queue a failed or dead message again with a fresh set of attempts*/
+func (u *OutboxService) Retry(id primitive.ObjectID) (*models.OutboxMessage, error) {
+	message, err := u.OutboxRepository.FindOne(bson.M{"_id": id})
+	if err != nil {
+		return nil, err
+	}
+	if message.Status != models.OutboxFailed && message.Status != models.OutboxDead {
+		return nil, helpers.NewErrConflict(fmt.Sprintf("only failed or dead messages can be retried, this one is %s", message.Status))
+	}
+	currentTime := time.Now()
+	return u.OutboxRepository.UpdateOne(bson.M{"_id": message.ID}, bson.M{
+		"status":        models.OutboxQueued,
+		"attempts":      0,
+		"nextAttemptAt": currentTime,
+		"lockedUntil":   nil,
+		"lockedBy":      "",
+		"updatedAt":     currentTime,
+	})
+}
+
+/*Start: run a pool of workers sending the due messages*/
+func (u *OutboxService) Start(workers int) {
+	for i := 0; i < workers; i++ {
+		go u.work()
+	}
+}
+
+/* work: claim and send messages until none is due, then wait for the next poll */
+func (u *OutboxService) work() {
+	for {
+		message, err := u.OutboxRepository.ClaimNext(time.Now(), OutboxLease)
+		if err != nil {
+			log.Printf("outbox: cannot claim a message: %s", err.Error())
+		}
+		if message == nil {
+			time.Sleep(OutboxPollInterval)
+			continue
+		}
+		u.process(message)
+	}
+}
+
+/*
+process: send one claimed message and record the outcome.
+The lease is extended while the mail is sent, and the outcome is only written by the worker still holding it
+*/
+func (u *OutboxService) process(message *models.OutboxMessage) {
+	release := u.holdLease(message)
+	err := u.send(message)
+	lockedUntil := release()
+
+	currentTime := time.Now()
+	update := bson.M{
+		"attempts":    message.Attempts + 1,
+		"lockedUntil": nil,
+		"lockedBy":    "",
+		"updatedAt":   currentTime,
+	}
+	if err != nil {
+		update["lastError"] = err.Error()
+		if message.Attempts+1 >= OutboxMaxAttempts {
+			update["status"] = models.OutboxDead
+		} else {
+			update["status"] = models.OutboxFailed
+			update["nextAttemptAt"] = currentTime.Add(Backoff(message.Attempts + 1))
+		}
+	} else {
+		update["status"] = models.OutboxSent
+		update["sentAt"] = currentTime
+	}
+	held, err := u.OutboxRepository.Release(message, lockedUntil, update)
+	if err != nil {
+		log.Printf("outbox: cannot update message %s: %s", message.ID.Hex(), err.Error())
+	} else if !held {
+		log.Printf("outbox: lost the lease of message %s, its outcome is left to the worker holding it", message.ID.Hex())
+	}
+}
+
+/* holdLease: keep extending the lease of a claimed message until release is called, release returns where the lease ends */
+func (u *OutboxService) holdLease(message *models.OutboxMessage) (release func() time.Time) {
+	done := make(chan struct{})
+	result := make(chan time.Time)
+	go func() {
+		claim := *message
+		ticker := time.NewTicker(OutboxLease / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				result <- *claim.LockedUntil
+				return
+			case <-ticker.C:
+				//stored dates keep milliseconds, the next extension must match this one exactly
+				lockedUntil := time.Now().Add(OutboxLease).Truncate(time.Millisecond)
+				extended, err := u.OutboxRepository.ExtendLease(&claim, lockedUntil)
+				if err != nil {
+					log.Printf("outbox: cannot extend the lease of message %s: %s", message.ID.Hex(), err.Error())
+				} else if extended {
+					claim.LockedUntil = &lockedUntil
+				}
+			}
+		}
+	}()
+	return func() time.Time {
+		close(done)
+		return <-result
+	}
+}
+
+/* send: build the mail of a message from the current participant and event */
+func (u *OutboxService) send(message *models.OutboxMessage) error {
+	switch message.Kind {
+	case models.OutboxTaskReminder:
+		return u.sendTaskReminder(message)
+	case models.OutboxApproval:
+		return u.sendApproval(message)
+	}
+	participant, err := u.ParticipantRepository.FindOne(bson.M{"_id": message.Participant})
+	if err != nil {
+		return err
+	}
+	event, err := u.EventRepository.FindOne(bson.M{"_id": message.Event})
+	if err != nil {
+		return err
+	}
+	//every kind of message is sent with the template of the same name
+	m, err := u.EmailTemplateService.Compose(message.Kind, event.Language, models.MailTemplateData{
+		Name:       participant.Name,
+		EventName:  event.Name,
+		EventStart: u.EmailTemplateService.FormatDate(event.StartDate, event.Language),
+		Location:   event.Location,
+		Link:       u.EmailTemplateService.Link("/events/%s", event.ID.Hex()),
+	})
+	if err != nil {
+		return err
+	}
+	//invitations carry the event so it can be added to a calendar
+	if message.Kind == models.OutboxInvitation {
+		m.AttachByteFile("event.ics", u.CalendarService.ForEvent(event).ToBytes())
+	}
+	if !models.OutboxWithQrCode(message.Kind) {
+		m.To = append(m.To, participant.Email)
+		return u.Mailer.Send(m)
+	}
+	return utilities.SendQrCodeMail(u.Mailer, m, participant)
+}
+
+/* sendApproval: tell the owner of an event it was approved */
+func (u *OutboxService) sendApproval(message *models.OutboxMessage) error {
+	event, err := u.EventRepository.FindOne(bson.M{"_id": message.Event})
+	if err != nil {
+		return err
+	}
+	user, err := u.UserRepository.FindOne(bson.M{"_id": message.User})
+	if err != nil {
+		return err
+	}
+	m, err := u.EmailTemplateService.Compose(message.Kind, event.Language, models.MailTemplateData{
+		Name:       user.Email,
+		EventName:  event.Name,
+		EventStart: u.EmailTemplateService.FormatDate(event.StartDate, event.Language),
+		Location:   event.Location,
+		Link:       u.EmailTemplateService.Link("/events/%s", event.ID.Hex()),
+	})
+	if err != nil {
+		return err
+	}
+	m.To = append(m.To, user.Email)
+	return u.Mailer.Send(m)
+}
+
+/* sendTaskReminder: build the reminder of a task for its assignee */
+func (u *OutboxService) sendTaskReminder(message *models.OutboxMessage) error {
+	task, err := u.TaskRepository.FindOne(bson.M{"_id": message.Task})
+	if err != nil {
+		return err
+	}
+	user, err := u.UserRepository.FindOne(bson.M{"_id": message.User})
+	if err != nil {
+		return err
+	}
+	//a task can exist without an event
+	event := &models.Event{}
+	if !task.Event.IsZero() {
+		if event, err = u.EventRepository.FindOne(bson.M{"_id": task.Event}); err != nil {
+			return err
+		}
+	}
+	m, err := u.EmailTemplateService.Compose(message.Kind, event.Language, models.MailTemplateData{
+		Name:       user.Email,
+		EventName:  event.Name,
+		EventStart: u.EmailTemplateService.FormatDate(event.StartDate, event.Language),
+		Location:   event.Location,
+		Link:       u.EmailTemplateService.Link("/tasks/%s", task.ID.Hex()),
+		TaskName:   task.Name,
+		TaskStart:  u.EmailTemplateService.FormatDate(task.StartDate, event.Language),
+	})
+	if err != nil {
+		return err
+	}
+	m.To = append(m.To, user.Email)
+	return u.Mailer.Send(m)
+}
+
+/*Backoff: the wait after a number of failed attempts, doubling from the base up to the max*/
+func Backoff(attempts int) time.Duration {
+	backoff := time.Duration(float64(OutboxBaseBackoff) * math.Pow(2, float64(attempts-1)))
+	if backoff > OutboxMaxBackoff || backoff <= 0 {
+		return OutboxMaxBackoff
+	}
+	return backoff
+}
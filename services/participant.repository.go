@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/khanhvtn/netevent-go/database"
@@ -11,6 +12,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var ParticipantRepositoryName = "ParticipantRepositoryName"
@@ -77,8 +79,8 @@ func (u *ParticipantRepository) FindOne(filter bson.M) (*models.Participant, err
 	return &participant, nil
 }
 
-/*Create: create a new record to a collection*/
-func (u *ParticipantRepository) Create(newParticipant models.Participant) (*models.Participant, error) {
+/*Create: create a new record to a collection, the mails it triggers are queued in the same transaction*/
+func (u *ParticipantRepository) Create(newParticipant models.Participant, outbox ...*models.OutboxMessage) (*models.Participant, error) {
 
 	//get a collection , context, cancel func
 	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionParticipantName)
@@ -87,19 +89,23 @@ func (u *ParticipantRepository) Create(newParticipant models.Participant) (*mode
 	//convert to bson.M
 	currentTime := time.Now()
 	participant := models.Participant{
-		CreatedAt:            currentTime,
-		UpdatedAt:            currentTime,
-		IsValid:              false,
-		IsAttended:           false,
-		Event:                newParticipant.Event,
-		Email:                newParticipant.Email,
-		Name:                 newParticipant.Name,
-		Academic:             newParticipant.Academic,
-		School:               newParticipant.School,
-		Major:                newParticipant.Major,
-		Phone:                newParticipant.Phone,
-		DOB:                  newParticipant.DOB,
-		ExpectedGraduateDate: newParticipant.ExpectedGraduateDate,
+		ID:                     newParticipant.ID,
+		CreatedAt:              currentTime,
+		UpdatedAt:              currentTime,
+		IsValid:                false,
+		IsAttended:             false,
+		Status:                 newParticipant.Status,
+		Event:                  newParticipant.Event,
+		Email:                  newParticipant.Email,
+		Name:                   newParticipant.Name,
+		Academic:               newParticipant.Academic,
+		School:                 newParticipant.School,
+		Major:                  newParticipant.Major,
+		Phone:                  newParticipant.Phone,
+		DOB:                    newParticipant.DOB,
+		ExpectedGraduateDate:   newParticipant.ExpectedGraduateDate,
+		Answers:                newParticipant.Answers,
+		CustomizeFieldsVersion: newParticipant.CustomizeFieldsVersion,
 	}
 	newData, err := utilities.InterfaceToBsonM(participant)
 	if err != nil {
@@ -107,26 +113,45 @@ func (u *ParticipantRepository) Create(newParticipant models.Participant) (*mode
 	}
 
 	//create user in database
-	insertResult, err := collection.InsertOne(ctx, newData)
+	var insertResult *mongo.InsertOneResult
+	err = u.MongoCN.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		result, err := collection.InsertOne(sessCtx, newData)
+		if err != nil {
+			return err
+		}
+		insertResult = result
+		for _, message := range outbox {
+			if _, err := u.MongoCN.Db.Collection(models.CollectionOutboxName).InsertOne(sessCtx, message); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, helpers.NewErrConflict("email is already registered for this event")
+		}
 		return nil, err
 	}
 
 	return &models.Participant{
-		ID:                   insertResult.InsertedID.(primitive.ObjectID),
-		CreatedAt:            currentTime,
-		UpdatedAt:            currentTime,
-		IsValid:              false,
-		IsAttended:           false,
-		Event:                newParticipant.Event,
-		Email:                newParticipant.Email,
-		Name:                 newParticipant.Name,
-		Academic:             newParticipant.Academic,
-		School:               newParticipant.School,
-		Major:                newParticipant.Major,
-		Phone:                newParticipant.Phone,
-		DOB:                  newParticipant.DOB,
-		ExpectedGraduateDate: newParticipant.ExpectedGraduateDate,
+		ID:                     insertResult.InsertedID.(primitive.ObjectID),
+		CreatedAt:              currentTime,
+		UpdatedAt:              currentTime,
+		IsValid:                false,
+		IsAttended:             false,
+		Status:                 newParticipant.Status,
+		Event:                  newParticipant.Event,
+		Email:                  newParticipant.Email,
+		Name:                   newParticipant.Name,
+		Academic:               newParticipant.Academic,
+		School:                 newParticipant.School,
+		Major:                  newParticipant.Major,
+		Phone:                  newParticipant.Phone,
+		DOB:                    newParticipant.DOB,
+		ExpectedGraduateDate:   newParticipant.ExpectedGraduateDate,
+		Answers:                newParticipant.Answers,
+		CustomizeFieldsVersion: newParticipant.CustomizeFieldsVersion,
 	}, nil
 }
 
@@ -139,6 +164,9 @@ func (u ParticipantRepository) UpdateOne(filter bson.M, update bson.M) (*models.
 	newUpdate := bson.M{"$set": update}
 	updateResult, err := collection.UpdateOne(ctx, filter, newUpdate)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, helpers.NewErrConflict("email is already registered for this event")
+		}
 		return nil, err
 	}
 
@@ -179,3 +207,113 @@ func (u ParticipantRepository) DeleteOne(filter bson.M) (*models.Participant, er
 
 	return participant, nil
 }
+
+/*Count: count the records matching the condition*/
+func (u *ParticipantRepository) Count(condition bson.M) (int64, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionParticipantName)
+	defer cancel()
+
+	return collection.CountDocuments(ctx, condition)
+}
+
+/*PromoteNext: move the longest waiting participant of an event off the waitlist, their promotion mail is queued in the same transaction*/
+func (u *ParticipantRepository) PromoteNext(eventId primitive.ObjectID) (*models.Participant, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionParticipantName)
+	defer cancel()
+
+	participant := models.Participant{}
+	opts := options.FindOneAndUpdate().SetSort(bson.M{"createdAt": 1}).SetReturnDocument(options.After)
+	err := u.MongoCN.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		if err := collection.FindOneAndUpdate(sessCtx,
+			bson.M{"event": eventId, "status": models.ParticipantWaitlisted},
+			bson.M{"$set": bson.M{"status": models.ParticipantRegistered, "updatedAt": time.Now()}},
+			opts,
+		).Decode(&participant); err != nil {
+			return err
+		}
+		_, err := u.MongoCN.Db.Collection(models.CollectionOutboxName).InsertOne(sessCtx, models.NewOutboxMessage(models.OutboxPromotion, &participant))
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, helpers.NewErrNotFound("no participant is waitlisted")
+		}
+		return nil, err
+	}
+	return &participant, nil
+}
+
+/*CreateIndexes: an email can register only once per event*/
+func (u *ParticipantRepository) CreateIndexes() error {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionParticipantName)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "event", Value: 1}, {Key: "email", Value: 1}},
+		Options: options.Index().SetName("event_email_unique").SetUnique(true),
+	})
+	return err
+}
+
+/*DuplicateEmails: the registrations of every email registered more than once for an event, ignoring case and spaces*/
+func (u *ParticipantRepository) DuplicateEmails() ([][]*models.Participant, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionParticipantName)
+	defer cancel()
+
+	cur, err := collection.Aggregate(ctx, bson.A{
+		bson.M{"$group": bson.M{
+			"_id":          bson.M{"event": "$event", "email": normalizedEmail},
+			"participants": bson.M{"$push": "$$ROOT"},
+			"count":        bson.M{"$sum": 1},
+		}},
+		bson.M{"$match": bson.M{"count": bson.M{"$gt": 1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var groups []struct {
+		Participants []*models.Participant `bson:"participants"`
+	}
+	if err := cur.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+	duplicates := make([][]*models.Participant, 0)
+	for _, group := range groups {
+		duplicates = append(duplicates, group.Participants)
+	}
+	return duplicates, nil
+}
+
+/*NormalizeEmails: store every email in lower case without surrounding spaces*/
+func (u *ParticipantRepository) NormalizeEmails() error {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionParticipantName)
+	defer cancel()
+
+	_, err := collection.UpdateMany(ctx, bson.M{}, bson.A{bson.M{"$set": bson.M{"email": normalizedEmail}}})
+	return err
+}
+
+//normalizedEmail: the expression of models.NormalizeEmail in an aggregation
+var normalizedEmail = bson.M{"$toLower": bson.M{"$trim": bson.M{"input": "$email"}}}
+
+/*MarkCheckedIn: record the check in of a participant unless one is already recorded*/
+func (u *ParticipantRepository) MarkCheckedIn(id primitive.ObjectID, checkIn *models.CheckIn) (bool, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionParticipantName)
+	defer cancel()
+
+	updateResult, err := collection.UpdateOne(ctx, bson.M{"_id": id, "checkIn": nil}, bson.M{"$set": bson.M{
+		"isAttended": true,
+		"checkIn":    checkIn,
+		"updatedAt":  checkIn.At,
+	}})
+	if err != nil {
+		return false, err
+	}
+	return updateResult.ModifiedCount == 1, nil
+}
@@ -2,6 +2,8 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation"
@@ -16,8 +18,14 @@ import (
 
 var ParticipantServiceName = "ParticipantServiceName"
 
+//staff can start scanning a while before the event starts
+var CheckInOpensBefore = time.Hour
+
 type ParticipantService struct {
 	ParticipantRepository *ParticipantRepository
+	EventRepository       *EventRepository
+	OutboxService         *OutboxService
+	ReferenceService      *ReferenceService
 }
 
 /* GetAll: get all data based on condition*/
@@ -25,69 +33,301 @@ func (u *ParticipantService) GetAll(condition bson.M) ([]*models.Participant, er
 	return u.ParticipantRepository.FindAll(condition)
 }
 
+/*GetHistory: get the registrations of one email across events, latest first*/
+func (u *ParticipantService) GetHistory(email string) ([]*models.Participant, error) {
+	participants, err := u.ParticipantRepository.FindAll(bson.M{"email": models.NormalizeEmail(email)})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(participants, func(i, j int) bool {
+		return participants[i].CreatedAt.After(participants[j].CreatedAt)
+	})
+	return participants, nil
+}
+
 /*GetOne: get one record from a collection  */
 func (u *ParticipantService) GetOne(filter bson.M) (*models.Participant, error) {
 	return u.ParticipantRepository.FindOne(filter)
 }
 
-/*Create: create a new record to a collection*/
+/*Create: register a participant, or put them on the waitlist once the event is full*/
 func (u *ParticipantService) Create(newParticipant model.NewParticipant) (*models.Participant, error) {
+	return u.create(newParticipant, true)
+}
+
+/* create: register a participant, the invitation is queued only when invite is set */
+func (u *ParticipantService) create(newParticipant model.NewParticipant, invite bool) (*models.Participant, error) {
 
 	//get event
 	eventId, err := primitive.ObjectIDFromHex(newParticipant.EventID)
 	if err != nil {
 		return nil, err
 	}
+	event, err := u.EventRepository.FindOne(bson.M{"_id": eventId})
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(event.RegistrationCloseDate) {
+		return nil, helpers.NewErrValidation("registration for this event is closed")
+	}
+
+	//take a seat in the same update that checks the capacity
+	status := models.ParticipantWaitlisted
+	reserved, err := u.EventRepository.ReserveSeat(eventId)
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		status = models.ParticipantRegistered
+	}
 
 	//convert to bson.M
 	currentTime := time.Now()
 	participant := models.Participant{
-		CreatedAt:            currentTime,
-		UpdatedAt:            currentTime,
-		IsValid:              false,
-		IsAttended:           false,
-		Event:                eventId,
-		Email:                newParticipant.Email,
-		Name:                 newParticipant.Name,
-		Academic:             newParticipant.Academic,
-		School:               newParticipant.School,
-		Major:                newParticipant.Major,
-		Phone:                newParticipant.Phone,
-		DOB:                  newParticipant.Dob,
-		ExpectedGraduateDate: newParticipant.ExpectedGraduateDate,
-	}
-	return u.ParticipantRepository.Create(participant)
-}
-
-/*UpdateOne: update one record from a collection*/
+		ID:                     primitive.NewObjectID(),
+		CreatedAt:              currentTime,
+		UpdatedAt:              currentTime,
+		IsValid:                false,
+		IsAttended:             false,
+		Status:                 status,
+		Event:                  eventId,
+		Email:                  models.NormalizeEmail(newParticipant.Email),
+		Name:                   newParticipant.Name,
+		Academic:               newParticipant.Academic,
+		School:                 newParticipant.School,
+		Major:                  newParticipant.Major,
+		Phone:                  newParticipant.Phone,
+		DOB:                    newParticipant.Dob,
+		ExpectedGraduateDate:   newParticipant.ExpectedGraduateDate,
+		Answers:                mapFieldAnswers(newParticipant.Answers),
+		CustomizeFieldsVersion: event.CustomizeFieldsVersion,
+	}
+	//the invitation is queued together with the registration, waitlisted participants get it once promoted
+	outbox := make([]*models.OutboxMessage, 0)
+	if invite && !participant.IsWaitlisted() {
+		outbox = append(outbox, models.NewOutboxMessage(models.OutboxInvitation, &participant))
+	}
+	createdParticipant, err := u.ParticipantRepository.Create(participant, outbox...)
+	if err != nil {
+		//give the seat back when the participant could not be saved
+		if reserved {
+			if err := u.EventRepository.ReleaseSeat(eventId); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+	return createdParticipant, nil
+}
+
+/*
+UpdateOne: update the details and answers of a participant.
+A participant stays in their event, moving would skip the seat accounting, so they cancel and register again.
+Attendance is only recorded by a check-in and a waitlisted participant cannot be marked valid
+*/
 func (u ParticipantService) UpdateOne(filter bson.M, update model.UpdateParticipant) (*models.Participant, error) {
-	bsonUpdate, err := utilities.InterfaceToBsonM(update)
+	participant, err := u.ParticipantRepository.FindOne(filter)
 	if err != nil {
 		return nil, err
 	}
-	return u.ParticipantRepository.UpdateOne(filter, bsonUpdate)
+	if update.EventID != participant.Event.Hex() {
+		return nil, helpers.NewErrValidation("a participant cannot move to another event, cancel the registration and register again")
+	}
+	if update.IsAttended != participant.IsAttended {
+		return nil, helpers.NewErrValidation("attendance is recorded by checking the participant in")
+	}
+	if update.IsValid && participant.IsWaitlisted() {
+		return nil, helpers.NewErrValidation("a waitlisted participant cannot be marked valid")
+	}
+	bsonUpdate := bson.M{
+		"updatedAt":            time.Now(),
+		"email":                models.NormalizeEmail(update.Email),
+		"name":                 update.Name,
+		"academic":             update.Academic,
+		"school":               update.School,
+		"major":                update.Major,
+		"phone":                update.Phone,
+		"dob":                  update.Dob,
+		"expectedGraduateDate": update.ExpectedGraduateDate,
+		"isValid":              update.IsValid,
+	}
+	//keep the stored answers when none are given
+	if update.Answers != nil {
+		//new answers are given against the current fields of the event
+		event, err := u.EventRepository.FindOne(bson.M{"_id": participant.Event})
+		if err != nil {
+			return nil, err
+		}
+		bsonUpdate["answers"] = mapFieldAnswers(update.Answers)
+		bsonUpdate["customizeFieldsVersion"] = event.CustomizeFieldsVersion
+	}
+	return u.ParticipantRepository.UpdateOne(bson.M{"_id": participant.ID}, bsonUpdate)
 }
 
-//DeleteOne func is to update one record from a collection
+//DeleteOne func is to cancel a registration, the freed seat goes to the waitlist
 func (u ParticipantService) DeleteOne(filter bson.M) (*models.Participant, error) {
-	return u.ParticipantRepository.DeleteOne(filter)
+	participant, err := u.ParticipantRepository.FindOne(filter)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.ReferenceService.Delete(models.CollectionParticipantName, participant.ID, nil); err != nil {
+		return nil, err
+	}
+	if participant.IsWaitlisted() {
+		return participant, nil
+	}
+	if err := u.EventRepository.ReleaseSeat(participant.Event); err != nil {
+		return nil, err
+	}
+	if _, err := u.PromoteWaitlisted(participant.Event); err != nil {
+		return nil, err
+	}
+	return participant, nil
+}
+
+/*CheckIn: verify a scanned QR code and mark the participant as attended, a code is accepted once*/
+func (u *ParticipantService) CheckIn(qrPayload string, staffId primitive.ObjectID, device string) (*models.Participant, error) {
+	payload, err := utilities.DecodeQrPayload(qrPayload)
+	if err != nil {
+		return nil, helpers.NewErrValidation(err.Error())
+	}
+	eventId, err := primitive.ObjectIDFromHex(payload.EventID)
+	if err != nil {
+		return nil, helpers.NewErrValidation("invalid QR code")
+	}
+	participantId, err := primitive.ObjectIDFromHex(payload.ParticipantID)
+	if err != nil {
+		return nil, helpers.NewErrValidation("invalid QR code")
+	}
+	participant, err := u.ParticipantRepository.FindOne(bson.M{"_id": participantId, "event": eventId})
+	if err != nil {
+		return nil, err
+	}
+	if participant.IsWaitlisted() {
+		return nil, helpers.NewErrConflict("participant is still on the waitlist")
+	}
+	event, err := u.EventRepository.FindOne(bson.M{"_id": eventId})
+	if err != nil {
+		return nil, err
+	}
+	currentTime := time.Now()
+	if currentTime.Before(event.StartDate.Add(-CheckInOpensBefore)) || currentTime.After(event.EndDate) {
+		return nil, helpers.NewErrConflict("event is not running")
+	}
+
+	//only the first scan matches, a second one finds the check in already set
+	marked, err := u.ParticipantRepository.MarkCheckedIn(participant.ID, &models.CheckIn{
+		At:     currentTime,
+		Staff:  staffId,
+		Device: device,
+	})
+	if err != nil {
+		return nil, err
+	}
+	checkedIn, err := u.ParticipantRepository.FindOne(bson.M{"_id": participant.ID})
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		return nil, helpers.NewErrConflict(fmt.Sprintf("participant already checked in at %s", checkedIn.CheckIn.At.Format(time.RFC3339)))
+	}
+	return checkedIn, nil
+}
+
+/*SendInvitations: queue an invitation for every registered participant of an event*/
+func (u *ParticipantService) SendInvitations(eventId primitive.ObjectID) (int, error) {
+	if _, err := u.EventRepository.FindOne(bson.M{"_id": eventId}); err != nil {
+		return 0, err
+	}
+	participants, err := u.ParticipantRepository.FindAll(bson.M{"event": eventId, "status": bson.M{"$ne": models.ParticipantWaitlisted}})
+	if err != nil {
+		return 0, err
+	}
+	//either every participant gets an invitation or none does, so the bulk send can be repeated
+	if err := u.OutboxService.EnqueueAll(models.OutboxInvitation, participants); err != nil {
+		return 0, err
+	}
+	return len(participants), nil
+}
+
+/*PromoteWaitlisted: fill the free seats of an event with waitlisted participants in registration order, each of them is mailed*/
+func (u *ParticipantService) PromoteWaitlisted(eventId primitive.ObjectID) ([]*models.Participant, error) {
+	promoted := make([]*models.Participant, 0)
+	for {
+		reserved, err := u.EventRepository.ReserveSeat(eventId)
+		if err != nil {
+			return nil, err
+		}
+		if !reserved {
+			break
+		}
+		participant, err := u.ParticipantRepository.PromoteNext(eventId)
+		if err != nil {
+			//nobody is waiting, the seat stays free
+			if err := u.EventRepository.ReleaseSeat(eventId); err != nil {
+				return nil, err
+			}
+			if _, ok := err.(*helpers.ErrNotFound); ok {
+				break
+			}
+			return nil, err
+		}
+		promoted = append(promoted, participant)
+	}
+	return promoted, nil
+}
+
+/*
+MigrateEmails: store emails normalized so the unique index on event and email can be built.
+Of the registrations of one email to one event only the preferred one is kept,
+the seats they held are given back by SyncRegisteredCounts
+*/
+func (u *ParticipantService) MigrateEmails() error {
+	duplicates, err := u.ParticipantRepository.DuplicateEmails()
+	if err != nil {
+		return err
+	}
+	for _, participants := range duplicates {
+		sort.SliceStable(participants, func(i, j int) bool {
+			return models.PreferredRegistration(participants[i], participants[j])
+		})
+		for _, participant := range participants[1:] {
+			if _, err := u.ReferenceService.Delete(models.CollectionParticipantName, participant.ID, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return u.ParticipantRepository.NormalizeEmails()
+}
+
+/*SyncRegisteredCounts: recount the taken seats of every event from its registered participants*/
+func (u *ParticipantService) SyncRegisteredCounts() error {
+	events, err := u.EventRepository.FindAll(bson.M{})
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		count, err := u.ParticipantRepository.Count(bson.M{"event": event.ID, "status": bson.M{"$ne": models.ParticipantWaitlisted}})
+		if err != nil {
+			return err
+		}
+		if err := u.EventRepository.SetRegisteredCount(event.ID, int(count)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 //validation
 func (u *ParticipantService) ValidateNewParticipant(newParticipant model.NewParticipant) error {
 	return validation.ValidateStruct(&newParticipant,
 		validation.Field(&newParticipant.Email, validation.Required.Error("email must not be blanked"), is.Email.Error("invalid email"), validation.By(func(email interface{}) error {
-			participant, err := u.GetOne(bson.M{"email": email.(string)})
-			if _, ok := err.(*helpers.ErrNotFound); err != nil && !ok {
-				return err
-			}
-			if participant != nil {
-				return errors.New("email already existed")
-			}
-			return nil
-
+			return u.validateEventEmail(newParticipant.EventID, email.(string), nil)
 		})),
 		validation.Field(&newParticipant.EventID, validation.Required.Error("event id must not be blanked")),
+		validation.Field(&newParticipant.Answers, validation.By(func(interface{}) error {
+			return u.validateAnswers(newParticipant.EventID, newParticipant.Answers)
+		})),
 		validation.Field(&newParticipant.Name, validation.Required.Error("name must not be blanked")),
 		validation.Field(&newParticipant.Academic, validation.Required.Error("academic must not be blanked")),
 		validation.Field(&newParticipant.Dob, validation.Required.Error("date of birth must not be blanked")),
@@ -106,28 +346,15 @@ func (u *ParticipantService) ValidateUpdateParticipant(id string, updateParticip
 			if err != nil {
 				return err
 			}
-			//get current participant
-			currentParticipant, err := u.GetOne(bson.M{"_id": objectId})
-			if _, ok := err.(*helpers.ErrNotFound); err != nil && !ok {
-				return err
-			}
-			//check email existed or not
-			if participant, err := u.GetOne(bson.M{"email": email.(string)}); err != nil {
-				if _, ok := err.(*helpers.ErrNotFound); ok {
-					return nil
-				} else {
-					return err
-				}
-			} else {
-				if participant.Name != currentParticipant.Name {
-					return errors.New("email already existed")
-				} else {
-					return nil
-				}
-			}
-
+			return u.validateEventEmail(updateParticipant.EventID, email.(string), objectId)
 		})),
 		validation.Field(&updateParticipant.EventID, validation.Required.Error("event id must not be blanked")),
+		validation.Field(&updateParticipant.Answers, validation.By(func(interface{}) error {
+			if updateParticipant.Answers == nil {
+				return nil
+			}
+			return u.validateAnswers(updateParticipant.EventID, updateParticipant.Answers)
+		})),
 		validation.Field(&updateParticipant.Name, validation.Required.Error("name must not be blanked")),
 		validation.Field(&updateParticipant.Academic, validation.Required.Error("academic must not be blanked")),
 		validation.Field(&updateParticipant.Dob, validation.Required.Error("date of birth must not be blanked")),
@@ -137,3 +364,50 @@ func (u *ParticipantService) ValidateUpdateParticipant(id string, updateParticip
 		validation.Field(&updateParticipant.School, validation.Required.Error("school must not be blanked")),
 	)
 }
+
+/*validateEventEmail: an email can register only once per event, the participant being updated is skipped*/
+func (u *ParticipantService) validateEventEmail(eventId string, email string, exclude *primitive.ObjectID) error {
+	eventObjectId, err := primitive.ObjectIDFromHex(eventId)
+	if err != nil {
+		//the event id is checked by its own rule
+		return nil
+	}
+	filter := bson.M{"event": eventObjectId, "email": models.NormalizeEmail(email)}
+	if exclude != nil {
+		filter["_id"] = bson.M{"$ne": *exclude}
+	}
+	participant, err := u.GetOne(filter)
+	if _, ok := err.(*helpers.ErrNotFound); err != nil && !ok {
+		return err
+	}
+	if participant != nil {
+		return errors.New("email already registered for this event")
+	}
+	return nil
+}
+
+/*validateAnswers: check the answers against the customize fields of the event*/
+func (u *ParticipantService) validateAnswers(eventId string, answers []*model.InputFieldAnswer) error {
+	eventObjectId, err := primitive.ObjectIDFromHex(eventId)
+	if err != nil {
+		//the event id is checked by its own rule
+		return nil
+	}
+	event, err := u.EventRepository.FindOne(bson.M{"_id": eventObjectId})
+	if err != nil {
+		return err
+	}
+	return ValidateFieldAnswers(event.CustomizeFields, answers)
+}
+
+/*mapFieldAnswers: convert graphql answers to the stored answers*/
+func mapFieldAnswers(answers []*model.InputFieldAnswer) []*models.FieldAnswer {
+	results := make([]*models.FieldAnswer, 0)
+	for _, answer := range answers {
+		results = append(results, &models.FieldAnswer{
+			Name:   answer.Name,
+			Values: answer.Values,
+		})
+	}
+	return results
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"github.com/khanhvtn/netevent-go/utilities"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//ImportMaxRows: the most participants a single file can hold
+var ImportMaxRows = 5000
+
+//ImportMaxBytes: the largest file that is read
+var ImportMaxBytes int64 = 5 << 20
+
+//importColumns: the columns mapped to the fields of a new participant
+var importColumns = []string{"email", "name", "academic", "school", "major", "phone", "dob", "expectedGraduateDate"}
+
+/*
+Import: read participants from a csv file with a header row and validate every row like createParticipant.
+Columns named after a customize field of the event, or prefixed with answer:, hold the answers.
+In commit mode the valid rows are registered, the invitations are queued only when invite is set
+*/
+func (u *ParticipantService) Import(eventId primitive.ObjectID, file io.Reader, mode string, invite bool) (*models.ParticipantImport, error) {
+	event, err := u.EventRepository.FindOne(bson.M{"_id": eventId})
+	if err != nil {
+		return nil, err
+	}
+	//a dry run must fail the same way the commit would
+	if time.Now().After(event.RegistrationCloseDate) {
+		return nil, helpers.NewErrValidation("registration for this event is closed")
+	}
+	header, rows, err := utilities.ReadImportFile(file, ImportMaxBytes, ImportMaxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	//map every column to a field or to a customize field
+	fields := make(map[int]string)
+	answers := make(map[int]*models.CustomizeField)
+	result := &models.ParticipantImport{Mode: mode, Imported: make([]*models.Participant, 0), Errors: make([]*models.ImportRowError, 0), IgnoredColumns: make([]string, 0)}
+	for i, column := range header {
+		column = strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))
+		if name, ok := matchImportColumn(column); ok {
+			fields[i] = name
+		} else if field := matchCustomizeField(event.CustomizeFields, strings.TrimPrefix(column, ExportAnswerPrefix)); field != nil {
+			answers[i] = field
+		} else {
+			result.IgnoredColumns = append(result.IgnoredColumns, column)
+		}
+	}
+	missing := make([]string, 0)
+	for _, name := range importColumns {
+		found := false
+		for _, field := range fields {
+			found = found || field == name
+		}
+		if !found {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, helpers.NewErrValidation(fmt.Sprintf("missing columns: %s", strings.Join(missing, ", ")))
+	}
+
+	result.Total = len(rows)
+	emails := make(map[string]int)
+	for _, row := range rows {
+		newParticipant, messages := mapImportRecord(event.ID.Hex(), row.Record, fields, answers)
+		rowError := &models.ImportRowError{Row: row.Line, Email: newParticipant.Email, Messages: messages}
+		if err := u.ValidateNewParticipant(newParticipant); err != nil {
+			rowError.Messages = append(rowError.Messages, validationMessages(err)...)
+		}
+		//the registrations of the file are not stored yet during a dry run
+		key := models.NormalizeEmail(newParticipant.Email)
+		if first, ok := emails[key]; ok && key != "" {
+			rowError.Messages = append(rowError.Messages, fmt.Sprintf("email: already in row %d", first))
+		} else {
+			emails[key] = row.Line
+		}
+		if len(rowError.Messages) > 0 {
+			result.Errors = append(result.Errors, rowError)
+			continue
+		}
+		result.Valid++
+		if mode != models.ImportCommit {
+			continue
+		}
+		participant, err := u.create(newParticipant, invite)
+		if err != nil {
+			if _, ok := err.(*helpers.ErrValidation); !ok {
+				if _, ok := err.(*helpers.ErrConflict); !ok {
+					return nil, err
+				}
+			}
+			result.Valid--
+			rowError.Messages = append(rowError.Messages, err.Error())
+			result.Errors = append(result.Errors, rowError)
+			continue
+		}
+		result.Imported = append(result.Imported, participant)
+	}
+	return result, nil
+}
+
+/* matchImportColumn: the field a column holds, headers are matched ignoring case */
+func matchImportColumn(column string) (string, bool) {
+	for _, name := range importColumns {
+		if strings.EqualFold(column, name) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+/* matchCustomizeField: the customize field a column holds, matched ignoring case */
+func matchCustomizeField(fields []*models.CustomizeField, column string) *models.CustomizeField {
+	for _, field := range fields {
+		if strings.EqualFold(field.Name, column) {
+			return field
+		}
+	}
+	return nil
+}
+
+/* mapImportRecord: build the new participant of a row, with the messages of the values that cannot be read */
+func mapImportRecord(eventId string, record []string, fields map[int]string, answers map[int]*models.CustomizeField) (model.NewParticipant, []string) {
+	newParticipant := model.NewParticipant{EventID: eventId, Answers: make([]*model.InputFieldAnswer, 0)}
+	messages := make([]string, 0)
+	for i, value := range record {
+		value = strings.TrimSpace(value)
+		if field, ok := answers[i]; ok {
+			if value == "" {
+				continue
+			}
+			//only a multi select answer holds several values, other answers can contain commas
+			values := []string{value}
+			if field.FieldType() == models.FieldTypeMultiSelect {
+				values = make([]string, 0)
+				for _, v := range strings.Split(value, ",") {
+					values = append(values, strings.TrimSpace(v))
+				}
+			}
+			newParticipant.Answers = append(newParticipant.Answers, &model.InputFieldAnswer{Name: field.Name, Values: values})
+			continue
+		}
+		switch fields[i] {
+		case "email":
+			newParticipant.Email = value
+		case "name":
+			newParticipant.Name = value
+		case "academic":
+			newParticipant.Academic = value
+		case "school":
+			newParticipant.School = value
+		case "major":
+			newParticipant.Major = value
+		case "phone":
+			newParticipant.Phone = value
+		case "dob", "expectedGraduateDate":
+			if value == "" {
+				continue
+			}
+			date, err := utilities.ParseImportDate(value)
+			if err != nil {
+				messages = append(messages, fmt.Sprintf("%s: %s is not a date like 2006-01-02", fields[i], value))
+				continue
+			}
+			if fields[i] == "dob" {
+				newParticipant.Dob = date
+			} else {
+				newParticipant.ExpectedGraduateDate = date
+			}
+		}
+	}
+	return newParticipant, messages
+}
+
+/* validationMessages: one message per invalid field, in field order */
+func validationMessages(err error) []string {
+	errs, ok := err.(validation.Errors)
+	if !ok {
+		return []string{err.Error()}
+	}
+	keys := make([]string, 0)
+	for key := range errs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	messages := make([]string, 0)
+	for _, key := range keys {
+		messages = append(messages, fmt.Sprintf("%s: %s", key, errs[key].Error()))
+	}
+	return messages
+}
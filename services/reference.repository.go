@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/khanhvtn/netevent-go/database"
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ReferenceRepositoryName = "ReferenceRepositoryName"
+
+/* ReferenceRepository: collection agnostic queries used to follow references between collections */
+type ReferenceRepository struct {
+	MongoCN *database.MongoInstance
+}
+
+/* createContextAndTargetCol: create and return targeted collection based on collection name */
+func (u *ReferenceRepository) createContextAndTargetCol(colName string) (col *mongo.Collection,
+	ctx context.Context,
+	cancel context.CancelFunc) {
+	col = u.MongoCN.Db.Collection(colName)
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	return
+}
+
+/* FindIDs: get the ids of all records in a collection matching the condition */
+func (u *ReferenceRepository) FindIDs(colName string, condition bson.M) ([]primitive.ObjectID, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(colName)
+	defer cancel()
+
+	ids := make([]primitive.ObjectID, 0)
+	cur, err := collection.Find(ctx, condition, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	//map data to target variable
+	for cur.Next(ctx) {
+		var record struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cur.Decode(&record); err != nil {
+			return nil, err
+		}
+		ids = append(ids, record.ID)
+	}
+	return ids, cur.Err()
+}
+
+/* Delete: apply the impacts of a delete, deepest first, and delete the target in one transaction */
+func (u *ReferenceRepository) Delete(colName string, filter bson.M, impacts []*models.DeleteImpact) error {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(colName)
+	defer cancel()
+
+	return u.MongoCN.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		//apply the deepest impacts first so cascaded records never point at something already gone
+		for i := len(impacts) - 1; i >= 0; i-- {
+			if err := u.apply(sessCtx, impacts[i]); err != nil {
+				return err
+			}
+		}
+		deleteResult, err := collection.DeleteOne(sessCtx, filter)
+		if err != nil {
+			return err
+		}
+		//the impacts are rolled back when the target is gone or no longer matches
+		if deleteResult.DeletedCount == 0 {
+			return helpers.NewErrNotFound("id is not found")
+		}
+		return nil
+	})
+}
+
+/* apply: execute one planned impact */
+func (u *ReferenceRepository) apply(sessCtx mongo.SessionContext, impact *models.DeleteImpact) error {
+	collection := u.MongoCN.Db.Collection(impact.Collection)
+	filter := bson.M{"_id": bson.M{"$in": impact.IDs}}
+	switch impact.Action {
+	case models.ReferenceCascade:
+		_, err := collection.DeleteMany(sessCtx, filter)
+		return err
+	case models.ReferenceNullify:
+		update := bson.M{"$set": bson.M{"updatedAt": time.Now()}}
+		if impact.Many {
+			update["$pull"] = bson.M{impact.Field: bson.M{"$in": impact.References}}
+		} else {
+			update["$set"].(bson.M)[impact.Field] = nil
+		}
+		_, err := collection.UpdateMany(sessCtx, filter, update)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ReferenceServiceName = "ReferenceServiceName"
+
+/* ReferenceService: keeps references between collections consistent when records are deleted */
+type ReferenceService struct {
+	ReferenceRepository *ReferenceRepository
+}
+
+/* Plan: report what deleting a record would affect without changing anything (dry run) */
+func (u *ReferenceService) Plan(colName string, id primitive.ObjectID) (*models.DeleteReport, error) {
+	//make sure the target exists before following its references
+	targetIDs, err := u.ReferenceRepository.FindIDs(colName, bson.M{"_id": id})
+	if err != nil {
+		return nil, err
+	}
+	if len(targetIDs) == 0 {
+		return nil, helpers.NewErrNotFound("id is not found")
+	}
+	report := &models.DeleteReport{
+		Collection: colName,
+		ID:         id,
+		Blockers:   make([]*models.DeleteImpact, 0),
+		Impacts:    make([]*models.DeleteImpact, 0),
+	}
+	deleting := map[string]map[primitive.ObjectID]bool{colName: {id: true}}
+	if err := u.plan(colName, []primitive.ObjectID{id}, report, deleting); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+/* Conflict: the error of a delete blocked by restrict rules, nil when it is allowed */
+func (u *ReferenceService) Conflict(report *models.DeleteReport) error {
+	if report.Allowed() {
+		return nil
+	}
+	blockers := make([]string, 0)
+	for _, v := range report.Blockers {
+		blockers = append(blockers, fmt.Sprintf("%d %s (%s)", len(v.IDs), v.Collection, v.Field))
+	}
+	return helpers.NewErrConflict(fmt.Sprintf("cannot delete, record is still referenced by %s", strings.Join(blockers, ", ")))
+}
+
+/*
+Delete: apply the reference rules and delete a record in one transaction,
+condition holds extra conditions the record must still meet when it is deleted
+*/
+func (u *ReferenceService) Delete(colName string, id primitive.ObjectID, condition bson.M) (*models.DeleteReport, error) {
+	report, err := u.Plan(colName, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.Conflict(report); err != nil {
+		return report, err
+	}
+	filter := bson.M{"_id": id}
+	for k, v := range condition {
+		filter[k] = v
+	}
+	if err := u.ReferenceRepository.Delete(colName, filter, report.Impacts); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+/* plan: walk the rules of a collection and collect the records referencing the given ids */
+func (u *ReferenceService) plan(colName string, ids []primitive.ObjectID, report *models.DeleteReport, deleting map[string]map[primitive.ObjectID]bool) error {
+	for _, rule := range models.ReferenceRules {
+		if rule.Collection != colName {
+			continue
+		}
+		refIDs, err := u.ReferenceRepository.FindIDs(rule.RefCollection, bson.M{rule.Field: bson.M{"$in": ids}})
+		if err != nil {
+			return err
+		}
+		//records that are deleted anyway do not need their references cleaned up
+		refIDs = u.removeDeleting(refIDs, deleting[rule.RefCollection])
+		if len(refIDs) == 0 {
+			continue
+		}
+
+		impact := &models.DeleteImpact{
+			Collection: rule.RefCollection,
+			Field:      rule.Field,
+			Many:       rule.Many,
+			Action:     rule.Action,
+			IDs:        refIDs,
+			References: ids,
+		}
+		switch rule.Action {
+		case models.ReferenceRestrict:
+			report.Blockers = append(report.Blockers, impact)
+		case models.ReferenceNullify:
+			report.Impacts = append(report.Impacts, impact)
+		case models.ReferenceCascade:
+			report.Impacts = append(report.Impacts, impact)
+			if deleting[rule.RefCollection] == nil {
+				deleting[rule.RefCollection] = make(map[primitive.ObjectID]bool)
+			}
+			for _, v := range refIDs {
+				deleting[rule.RefCollection][v] = true
+			}
+			if err := u.plan(rule.RefCollection, refIDs, report, deleting); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+/* removeDeleting: remove the ids that are already planned for deletion */
+func (u *ReferenceService) removeDeleting(ids []primitive.ObjectID, deleting map[primitive.ObjectID]bool) []primitive.ObjectID {
+	tmpSlice := make([]primitive.ObjectID, 0)
+	for _, v := range ids {
+		if !deleting[v] {
+			tmpSlice = append(tmpSlice, v)
+		}
+	}
+	return tmpSlice
+}
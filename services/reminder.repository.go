@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/khanhvtn/netevent-go/database"
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ReminderRepositoryName = "ReminderRepositoryName"
+
+type ReminderRepository struct {
+	MongoCN *database.MongoInstance
+}
+
+/* createContextAndTargetCol: create and return targeted collection based on collection name */
+func (u *ReminderRepository) createContextAndTargetCol(colName string) (col *mongo.Collection,
+	ctx context.Context,
+	cancel context.CancelFunc) {
+	col = u.MongoCN.Db.Collection(colName)
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	return
+}
+
+/*Create: record a reminder and queue its mail in the same transaction, fails with a conflict when it was already queued*/
+func (u *ReminderRepository) Create(reminder models.Reminder, message *models.OutboxMessage) (*models.Reminder, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionReminderName)
+	defer cancel()
+
+	reminder.CreatedAt = time.Now()
+	reminder.Outbox = message.ID
+	err := u.MongoCN.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		result, err := collection.InsertOne(sessCtx, reminder)
+		if err != nil {
+			return err
+		}
+		reminder.ID = result.InsertedID.(primitive.ObjectID)
+		_, err = u.MongoCN.Db.Collection(models.CollectionOutboxName).InsertOne(sessCtx, message)
+		return err
+	})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, helpers.NewErrConflict("reminder is already queued")
+		}
+		return nil, err
+	}
+	return &reminder, nil
+}
+
+/*AcquireLease: take or renew a named lease, false when another instance holds it*/
+func (u *ReminderRepository) AcquireLease(name string, owner string, ttl time.Duration) (bool, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionLeaseName)
+	defer cancel()
+
+	currentTime := time.Now()
+	//the upsert hits the unique _id when the lease is held by someone else
+	_, err := collection.UpdateOne(ctx,
+		bson.M{"_id": name, "$or": bson.A{
+			bson.M{"owner": owner},
+			bson.M{"lockedUntil": bson.M{"$lte": currentTime}},
+		}},
+		bson.M{"$set": bson.M{"owner": owner, "lockedUntil": currentTime.Add(ttl)}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+/*CreateIndexes: a reminder is queued only once per target and offset*/
+func (u *ReminderRepository) CreateIndexes() error {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionReminderName)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "kind", Value: 1}, {Key: "target", Value: 1}, {Key: "offset", Value: 1}},
+		Options: options.Index().SetName("kind_target_offset_unique").SetUnique(true),
+	})
+	return err
+}
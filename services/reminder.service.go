@@ -0,0 +1,178 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ReminderServiceName = "ReminderServiceName"
+
+var (
+	//ReminderInterval: how often the due reminders are looked for
+	ReminderInterval = time.Minute
+	//ReminderLease: how long an instance owns the scheduler without renewing it
+	ReminderLease = 3 * time.Minute
+	//ReminderLeaseName: the lease shared by every instance running the scheduler
+	ReminderLeaseName = "reminders"
+	//DefaultEventReminderOffsets: participants are reminded 7 days and 1 day before the event
+	DefaultEventReminderOffsets = []time.Duration{7 * 24 * time.Hour, 24 * time.Hour}
+	//DefaultTaskReminderOffsets: assignees are reminded 1 day before their task
+	DefaultTaskReminderOffsets = []time.Duration{24 * time.Hour}
+)
+
+/* ReminderService: queues reminder mails before events and tasks start, one instance at a time */
+type ReminderService struct {
+	ReminderRepository    *ReminderRepository
+	EventRepository       *EventRepository
+	ParticipantRepository *ParticipantRepository
+	TaskRepository        *TaskRepository
+	UserRepository        *UserRepository
+	EventOffsets          []time.Duration
+	TaskOffsets           []time.Duration
+	owner                 string
+}
+
+/* ParseReminderOffsets: read a comma separated list of durations like "168h,24h", the defaults are used when it is empty */
+func ParseReminderOffsets(value string, defaults []time.Duration) ([]time.Duration, error) {
+	if strings.TrimSpace(value) == "" {
+		return defaults, nil
+	}
+	offsets := make([]time.Duration, 0)
+	for _, part := range strings.Split(value, ",") {
+		offset, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		if offset <= 0 {
+			return nil, fmt.Errorf("reminder offset %s must be positive", part)
+		}
+		offsets = append(offsets, offset)
+	}
+	return offsets, nil
+}
+
+/*Start: look for due reminders in the background*/
+func (u *ReminderService) Start() {
+	hostname, _ := os.Hostname()
+	u.owner = fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), primitive.NewObjectID().Hex())
+	go func() {
+		for {
+			u.tick(time.Now())
+			time.Sleep(ReminderInterval)
+		}
+	}()
+}
+
+/* tick: queue the due reminders when this instance holds the lease */
+func (u *ReminderService) tick(now time.Time) {
+	acquired, err := u.ReminderRepository.AcquireLease(ReminderLeaseName, u.owner, ReminderLease)
+	if err != nil {
+		log.Printf("reminders: cannot acquire the lease: %s", err.Error())
+		return
+	}
+	if !acquired {
+		return
+	}
+	if err := u.remindParticipants(now); err != nil {
+		log.Printf("reminders: cannot remind participants: %s", err.Error())
+	}
+	if err := u.remindTasks(now); err != nil {
+		log.Printf("reminders: cannot remind task assignees: %s", err.Error())
+	}
+}
+
+/*
+	remindParticipants: remind the valid participants of the events starting within an offset.
+
+Only the closest offset is sent, and only to participants registered before it was due
+*/
+func (u *ReminderService) remindParticipants(now time.Time) error {
+	handled := make(map[primitive.ObjectID]bool)
+	for _, offset := range ascending(u.EventOffsets) {
+		events, err := u.EventRepository.FindAll(bson.M{
+			"isDeleted":  false,
+			"isFinished": false,
+			"startDate":  bson.M{"$gt": now, "$lte": now.Add(offset)},
+		})
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			participants, err := u.ParticipantRepository.FindAll(bson.M{
+				"event":     event.ID,
+				"isValid":   true,
+				"status":    bson.M{"$ne": models.ParticipantWaitlisted},
+				"createdAt": bson.M{"$lte": event.StartDate.Add(-offset)},
+			})
+			if err != nil {
+				return err
+			}
+			for _, participant := range participants {
+				if handled[participant.ID] {
+					continue
+				}
+				handled[participant.ID] = true
+				if err := u.queue(participant.ID, offset, models.NewOutboxMessage(models.OutboxReminder, participant)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+/* remindTasks: remind the assignees of the tasks starting within an offset, like remindParticipants */
+func (u *ReminderService) remindTasks(now time.Time) error {
+	handled := make(map[primitive.ObjectID]bool)
+	for _, offset := range ascending(u.TaskOffsets) {
+		tasks, err := u.TaskRepository.FindAll(bson.M{
+			"startDate": bson.M{"$gt": now, "$lte": now.Add(offset)},
+		})
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			if handled[task.ID] || task.CreatedAt.After(task.StartDate.Add(-offset)) {
+				continue
+			}
+			handled[task.ID] = true
+			user, err := u.UserRepository.FindOne(bson.M{"_id": task.User})
+			if err != nil {
+				return err
+			}
+			if err := u.queue(task.ID, offset, models.NewTaskOutboxMessage(models.OutboxTaskReminder, task, user)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+/* queue: record the reminder together with its mail, a reminder queued before is skipped */
+func (u *ReminderService) queue(target primitive.ObjectID, offset time.Duration, message *models.OutboxMessage) error {
+	_, err := u.ReminderRepository.Create(models.Reminder{
+		Kind:   message.Kind,
+		Target: target,
+		Offset: offset.String(),
+	}, message)
+	if _, ok := err.(*helpers.ErrConflict); ok {
+		return nil
+	}
+	return err
+}
+
+/* ascending: the offsets from the closest to the start to the furthest */
+func ascending(offsets []time.Duration) []time.Duration {
+	sorted := append(make([]time.Duration, 0), offsets...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/khanhvtn/netevent-go/database"
+	"github.com/khanhvtn/netevent-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var SearchRepositoryName = "SearchRepositoryName"
+
+type SearchRepository struct {
+	MongoCN *database.MongoInstance
+}
+
+/* createContextAndTargetCol: create and return targeted collection based on collection name */
+func (u *SearchRepository) createContextAndTargetCol(colName string) (col *mongo.Collection,
+	ctx context.Context,
+	cancel context.CancelFunc) {
+	col = u.MongoCN.Db.Collection(colName)
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	return
+}
+
+/*CreateIndexes: a text index over the searched fields of every searchable collection*/
+func (u *SearchRepository) CreateIndexes() error {
+	for searchType, colName := range models.SearchCollections {
+		keys := bson.D{}
+		weights := bson.D{}
+		for _, field := range models.SearchFields[searchType] {
+			keys = append(keys, bson.E{Key: field.Name, Value: "text"})
+			weights = append(weights, bson.E{Key: field.Name, Value: field.Weight})
+		}
+		//words are kept as typed, without stemming, since most texts are names.
+		//events have a language field of their own, which must not be taken as the language of the index
+		indexOptions := options.Index().SetName("search_text").SetWeights(weights).
+			SetDefaultLanguage("none").SetLanguageOverride("searchLanguage")
+		if err := u.createIndex(colName, mongo.IndexModel{Keys: keys, Options: indexOptions}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+createIndex: create one text index on a collection.
+A collection holds a single text index, one left by other searched fields or another name is replaced
+*/
+func (u *SearchRepository) createIndex(colName string, index mongo.IndexModel) error {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(colName)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateOne(ctx, index)
+	//IndexOptionsConflict and IndexKeySpecsConflict
+	var commandErr mongo.CommandError
+	if !errors.As(err, &commandErr) || (commandErr.Code != 85 && commandErr.Code != 86) {
+		return err
+	}
+	cur, err := collection.Indexes().List(ctx)
+	if err != nil {
+		return err
+	}
+	indexes := make([]struct {
+		Name string `bson:"name"`
+		Key  bson.M `bson:"key"`
+	}, 0)
+	if err := cur.All(ctx, &indexes); err != nil {
+		return err
+	}
+	for _, existing := range indexes {
+		if existing.Key["_fts"] != "text" {
+			continue
+		}
+		log.Printf("search: replacing the text index %s of %s", existing.Name, colName)
+		if _, err := collection.Indexes().DropOne(ctx, existing.Name); err != nil {
+			return err
+		}
+	}
+	_, err = collection.Indexes().CreateOne(ctx, index)
+	return err
+}
+
+/*Text: the best records of a type for a $text search, with their text score*/
+func (u *SearchRepository) Text(searchType string, query string, limit int64) ([]*models.SearchHit, error) {
+	score := bson.M{"$meta": "textScore"}
+	findOptions := options.Find().SetProjection(bson.M{"score": score}).SetSort(bson.M{"score": score}).SetLimit(limit)
+	return u.find(searchType, searchable(searchType, bson.M{"$text": bson.M{"$search": query}}), findOptions)
+}
+
+/*
+Match: records of a type where every pattern matches one of the searched fields, newest first.
+The patterns cannot use an index, the records are walked along the _id index so the scan stops once limit records match
+*/
+func (u *SearchRepository) Match(searchType string, patterns []string, limit int64) ([]*models.SearchHit, error) {
+	conditions := bson.A{}
+	for _, pattern := range patterns {
+		fields := bson.A{}
+		for _, field := range models.SearchFields[searchType] {
+			fields = append(fields, bson.M{field.Name: bson.M{"$regex": pattern, "$options": "i"}})
+		}
+		conditions = append(conditions, bson.M{"$or": fields})
+	}
+	findOptions := options.Find().SetSort(bson.M{"_id": -1}).SetLimit(limit)
+	return u.find(searchType, searchable(searchType, bson.M{"$and": conditions}), findOptions)
+}
+
+/* searchable: narrow the filter of a search to the records it may return, deleted events are left out */
+func searchable(searchType string, filter bson.M) bson.M {
+	if searchType == models.SearchEvent {
+		filter["isDeleted"] = false
+	}
+	return filter
+}
+
+/* find: decode the records of a search into hits of their type */
+func (u *SearchRepository) find(searchType string, filter bson.M, findOptions *options.FindOptions) ([]*models.SearchHit, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.SearchCollections[searchType])
+	defer cancel()
+
+	cur, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	hits := make([]*models.SearchHit, 0)
+	for cur.Next(ctx) {
+		var scored struct {
+			Score float64 `bson:"score"`
+		}
+		if err := cur.Decode(&scored); err != nil {
+			return nil, err
+		}
+		hit := &models.SearchHit{Type: searchType, TextScore: scored.Score}
+		switch searchType {
+		case models.SearchEvent:
+			var event models.Event
+			if err := cur.Decode(&event); err != nil {
+				return nil, err
+			}
+			hit.ID, hit.Event = event.ID, &event
+		case models.SearchParticipant:
+			var participant models.Participant
+			if err := cur.Decode(&participant); err != nil {
+				return nil, err
+			}
+			hit.ID, hit.Participant = participant.ID, &participant
+		}
+		hits = append(hits, hit)
+	}
+	return hits, cur.Err()
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"github.com/khanhvtn/netevent-go/utilities"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var SearchServiceName = "SearchServiceName"
+
+var (
+	//SearchDefaultLimit: results returned when the client does not ask for a number
+	SearchDefaultLimit = 20
+	//SearchMaxLimit: the most results a search returns
+	SearchMaxLimit = 50
+	//SearchSnippetLength: runes of a snippet around the first match
+	SearchSnippetLength = 160
+	//SearchMaxQueryLength: the most runes of a query, every term becomes a pattern tried on every field
+	SearchMaxQueryLength = 100
+	//SearchMaxTerms: the most words of a query
+	SearchMaxTerms = 8
+)
+
+//scores of a term matching a whole word, the start of a word, or a word with a typo
+var (
+	searchExactScore  = 1.0
+	searchPrefixScore = 0.75
+	searchTypoScore   = 0.5
+)
+
+/* SearchService: finds events and participants for the search box of the admin ui */
+type SearchService struct {
+	SearchRepository *SearchRepository
+}
+
+/*
+Search: ranked events and participants for a query.
+Candidates come from the text index, and from a scan accepting word prefixes and typos for the terms the index found no word for,
+then every record is ranked on the weighted matches of the terms in its fields plus its text score
+*/
+func (u *SearchService) Search(query string, types []string, limit int) ([]*models.SearchHit, error) {
+	if utf8.RuneCountInString(query) > SearchMaxQueryLength {
+		return nil, helpers.NewErrValidation(fmt.Sprintf("query must not be longer than %d characters", SearchMaxQueryLength))
+	}
+	terms := utilities.SearchTerms(query)
+	if len(terms) == 0 {
+		return nil, helpers.NewErrValidation("query must contain a word")
+	}
+	if len(terms) > SearchMaxTerms {
+		return nil, helpers.NewErrValidation(fmt.Sprintf("query must not have more than %d words", SearchMaxTerms))
+	}
+	if limit < 1 || limit > SearchMaxLimit {
+		return nil, helpers.NewErrValidation(fmt.Sprintf("limit must be between 1 and %d", SearchMaxLimit))
+	}
+	if len(types) == 0 {
+		types = []string{models.SearchEvent, models.SearchParticipant}
+	}
+	//more candidates than results so the ranking has something to choose from
+	candidates := int64(3 * limit)
+	hits := make([]*models.SearchHit, 0)
+	for _, searchType := range types {
+		textHits, err := u.SearchRepository.Text(searchType, strings.Join(terms, " "), candidates)
+		if err != nil {
+			return nil, err
+		}
+		//the scan cannot use an index, it only runs when some term is not a whole word of a text hit
+		patterns := make([]string, 0)
+		for _, term := range terms {
+			if !answered(textHits, term) {
+				patterns = append(patterns, utilities.FuzzyPattern(term))
+			}
+		}
+		matchHits := make([]*models.SearchHit, 0)
+		if len(patterns) > 0 {
+			if matchHits, err = u.SearchRepository.Match(searchType, patterns, candidates); err != nil {
+				return nil, err
+			}
+		}
+		//a record found both ways keeps its text score
+		seen := make(map[primitive.ObjectID]bool)
+		for _, hit := range append(textHits, matchHits...) {
+			if seen[hit.ID] {
+				continue
+			}
+			seen[hit.ID] = true
+			if u.rank(hit, terms) {
+				hits = append(hits, hit)
+			}
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Score > hits[j].Score
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+/* rank: score a hit and take its title and snippet, false when nothing matches */
+func (u *SearchService) rank(hit *models.SearchHit, terms []string) bool {
+	fields := models.SearchFields[hit.Type]
+	values := hit.FieldValues()
+	best := 0.0
+	for _, field := range fields {
+		tokens := utilities.Tokenize(values[field.Name])
+		matched := make([]bool, len(tokens))
+		fieldScore := 0.0
+		for _, term := range terms {
+			termScore := 0.0
+			for i, token := range tokens {
+				if score := searchMatch(term, token.Folded); score > 0 {
+					matched[i] = true
+					if score > termScore {
+						termScore = score
+					}
+				}
+			}
+			fieldScore += termScore * float64(field.Weight)
+		}
+		hit.Score += fieldScore
+		//the snippet comes from the field weighing the most in the score
+		if fieldScore > best {
+			best = fieldScore
+			spans := make([][2]int, 0)
+			for i, token := range tokens {
+				if matched[i] {
+					spans = append(spans, [2]int{token.Start, token.End})
+				}
+			}
+			hit.Field = field.Name
+			hit.Snippet = utilities.Highlight(values[field.Name], spans, SearchSnippetLength)
+		}
+	}
+	hit.Score += hit.TextScore
+	if hit.Score == 0 {
+		return false
+	}
+	//a text match the terms do not explain is shown from the first field
+	if hit.Field == "" {
+		hit.Field = fields[0].Name
+		hit.Snippet = utilities.Highlight(values[hit.Field], nil, SearchSnippetLength)
+	}
+	hit.Title = values[fields[0].Name]
+	if hit.Participant != nil && hit.Title == "" {
+		hit.Title = hit.Participant.Email
+	}
+	return true
+}
+
+/* answered: whether a folded term is a whole word of one of the hits */
+func answered(hits []*models.SearchHit, term string) bool {
+	for _, hit := range hits {
+		for _, value := range hit.FieldValues() {
+			for _, token := range utilities.Tokenize(value) {
+				if token.Folded == term {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+/* searchMatch: how well a folded term matches a folded word, 0 when it does not */
+func searchMatch(term string, word string) float64 {
+	switch {
+	case word == term:
+		return searchExactScore
+	case strings.HasPrefix(word, term):
+		return searchPrefixScore
+	}
+	typos := utilities.AllowedTypos(term)
+	if typos == 0 {
+		return 0
+	}
+	if distance := utilities.PrefixDistance(term, word); distance <= typos {
+		return searchTypoScore / float64(distance)
+	}
+	return 0
+}
@@ -2,9 +2,11 @@ package services
 
 import (
 	"context"
+	"os"
 	"time"
 
 	"github.com/khanhvtn/netevent-go/database"
+	"github.com/khanhvtn/netevent-go/models"
 	"github.com/sarulabs/di"
 )
 
@@ -29,6 +31,9 @@ func New() (*DI, error) {
 	return &DI{Container: app}, nil
 }
 
+//MailerName: the mail transport selected by the MAIL_TRANSPORT config
+var MailerName = "MailerName"
+
 // Services contains the definitions of the application services.
 var services = []di.Def{
 	{
@@ -54,7 +59,8 @@ var services = []di.Def{
 		Name: UserServiceName,
 		Build: func(ctn di.Container) (interface{}, error) {
 			return &UserService{
-				UserRepository: ctn.Get(UserRepositoryName).(*UserRepository),
+				UserRepository:   ctn.Get(UserRepositoryName).(*UserRepository),
+				ReferenceService: ctn.Get(ReferenceServiceName).(*ReferenceService),
 			}, nil
 		},
 	},
@@ -71,6 +77,7 @@ var services = []di.Def{
 		Build: func(ctn di.Container) (interface{}, error) {
 			return &EventTypeService{
 				EventTypeRepository: ctn.Get(EventTypeRepositoryName).(*EventTypeRepository),
+				ReferenceService:    ctn.Get(ReferenceServiceName).(*ReferenceService),
 			}, nil
 		},
 	},
@@ -86,7 +93,11 @@ var services = []di.Def{
 		Name: FacilityServiceName,
 		Build: func(ctn di.Container) (interface{}, error) {
 			return &FacilityService{
-				FacilityRepository: ctn.Get(FacilityRepositoryName).(*FacilityRepository),
+				FacilityRepository:        ctn.Get(FacilityRepositoryName).(*FacilityRepository),
+				FacilityTypeRepository:    ctn.Get(FacilityTypeRepositoryName).(*FacilityTypeRepository),
+				FacilityHistoryRepository: ctn.Get(FacilityHistoryRepositoryName).(*FacilityHistoryRepository),
+				FacilityHistoryService:    ctn.Get(FacilityHistoryServiceName).(*FacilityHistoryService),
+				ReferenceService:          ctn.Get(ReferenceServiceName).(*ReferenceService),
 			}, nil
 		},
 	},
@@ -105,6 +116,11 @@ var services = []di.Def{
 				EventRepository:           ctn.Get(EventRepositoryName).(*EventRepository),
 				TaskRepository:            ctn.Get(TaskRepositoryName).(*TaskRepository),
 				FacilityHistoryRepository: ctn.Get(FacilityHistoryRepositoryName).(*FacilityHistoryRepository),
+				FacilityHistoryService:    ctn.Get(FacilityHistoryServiceName).(*FacilityHistoryService),
+				ReferenceService:          ctn.Get(ReferenceServiceName).(*ReferenceService),
+				ParticipantService:        ctn.Get(ParticipantServiceName).(*ParticipantService),
+				UserRepository:            ctn.Get(UserRepositoryName).(*UserRepository),
+				SessionRepository:         ctn.Get(SessionRepositoryName).(*SessionRepository),
 			}, nil
 		},
 	},
@@ -121,6 +137,9 @@ var services = []di.Def{
 		Build: func(ctn di.Container) (interface{}, error) {
 			return &FacilityHistoryService{
 				FacilityHistoryRepository: ctn.Get(FacilityHistoryRepositoryName).(*FacilityHistoryRepository),
+				FacilityRepository:        ctn.Get(FacilityRepositoryName).(*FacilityRepository),
+				EventRepository:           ctn.Get(EventRepositoryName).(*EventRepository),
+				ReferenceService:          ctn.Get(ReferenceServiceName).(*ReferenceService),
 			}, nil
 		},
 	},
@@ -137,6 +156,9 @@ var services = []di.Def{
 		Build: func(ctn di.Container) (interface{}, error) {
 			return &ParticipantService{
 				ParticipantRepository: ctn.Get(ParticipantRepositoryName).(*ParticipantRepository),
+				EventRepository:       ctn.Get(EventRepositoryName).(*EventRepository),
+				OutboxService:         ctn.Get(OutboxServiceName).(*OutboxService),
+				ReferenceService:      ctn.Get(ReferenceServiceName).(*ReferenceService),
 			}, nil
 		},
 	},
@@ -152,7 +174,313 @@ var services = []di.Def{
 		Name: TaskServiceName,
 		Build: func(ctn di.Container) (interface{}, error) {
 			return &TaskService{
-				TaskRepository: ctn.Get(TaskRepositoryName).(*TaskRepository),
+				TaskRepository:   ctn.Get(TaskRepositoryName).(*TaskRepository),
+				ReferenceService: ctn.Get(ReferenceServiceName).(*ReferenceService),
+			}, nil
+		},
+	}, {
+		Name: ReferenceRepositoryName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return &ReferenceRepository{
+				MongoCN: ctn.Get(database.MongoCNName).(*database.MongoInstance),
+			}, nil
+		},
+	},
+	{
+		Name: ReferenceServiceName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return &ReferenceService{
+				ReferenceRepository: ctn.Get(ReferenceRepositoryName).(*ReferenceRepository),
+			}, nil
+		},
+	}, {
+		Name: FacilityTypeRepositoryName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return &FacilityTypeRepository{
+				MongoCN: ctn.Get(database.MongoCNName).(*database.MongoInstance),
+			}, nil
+		},
+	},
+	{
+		Name: FacilityTypeServiceName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return &FacilityTypeService{
+				FacilityTypeRepository: ctn.Get(FacilityTypeRepositoryName).(*FacilityTypeRepository),
+				FacilityRepository:     ctn.Get(FacilityRepositoryName).(*FacilityRepository),
+				ReferenceService:       ctn.Get(ReferenceServiceName).(*ReferenceService),
+			}, nil
+		},
+	},
+	{
+		Name: OutboxRepositoryName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return &OutboxRepository{
+				MongoCN: ctn.Get(database.MongoCNName).(*database.MongoInstance),
+			}, nil
+		},
+	},
+	{
+		Name: OutboxServiceName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return &OutboxService{
+				OutboxRepository:      ctn.Get(OutboxRepositoryName).(*OutboxRepository),
+				ParticipantRepository: ctn.Get(ParticipantRepositoryName).(*ParticipantRepository),
+				EventRepository:       ctn.Get(EventRepositoryName).(*EventRepository),
+				TaskRepository:        ctn.Get(TaskRepositoryName).(*TaskRepository),
+				UserRepository:        ctn.Get(UserRepositoryName).(*UserRepository),
+				Mailer:                ctn.Get(MailerName).(models.Mailer),
+				EmailTemplateService:  ctn.Get(EmailTemplateServiceName).(*EmailTemplateService),
+				CalendarService:       ctn.Get(CalendarServiceName).(*CalendarService),
+			}, nil
+		},
+	},
+	{
+		Name: MailerName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return models.NewMailer(models.MailerConfigFromEnv())
+		},
+	},
+	{
+		Name: EmailTemplateRepositoryName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return &EmailTemplateRepository{
+				MongoCN: ctn.Get(database.MongoCNName).(*database.MongoInstance),
+			}, nil
+		},
+	},
+	{
+		Name: EmailTemplateServiceName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			location, err := LoadCalendarLocation(os.Getenv("CALENDAR_TIMEZONE"))
+			if err != nil {
+				return nil, err
+			}
+			appURL := os.Getenv("APP_URL")
+			if appURL == "" {
+				appURL = DefaultAppURL
+			}
+			return &EmailTemplateService{
+				EmailTemplateRepository: ctn.Get(EmailTemplateRepositoryName).(*EmailTemplateRepository),
+				Location:                location,
+				AppURL:                  appURL,
+			}, nil
+		},
+	},
+	{
+		Name: ReminderRepositoryName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return &ReminderRepository{
+				MongoCN: ctn.Get(database.MongoCNName).(*database.MongoInstance),
+			}, nil
+		},
+	},
+	{
+		Name: ReminderServiceName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			eventOffsets, err := ParseReminderOffsets(os.Getenv("EVENT_REMINDER_OFFSETS"), DefaultEventReminderOffsets)
+			if err != nil {
+				return nil, err
+			}
+			taskOffsets, err := ParseReminderOffsets(os.Getenv("TASK_REMINDER_OFFSETS"), DefaultTaskReminderOffsets)
+			if err != nil {
+				return nil, err
+			}
+			return &ReminderService{
+				ReminderRepository:    ctn.Get(ReminderRepositoryName).(*ReminderRepository),
+				EventRepository:       ctn.Get(EventRepositoryName).(*EventRepository),
+				ParticipantRepository: ctn.Get(ParticipantRepositoryName).(*ParticipantRepository),
+				TaskRepository:        ctn.Get(TaskRepositoryName).(*TaskRepository),
+				UserRepository:        ctn.Get(UserRepositoryName).(*UserRepository),
+				EventOffsets:          eventOffsets,
+				TaskOffsets:           taskOffsets,
+			}, nil
+		},
+	},
+	{
+		Name: StatisticRepositoryName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return &StatisticRepository{
+				MongoCN: ctn.Get(database.MongoCNName).(*database.MongoInstance),
+			}, nil
+		},
+	},
+	{
+		Name: StatisticServiceName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return &StatisticService{
+				StatisticRepository: ctn.Get(StatisticRepositoryName).(*StatisticRepository),
+				EventRepository:     ctn.Get(EventRepositoryName).(*EventRepository),
+			}, nil
+		},
+	},
+	{
+		Name: SessionRepositoryName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return &SessionRepository{
+				MongoCN: ctn.Get(database.MongoCNName).(*database.MongoInstance),
+			}, nil
+		},
+	},
+	{
+		Name: SessionServiceName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return &SessionService{
+				SessionRepository:     ctn.Get(SessionRepositoryName).(*SessionRepository),
+				EventRepository:       ctn.Get(EventRepositoryName).(*EventRepository),
+				FacilityRepository:    ctn.Get(FacilityRepositoryName).(*FacilityRepository),
+				ParticipantRepository: ctn.Get(ParticipantRepositoryName).(*ParticipantRepository),
+				ReferenceService:      ctn.Get(ReferenceServiceName).(*ReferenceService),
+			}, nil
+		},
+	},
+	{
+		Name: BudgetItemRepositoryName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return &BudgetItemRepository{
+				MongoCN: ctn.Get(database.MongoCNName).(*database.MongoInstance),
+			}, nil
+		},
+	},
+	{
+		Name: BudgetItemServiceName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return &BudgetItemService{
+				BudgetItemRepository: ctn.Get(BudgetItemRepositoryName).(*BudgetItemRepository),
+				ExpenseRepository:    ctn.Get(ExpenseRepositoryName).(*ExpenseRepository),
+				EventRepository:      ctn.Get(EventRepositoryName).(*EventRepository),
+				ReferenceService:     ctn.Get(ReferenceServiceName).(*ReferenceService),
+			}, nil
+		},
+	},
+	{
+		Name: ExpenseRepositoryName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return &ExpenseRepository{
+				MongoCN: ctn.Get(database.MongoCNName).(*database.MongoInstance),
+			}, nil
+		},
+	},
+	{
+		Name: ExpenseServiceName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			thresholds, err := ParseExpenseApprovalThresholds(os.Getenv("EXPENSE_APPROVAL_THRESHOLDS"), DefaultExpenseApprovalThresholds)
+			if err != nil {
+				return nil, err
+			}
+			return &ExpenseService{
+				ExpenseRepository:    ctn.Get(ExpenseRepositoryName).(*ExpenseRepository),
+				BudgetItemRepository: ctn.Get(BudgetItemRepositoryName).(*BudgetItemRepository),
+				ReferenceService:     ctn.Get(ReferenceServiceName).(*ReferenceService),
+				Thresholds:           thresholds,
+			}, nil
+		},
+	},
+	{
+		Name: CalendarServiceName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			location, err := LoadCalendarLocation(os.Getenv("CALENDAR_TIMEZONE"))
+			if err != nil {
+				return nil, err
+			}
+			return &CalendarService{
+				EventRepository: ctn.Get(EventRepositoryName).(*EventRepository),
+				TaskRepository:  ctn.Get(TaskRepositoryName).(*TaskRepository),
+				UserRepository:  ctn.Get(UserRepositoryName).(*UserRepository),
+				Location:        location,
+			}, nil
+		},
+	},
+	{
+		Name: EventSeriesRepositoryName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return &EventSeriesRepository{
+				MongoCN: ctn.Get(database.MongoCNName).(*database.MongoInstance),
+			}, nil
+		},
+	},
+	{
+		Name: EventSeriesServiceName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			//occurrences follow the wall clock of the same zone as the calendars
+			location, err := LoadCalendarLocation(os.Getenv("CALENDAR_TIMEZONE"))
+			if err != nil {
+				return nil, err
+			}
+			return &EventSeriesService{
+				EventSeriesRepository:     ctn.Get(EventSeriesRepositoryName).(*EventSeriesRepository),
+				EventRepository:           ctn.Get(EventRepositoryName).(*EventRepository),
+				TaskRepository:            ctn.Get(TaskRepositoryName).(*TaskRepository),
+				FacilityHistoryRepository: ctn.Get(FacilityHistoryRepositoryName).(*FacilityHistoryRepository),
+				EventService:              ctn.Get(EventServiceName).(*EventService),
+				ReferenceService:          ctn.Get(ReferenceServiceName).(*ReferenceService),
+				ParticipantService:        ctn.Get(ParticipantServiceName).(*ParticipantService),
+				Location:                  location,
+			}, nil
+		},
+	},
+	{
+		Name: EventTemplateRepositoryName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return &EventTemplateRepository{
+				MongoCN: ctn.Get(database.MongoCNName).(*database.MongoInstance),
+			}, nil
+		},
+	},
+	{
+		Name: EventTemplateServiceName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return &EventTemplateService{
+				EventTemplateRepository:   ctn.Get(EventTemplateRepositoryName).(*EventTemplateRepository),
+				EventRepository:           ctn.Get(EventRepositoryName).(*EventRepository),
+				TaskRepository:            ctn.Get(TaskRepositoryName).(*TaskRepository),
+				FacilityHistoryRepository: ctn.Get(FacilityHistoryRepositoryName).(*FacilityHistoryRepository),
+				EventService:              ctn.Get(EventServiceName).(*EventService),
+				ReferenceService:          ctn.Get(ReferenceServiceName).(*ReferenceService),
+				UserRepository:            ctn.Get(UserRepositoryName).(*UserRepository),
+			}, nil
+		},
+	},
+	{
+		Name: SearchRepositoryName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return &SearchRepository{
+				MongoCN: ctn.Get(database.MongoCNName).(*database.MongoInstance),
+			}, nil
+		},
+	},
+	{
+		Name: SearchServiceName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return &SearchService{
+				SearchRepository: ctn.Get(SearchRepositoryName).(*SearchRepository),
+			}, nil
+		},
+	},
+	{
+		Name: DashboardServiceName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return &DashboardService{
+				StatisticRepository: ctn.Get(StatisticRepositoryName).(*StatisticRepository),
+			}, nil
+		},
+	},
+	{
+		Name: ExportRepositoryName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return &ExportRepository{
+				MongoCN: ctn.Get(database.MongoCNName).(*database.MongoInstance),
+			}, nil
+		},
+	},
+	{
+		Name: ExportServiceName,
+		Build: func(ctn di.Container) (interface{}, error) {
+			return &ExportService{
+				ExportRepository:    ctn.Get(ExportRepositoryName).(*ExportRepository),
+				EventRepository:     ctn.Get(EventRepositoryName).(*EventRepository),
+				UserRepository:      ctn.Get(UserRepositoryName).(*UserRepository),
+				EventTypeRepository: ctn.Get(EventTypeRepositoryName).(*EventTypeRepository),
+				FacilityRepository:  ctn.Get(FacilityRepositoryName).(*FacilityRepository),
 			}, nil
 		},
 	},
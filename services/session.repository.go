@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/khanhvtn/netevent-go/database"
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var SessionRepositoryName = "SessionRepositoryName"
+
+type SessionRepository struct {
+	MongoCN *database.MongoInstance
+}
+
+/* createContextAndTargetCol: create and return targeted collection based on collection name */
+func (u *SessionRepository) createContextAndTargetCol(colName string) (col *mongo.Collection,
+	ctx context.Context,
+	cancel context.CancelFunc) {
+	col = u.MongoCN.Db.Collection(colName)
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	return
+}
+
+/* FindAll: get all data based on condition*/
+func (u *SessionRepository) FindAll(condition bson.M) ([]*models.Session, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionSessionName)
+	defer cancel()
+
+	//create an empty array to store all fields from collection
+	var sessions []*models.Session = make([]*models.Session, 0)
+
+	//get all record
+	cur, err := collection.Find(ctx, condition)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	//map data to target variable
+	for cur.Next(ctx) {
+		var session models.Session
+		cur.Decode(&session)
+		sessions = append(sessions, &session)
+	}
+	//response data to client
+	if sessions == nil {
+		return make([]*models.Session, 0), nil
+	}
+	return sessions, nil
+}
+
+/*FindOne: get one record from a collection  */
+func (u *SessionRepository) FindOne(filter bson.M) (*models.Session, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionSessionName)
+	defer cancel()
+
+	session := models.Session{}
+	//Decode record into result
+	if err := collection.FindOne(ctx, filter).Decode(&session); err != nil {
+		if err == mongo.ErrNoDocuments {
+			//return nil data when id is not existed.
+			return nil, helpers.NewErrNotFound("session id is not found")
+		}
+		//return err if there is a system error
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+/*Create: create a new record to a collection*/
+func (u *SessionRepository) Create(session *models.Session) (*models.Session, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionSessionName)
+	defer cancel()
+
+	if _, err := collection.InsertOne(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+/*UpdateOne: update one record from a collection*/
+func (u SessionRepository) UpdateOne(filter bson.M, update bson.M) (*models.Session, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionSessionName)
+	defer cancel()
+
+	//update session information
+	newUpdate := bson.M{"$set": update}
+	updateResult, err := collection.UpdateOne(ctx, filter, newUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return nil, helpers.NewErrNotFound("session id is not found")
+	}
+
+	//query the new update
+	session, errQuery := u.FindOne(filter)
+	if errQuery != nil {
+		return nil, errQuery
+	}
+
+	return session, nil
+}
+
+//DeleteOne func is to update one record from a collection
+func (u SessionRepository) DeleteOne(filter bson.M) (*models.Session, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionSessionName)
+	defer cancel()
+
+	session, errFind := u.FindOne(filter)
+	if errFind != nil {
+		return nil, errFind
+	}
+
+	//delete session from database
+	deleteResult, err := collection.DeleteOne(ctx, filter)
+	if err != nil {
+		//response to client if there is an error.
+		return nil, err
+	}
+
+	if deleteResult.DeletedCount == 0 {
+		return nil, helpers.NewErrNotFound("session id is not found")
+	}
+
+	return session, nil
+}
+
+/*
+AddAttendee: register a participant for a session that does not overlap their other sessions, fails when the session is full.
+The participant is written first in the same transaction, so two registrations of one participant conflict and are retried one after the other
+*/
+func (u *SessionRepository) AddAttendee(session *models.Session, participantId primitive.ObjectID) error {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionSessionName)
+	defer cancel()
+	participantCollection := u.MongoCN.Db.Collection(models.CollectionParticipantName)
+
+	return u.MongoCN.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		currentTime := time.Now()
+		if _, err := participantCollection.UpdateOne(sessCtx, bson.M{"_id": participantId}, bson.M{"$set": bson.M{"updatedAt": currentTime}}); err != nil {
+			return err
+		}
+		cur, err := collection.Find(sessCtx, bson.M{"event": session.Event, "attendees": participantId})
+		if err != nil {
+			return err
+		}
+		registered := make([]*models.Session, 0)
+		if err := cur.All(sessCtx, &registered); err != nil {
+			return err
+		}
+		for _, other := range registered {
+			if other.ID != session.ID && other.Overlaps(session) {
+				return helpers.NewErrConflict(fmt.Sprintf("participant is already registered for %s at that time", other.Title))
+			}
+		}
+
+		//a session without capacity has no limit
+		filter := bson.M{"_id": session.ID, "$expr": bson.M{"$or": bson.A{
+			bson.M{"$lte": bson.A{"$capacity", 0}},
+			bson.M{"$lt": bson.A{bson.M{"$size": bson.M{"$ifNull": bson.A{"$attendees", bson.A{}}}}, "$capacity"}},
+		}}}
+		updateResult, err := collection.UpdateOne(sessCtx, filter, bson.M{
+			"$addToSet": bson.M{"attendees": participantId},
+			"$set":      bson.M{"updatedAt": currentTime},
+		})
+		if err != nil {
+			return err
+		}
+		if updateResult.MatchedCount == 0 {
+			return helpers.NewErrConflict("session is full")
+		}
+		return nil
+	})
+}
+
+/*RemoveAttendee: give back the seat of a participant*/
+func (u *SessionRepository) RemoveAttendee(sessionId primitive.ObjectID, participantId primitive.ObjectID) (*models.Session, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionSessionName)
+	defer cancel()
+
+	filter := bson.M{"_id": sessionId}
+	updateResult, err := collection.UpdateOne(ctx, filter, bson.M{
+		"$pull": bson.M{"attendees": participantId},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if updateResult.MatchedCount == 0 {
+		return nil, helpers.NewErrNotFound("session id is not found")
+	}
+	return u.FindOne(filter)
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/helpers"
+	"github.com/khanhvtn/netevent-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var SessionServiceName = "SessionServiceName"
+
+type SessionService struct {
+	SessionRepository     *SessionRepository
+	EventRepository       *EventRepository
+	FacilityRepository    *FacilityRepository
+	ParticipantRepository *ParticipantRepository
+	ReferenceService      *ReferenceService
+}
+
+/* GetAll: get all data based on condition*/
+func (u *SessionService) GetAll(condition bson.M) ([]*models.Session, error) {
+	return u.SessionRepository.FindAll(condition)
+}
+
+/*GetOne: get one record from a collection  */
+func (u *SessionService) GetOne(filter bson.M) (*models.Session, error) {
+	return u.SessionRepository.FindOne(filter)
+}
+
+/*Create: add a session to the agenda of an event, refused when its room or a speaker is taken at that time*/
+func (u *SessionService) Create(newSession model.NewSession) (*models.Session, error) {
+	eventId, err := primitive.ObjectIDFromHex(newSession.EventID)
+	if err != nil {
+		return nil, helpers.NewErrValidation("invalid event id")
+	}
+	currentTime := time.Now()
+	session := &models.Session{
+		ID:        primitive.NewObjectID(),
+		CreatedAt: currentTime,
+		UpdatedAt: currentTime,
+		Event:     eventId,
+		Attendees: make([]primitive.ObjectID, 0),
+	}
+	if err := u.apply(session, newSession.Title, newSession.Description, newSession.Track, newSession.StartDate, newSession.EndDate, newSession.RoomID, newSession.Speakers, newSession.Capacity); err != nil {
+		return nil, err
+	}
+	return u.SessionRepository.Create(session)
+}
+
+/*UpdateOne: change a session, the checks of a new session are run again*/
+func (u *SessionService) UpdateOne(id primitive.ObjectID, update model.UpdateSession) (*models.Session, error) {
+	session, err := u.SessionRepository.FindOne(bson.M{"_id": id})
+	if err != nil {
+		return nil, err
+	}
+	if err := u.apply(session, update.Title, update.Description, update.Track, update.StartDate, update.EndDate, update.RoomID, update.Speakers, update.Capacity); err != nil {
+		return nil, err
+	}
+	if session.Capacity > 0 && len(session.Attendees) > session.Capacity {
+		return nil, helpers.NewErrValidation(fmt.Sprintf("capacity cannot be lower than the %d registered participants", len(session.Attendees)))
+	}
+	return u.SessionRepository.UpdateOne(bson.M{"_id": id}, bson.M{
+		"updatedAt":   time.Now(),
+		"title":       session.Title,
+		"description": session.Description,
+		"track":       session.Track,
+		"startDate":   session.StartDate,
+		"endDate":     session.EndDate,
+		"room":        session.Room,
+		"speakers":    session.Speakers,
+		"capacity":    session.Capacity,
+	})
+}
+
+//DeleteOne func is to delete one record from a collection once its references are resolved
+func (u *SessionService) DeleteOne(filter bson.M) (*models.Session, error) {
+	session, err := u.SessionRepository.FindOne(filter)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.ReferenceService.Delete(models.CollectionSessionName, session.ID, nil); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+/*Register: give a registered participant of the event a seat in a session that does not clash with their other sessions*/
+func (u *SessionService) Register(sessionId primitive.ObjectID, participantId primitive.ObjectID) (*models.Session, error) {
+	session, err := u.SessionRepository.FindOne(bson.M{"_id": sessionId})
+	if err != nil {
+		return nil, err
+	}
+	participant, err := u.ParticipantRepository.FindOne(bson.M{"_id": participantId})
+	if err != nil {
+		return nil, err
+	}
+	if participant.Event != session.Event {
+		return nil, helpers.NewErrValidation("participant is not registered for the event of the session")
+	}
+	if participant.IsWaitlisted() {
+		return nil, helpers.NewErrValidation("a waitlisted participant cannot register for sessions")
+	}
+	for _, attendee := range session.Attendees {
+		if attendee == participantId {
+			return session, nil
+		}
+	}
+	if err := u.SessionRepository.AddAttendee(session, participantId); err != nil {
+		return nil, err
+	}
+	return u.SessionRepository.FindOne(bson.M{"_id": sessionId})
+}
+
+/*Unregister: free the seat of a participant in a session*/
+func (u *SessionService) Unregister(sessionId primitive.ObjectID, participantId primitive.ObjectID) (*models.Session, error) {
+	return u.SessionRepository.RemoveAttendee(sessionId, participantId)
+}
+
+/* apply: set the fields of a session and check it fits the event and clashes with no other session */
+func (u *SessionService) apply(session *models.Session, title string, description *string, track *string, startDate time.Time, endDate time.Time, roomId *string, speakers []*model.SpeakerInput, capacity *int) error {
+	event, err := u.EventRepository.FindOne(bson.M{"_id": session.Event})
+	if err != nil {
+		return err
+	}
+	session.Title = title
+	session.Description = ""
+	if description != nil {
+		session.Description = *description
+	}
+	session.Track = ""
+	if track != nil {
+		session.Track = *track
+	}
+	session.StartDate = startDate
+	session.EndDate = endDate
+	session.Room = nil
+	if roomId != nil && *roomId != "" {
+		id, err := primitive.ObjectIDFromHex(*roomId)
+		if err != nil {
+			return helpers.NewErrValidation("invalid room id")
+		}
+		if _, err := u.FacilityRepository.FindOne(bson.M{"_id": id}); err != nil {
+			return err
+		}
+		session.Room = &id
+	}
+	session.Speakers = make([]*models.Speaker, 0)
+	for _, speaker := range speakers {
+		s := &models.Speaker{Name: strings.TrimSpace(speaker.Name)}
+		if speaker.Email != nil {
+			s.Email = strings.TrimSpace(*speaker.Email)
+		}
+		session.Speakers = append(session.Speakers, s)
+	}
+	session.Capacity = 0
+	if capacity != nil {
+		session.Capacity = *capacity
+	}
+
+	if session.StartDate.Before(event.StartDate) || session.EndDate.After(event.EndDate) {
+		return helpers.NewErrValidation("session must take place between the start and the end of the event")
+	}
+	return u.checkConflicts(session)
+}
+
+/* checkConflicts: refuse a session sharing its room or a speaker with another session at the same time, of any event */
+func (u *SessionService) checkConflicts(session *models.Session) error {
+	overlapping, err := u.SessionRepository.FindAll(bson.M{
+		"_id":       bson.M{"$ne": session.ID},
+		"startDate": bson.M{"$lt": session.EndDate},
+		"endDate":   bson.M{"$gt": session.StartDate},
+	})
+	if err != nil {
+		return err
+	}
+	conflicts := make([]string, 0)
+	for _, other := range overlapping {
+		if session.Room != nil && other.Room != nil && *session.Room == *other.Room {
+			conflicts = append(conflicts, fmt.Sprintf("room is used by %s", other.Title))
+		}
+		for _, speaker := range session.Speakers {
+			for _, otherSpeaker := range other.Speakers {
+				if speaker.SameAs(otherSpeaker) {
+					conflicts = append(conflicts, fmt.Sprintf("%s is speaking at %s", speaker.Name, other.Title))
+				}
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		return helpers.NewErrConflict(fmt.Sprintf("session clashes with other sessions: %s", strings.Join(conflicts, ", ")))
+	}
+	return nil
+}
+
+//validation
+func (u *SessionService) ValidateNewSession(newSession model.NewSession) error {
+	return validation.ValidateStruct(&newSession,
+		validation.Field(&newSession.EventID, validation.Required.Error("event id must not be blanked")),
+		validation.Field(&newSession.Title, validation.Required.Error("title must not be blanked")),
+		validation.Field(&newSession.StartDate, validation.Required.Error("start date must not be blanked")),
+		validation.Field(&newSession.EndDate, validation.Required.Error("end date must not be blanked"), validation.By(func(interface{}) error {
+			return validateSessionDates(newSession.StartDate, newSession.EndDate)
+		})),
+		validation.Field(&newSession.Capacity, validation.Min(0).Error("capacity must not be negative")),
+		validation.Field(&newSession.Speakers, validation.By(validateSpeakers)),
+	)
+}
+
+func (u *SessionService) ValidateUpdateSession(updateSession model.UpdateSession) error {
+	return validation.ValidateStruct(&updateSession,
+		validation.Field(&updateSession.Title, validation.Required.Error("title must not be blanked")),
+		validation.Field(&updateSession.StartDate, validation.Required.Error("start date must not be blanked")),
+		validation.Field(&updateSession.EndDate, validation.Required.Error("end date must not be blanked"), validation.By(func(interface{}) error {
+			return validateSessionDates(updateSession.StartDate, updateSession.EndDate)
+		})),
+		validation.Field(&updateSession.Capacity, validation.Min(0).Error("capacity must not be negative")),
+		validation.Field(&updateSession.Speakers, validation.By(validateSpeakers)),
+	)
+}
+
+/* validateSessionDates: a session ends after it starts */
+func validateSessionDates(startDate time.Time, endDate time.Time) error {
+	if !endDate.After(startDate) {
+		return errors.New("end date must be after start date")
+	}
+	return nil
+}
+
+/* validateSpeakers: every speaker has a name, and a valid email when one is given */
+func validateSpeakers(value interface{}) error {
+	for _, speaker := range value.([]*model.SpeakerInput) {
+		if strings.TrimSpace(speaker.Name) == "" {
+			return errors.New("speaker name must not be blanked")
+		}
+		if speaker.Email != nil && *speaker.Email != "" {
+			if err := is.Email.Validate(*speaker.Email); err != nil {
+				return fmt.Errorf("invalid speaker email %s", *speaker.Email)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,338 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/khanhvtn/netevent-go/database"
+	"github.com/khanhvtn/netevent-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var StatisticRepositoryName = "StatisticRepositoryName"
+
+/* StatisticRepository: aggregation pipelines reporting over several collections */
+type StatisticRepository struct {
+	MongoCN *database.MongoInstance
+}
+
+/* createContextAndTargetCol: create and return targeted collection based on collection name */
+func (u *StatisticRepository) createContextAndTargetCol(colName string) (col *mongo.Collection,
+	ctx context.Context,
+	cancel context.CancelFunc) {
+	col = u.MongoCN.Db.Collection(colName)
+	ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+	return
+}
+
+/* aggregate: run a pipeline and decode every result into results */
+func (u *StatisticRepository) aggregate(colName string, pipeline bson.A, results interface{}) error {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(colName)
+	defer cancel()
+
+	cur, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	return cur.All(ctx, results)
+}
+
+/* countIf: a $group accumulator counting the documents matching an expression */
+func countIf(expression interface{}) bson.M {
+	return bson.M{"$sum": bson.M{"$cond": bson.A{expression, 1, 0}}}
+}
+
+/* day: the start of the day of a date field, in UTC */
+func day(field string) bson.M {
+	return bson.M{"$dateFromParts": bson.M{
+		"year":  bson.M{"$year": field},
+		"month": bson.M{"$month": field},
+		"day":   bson.M{"$dayOfMonth": field},
+	}}
+}
+
+/* breakdown: a $facet branch counting the participants per value of a field, most common first */
+func breakdown(field string) bson.A {
+	return bson.A{
+		bson.M{"$group": bson.M{"_id": field, "count": bson.M{"$sum": 1}}},
+		bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+	}
+}
+
+/*ParticipantStatistic: counts, registrations per day and breakdowns of the participants of an event*/
+func (u *StatisticRepository) ParticipantStatistic(eventId primitive.ObjectID) (*models.ParticipantStatistic, error) {
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{"event": eventId}},
+		bson.M{"$facet": bson.M{
+			//waitlisted participants hold no seat, they are neither valid nor invalid yet
+			"counts": bson.A{bson.M{"$group": bson.M{
+				"_id":        nil,
+				"total":      bson.M{"$sum": 1},
+				"waitlisted": countIf(bson.M{"$eq": bson.A{"$status", models.ParticipantWaitlisted}}),
+				"valid":      countIf(bson.M{"$and": bson.A{"$isValid", bson.M{"$ne": bson.A{"$status", models.ParticipantWaitlisted}}}}),
+				"attended":   countIf("$isAttended"),
+			}}},
+			"registrations": bson.A{
+				bson.M{"$group": bson.M{"_id": day("$createdAt"), "count": bson.M{"$sum": 1}}},
+				bson.M{"$sort": bson.M{"_id": 1}},
+			},
+			"bySchool":   breakdown("$school"),
+			"byMajor":    breakdown("$major"),
+			"byAcademic": breakdown("$academic"),
+		}},
+		//an event without participants has no counts group
+		bson.M{"$project": bson.M{
+			"counts":        bson.M{"$ifNull": bson.A{bson.M{"$arrayElemAt": bson.A{"$counts", 0}}, bson.M{}}},
+			"registrations": 1,
+			"bySchool":      1,
+			"byMajor":       1,
+			"byAcademic":    1,
+		}},
+	}
+	results := make([]*models.ParticipantStatistic, 0)
+	if err := u.aggregate(models.CollectionParticipantName, pipeline, &results); err != nil {
+		return nil, err
+	}
+	return results[0], nil
+}
+
+/*TaskCounts: the tasks of an event by progress at a time*/
+func (u *StatisticRepository) TaskCounts(eventId primitive.ObjectID, now time.Time) (*models.TaskCounts, error) {
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{"event": eventId}},
+		bson.M{"$group": bson.M{
+			"_id":     nil,
+			"total":   bson.M{"$sum": 1},
+			"elapsed": countIf(bson.M{"$lte": bson.A{"$endDate", now}}),
+			"inProgress": countIf(bson.M{"$and": bson.A{
+				bson.M{"$lte": bson.A{"$startDate", now}},
+				bson.M{"$gt": bson.A{"$endDate", now}},
+			}}),
+		}},
+	}
+	results := make([]*models.TaskCounts, 0)
+	if err := u.aggregate(models.CollectionTaskName, pipeline, &results); err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return &models.TaskCounts{}, nil
+	}
+	return results[0], nil
+}
+
+/*FacilityUsage: the bookings of an event per facility, most booked first*/
+func (u *StatisticRepository) FacilityUsage(eventId primitive.ObjectID) ([]*models.FacilityUsage, error) {
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{"event": eventId}},
+		bson.M{"$group": bson.M{
+			"_id":      "$facility",
+			"bookings": bson.M{"$sum": 1},
+			//bookings made before quantities existed count as one unit
+			"quantity":   bson.M{"$sum": bson.M{"$max": bson.A{bson.M{"$ifNull": bson.A{"$quantity", 1}}, 1}}},
+			"checkedOut": countIf(bson.M{"$gt": bson.A{"$checkedOut", nil}}),
+			"returned":   countIf(bson.M{"$gt": bson.A{"$returned", nil}}),
+		}},
+		bson.M{"$sort": bson.D{{Key: "quantity", Value: -1}, {Key: "_id", Value: 1}}},
+	}
+	results := make([]*models.FacilityUsage, 0)
+	if err := u.aggregate(models.CollectionFacilityHistoryName, pipeline, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+/* month: the start of the month of a date field, in UTC */
+func month(field string) bson.M {
+	return bson.M{"$dateFromParts": bson.M{
+		"year":  bson.M{"$year": field},
+		"month": bson.M{"$month": field},
+	}}
+}
+
+/* periodEvents: the events starting in a period, deleted ones left out */
+func periodEvents(from time.Time, to time.Time) bson.M {
+	return bson.M{"$match": bson.M{"isDeleted": false, "startDate": bson.M{"$gte": from, "$lt": to}}}
+}
+
+/* liveEvent: the stages keeping the records of events that are not deleted */
+func liveEvent() bson.A {
+	return bson.A{
+		bson.M{"$lookup": bson.M{"from": models.CollectionEventName, "localField": "event", "foreignField": "_id", "as": "liveEvent"}},
+		bson.M{"$match": bson.M{"liveEvent.isDeleted": false}},
+	}
+}
+
+/*EventTotals: the number of events of a period*/
+func (u *StatisticRepository) EventTotals(from time.Time, to time.Time) (*models.EventTotals, error) {
+	pipeline := bson.A{
+		periodEvents(from, to),
+		bson.M{"$group": bson.M{
+			"_id":   nil,
+			"count": bson.M{"$sum": 1},
+		}},
+	}
+	results := make([]*models.EventTotals, 0)
+	if err := u.aggregate(models.CollectionEventName, pipeline, &results); err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return &models.EventTotals{}, nil
+	}
+	return results[0], nil
+}
+
+/*BudgetTotals: the budget items of the events of a period summed per currency, with the number of events having some*/
+func (u *StatisticRepository) BudgetTotals(from time.Time, to time.Time) ([]*models.CurrencyBudget, error) {
+	pipeline := bson.A{
+		periodEvents(from, to),
+		bson.M{"$lookup": bson.M{"from": models.CollectionBudgetItemName, "localField": "_id", "foreignField": "event", "as": "budgetItems"}},
+		bson.M{"$unwind": "$budgetItems"},
+		bson.M{"$group": bson.M{
+			"_id":     bson.M{"event": "$_id", "currency": "$budgetItems.currency"},
+			"planned": bson.M{"$sum": "$budgetItems.planned"},
+		}},
+		bson.M{"$group": bson.M{
+			"_id":    "$_id.currency",
+			"total":  bson.M{"$sum": "$planned"},
+			"events": bson.M{"$sum": 1},
+		}},
+		bson.M{"$sort": bson.M{"_id": 1}},
+	}
+	results := make([]*models.CurrencyBudget, 0)
+	if err := u.aggregate(models.CollectionEventName, pipeline, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+/*EventsPerTypeMonth: the events of a period per event type and month of their start*/
+func (u *StatisticRepository) EventsPerTypeMonth(from time.Time, to time.Time) ([]*models.EventTypeMonthCount, error) {
+	pipeline := bson.A{
+		periodEvents(from, to),
+		bson.M{"$group": bson.M{
+			"_id":   bson.M{"eventType": "$eventType", "month": month("$startDate")},
+			"count": bson.M{"$sum": 1},
+		}},
+		bson.M{"$project": bson.M{"_id": 0, "eventType": "$_id.eventType", "month": "$_id.month", "count": 1}},
+		bson.M{"$sort": bson.D{{Key: "month", Value: 1}, {Key: "count", Value: -1}}},
+	}
+	results := make([]*models.EventTypeMonthCount, 0)
+	if err := u.aggregate(models.CollectionEventName, pipeline, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+/*ApprovalTurnaround: the time from creation to first approval of the approved events of a period*/
+func (u *StatisticRepository) ApprovalTurnaround(from time.Time, to time.Time) (*models.ApprovalTurnaround, error) {
+	hours := bson.M{"$divide": bson.A{bson.M{"$subtract": bson.A{"$approvedAt", "$createdAt"}}, float64(time.Hour / time.Millisecond)}}
+	pipeline := bson.A{
+		periodEvents(from, to),
+		//events approved before the approval time was recorded cannot be measured
+		bson.M{"$match": bson.M{"isApproved": true, "approvedAt": bson.M{"$exists": true}}},
+		bson.M{"$group": bson.M{
+			"_id":          nil,
+			"approved":     bson.M{"$sum": 1},
+			"averageHours": bson.M{"$avg": hours},
+			"minHours":     bson.M{"$min": hours},
+			"maxHours":     bson.M{"$max": hours},
+		}},
+	}
+	results := make([]*models.ApprovalTurnaround, 0)
+	if err := u.aggregate(models.CollectionEventName, pipeline, &results); err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return &models.ApprovalTurnaround{}, nil
+	}
+	return results[0], nil
+}
+
+/*AttendanceTrend: registered and attended participants per month of the start of their event*/
+func (u *StatisticRepository) AttendanceTrend(from time.Time, to time.Time) ([]*models.AttendanceMonth, error) {
+	pipeline := bson.A{
+		periodEvents(from, to),
+		bson.M{"$lookup": bson.M{
+			"from":         models.CollectionParticipantName,
+			"localField":   "_id",
+			"foreignField": "event",
+			"as":           "participants",
+		}},
+		bson.M{"$unwind": "$participants"},
+		bson.M{"$match": bson.M{"participants.status": bson.M{"$ne": models.ParticipantWaitlisted}}},
+		bson.M{"$group": bson.M{
+			"_id":        month("$startDate"),
+			"registered": bson.M{"$sum": 1},
+			"attended":   countIf("$participants.isAttended"),
+		}},
+		bson.M{"$sort": bson.M{"_id": 1}},
+	}
+	results := make([]*models.AttendanceMonth, 0)
+	if err := u.aggregate(models.CollectionEventName, pipeline, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+/*BusiestFacilities: the facilities with the most units booked in a period, bookings of deleted events left out*/
+func (u *StatisticRepository) BusiestFacilities(from time.Time, to time.Time, limit int) ([]*models.FacilityUsage, error) {
+	pipeline := append(bson.A{bson.M{"$match": bson.M{"borrowDate": bson.M{"$gte": from, "$lt": to}}}}, liveEvent()...)
+	pipeline = append(pipeline,
+		bson.M{"$group": bson.M{
+			"_id":        "$facility",
+			"bookings":   bson.M{"$sum": 1},
+			"quantity":   bson.M{"$sum": bson.M{"$max": bson.A{bson.M{"$ifNull": bson.A{"$quantity", 1}}, 1}}},
+			"checkedOut": countIf(bson.M{"$gt": bson.A{"$checkedOut", nil}}),
+			"returned":   countIf(bson.M{"$gt": bson.A{"$returned", nil}}),
+		}},
+		bson.M{"$sort": bson.D{{Key: "quantity", Value: -1}, {Key: "bookings", Value: -1}, {Key: "_id", Value: 1}}},
+		bson.M{"$limit": limit},
+	)
+	results := make([]*models.FacilityUsage, 0)
+	if err := u.aggregate(models.CollectionFacilityHistoryName, pipeline, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+/*TopStaff: the users assigned the most tasks starting in a period, tasks of deleted events left out*/
+func (u *StatisticRepository) TopStaff(from time.Time, to time.Time, limit int) ([]*models.StaffAssignment, error) {
+	pipeline := append(bson.A{bson.M{"$match": bson.M{"startDate": bson.M{"$gte": from, "$lt": to}}}}, liveEvent()...)
+	pipeline = append(pipeline,
+		bson.M{"$group": bson.M{"_id": "$user", "tasks": bson.M{"$sum": 1}}},
+		bson.M{"$sort": bson.D{{Key: "tasks", Value: -1}, {Key: "_id", Value: 1}}},
+		bson.M{"$limit": limit},
+	)
+	results := make([]*models.StaffAssignment, 0)
+	if err := u.aggregate(models.CollectionTaskName, pipeline, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+/*CacheVersion: the version of the cached statistics, bumped on every write*/
+func (u *StatisticRepository) CacheVersion() (int64, error) {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionCacheVersionName)
+	defer cancel()
+
+	cacheVersion := models.CacheVersion{}
+	if err := collection.FindOne(ctx, bson.M{"_id": models.CacheStatistics}).Decode(&cacheVersion); err != nil && err != mongo.ErrNoDocuments {
+		return 0, err
+	}
+	return cacheVersion.Version, nil
+}
+
+/*BumpCacheVersion: outdate the cached statistics of every instance*/
+func (u *StatisticRepository) BumpCacheVersion() error {
+	//get a collection , context, cancel func
+	collection, ctx, cancel := u.createContextAndTargetCol(models.CollectionCacheVersionName)
+	defer cancel()
+
+	_, err := collection.UpdateOne(ctx, bson.M{"_id": models.CacheStatistics}, bson.M{"$inc": bson.M{"version": 1}}, options.Update().SetUpsert(true))
+	return err
+}
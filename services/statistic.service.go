@@ -0,0 +1,47 @@
+package services
+
+import (
+	"time"
+
+	"github.com/khanhvtn/netevent-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var StatisticServiceName = "StatisticServiceName"
+
+type StatisticService struct {
+	StatisticRepository *StatisticRepository
+	EventRepository     *EventRepository
+}
+
+/*GetEventStatistic: the participant, task and facility statistics of an event*/
+func (u *StatisticService) GetEventStatistic(eventId primitive.ObjectID) (*models.EventStatistic, error) {
+	event, err := u.EventRepository.FindOne(bson.M{"_id": eventId})
+	if err != nil {
+		return nil, err
+	}
+	participants, err := u.StatisticRepository.ParticipantStatistic(event.ID)
+	if err != nil {
+		return nil, err
+	}
+	cumulative := 0
+	for _, registration := range participants.Registrations {
+		cumulative += registration.Count
+		registration.Cumulative = cumulative
+	}
+	tasks, err := u.StatisticRepository.TaskCounts(event.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	facilities, err := u.StatisticRepository.FacilityUsage(event.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.EventStatistic{
+		Event:        event,
+		Participants: *participants,
+		Tasks:        *tasks,
+		Facilities:   facilities,
+	}, nil
+}
@@ -16,7 +16,8 @@ import (
 var TaskServiceName = "TaskServiceName"
 
 type TaskService struct {
-	TaskRepository *TaskRepository
+	TaskRepository   *TaskRepository
+	ReferenceService *ReferenceService
 }
 
 /* GetAll: get all data based on condition*/
@@ -67,9 +68,16 @@ func (u TaskService) UpdateOne(filter bson.M, update model.UpdateTask) (*models.
 	return u.TaskRepository.UpdateOne(filter, bsonUpdate)
 }
 
-//DeleteOne func is to update one record from a collection
+//DeleteOne func is to delete one record from a collection once its references are resolved
 func (u TaskService) DeleteOne(filter bson.M) (*models.Task, error) {
-	return u.TaskRepository.DeleteOne(filter)
+	task, err := u.TaskRepository.FindOne(filter)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.ReferenceService.Delete(models.CollectionTaskName, task.ID, nil); err != nil {
+		return nil, err
+	}
+	return task, nil
 }
 
 //validation
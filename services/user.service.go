@@ -6,6 +6,7 @@ import (
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/go-ozzo/ozzo-validation/is"
 	"github.com/khanhvtn/netevent-go/graph/model"
+	"github.com/khanhvtn/netevent-go/helpers"
 	"github.com/khanhvtn/netevent-go/models"
 	"github.com/khanhvtn/netevent-go/utilities"
 	"go.mongodb.org/mongo-driver/bson"
@@ -15,7 +16,8 @@ var UserServiceName = "UserServiceName"
 
 // UserService handles the creation, modification and deletion of users.
 type UserService struct {
-	UserRepository *UserRepository
+	UserRepository   *UserRepository
+	ReferenceService *ReferenceService
 }
 
 /* GetAll: get all data based on condition*/
@@ -28,6 +30,29 @@ func (u *UserService) GetOne(filter bson.M) (*models.User, error) {
 	return u.UserRepository.FindOne(filter)
 }
 
+/*GetBySession: get the logged in user from the encrypted session cookie*/
+func (u *UserService) GetBySession(encryptedCookie string) (*models.User, error) {
+	//decrypt cookie
+	id, err := utilities.Decrypted([]byte(encryptedCookie))
+	if err != nil {
+		return nil, errors.New("access denied")
+	}
+	objectId, err := utilities.ConvertStringIdToObjectID(string(id))
+	if err != nil {
+		return nil, err
+	}
+	//get user based specific id
+	return u.GetOne(bson.M{"_id": objectId})
+}
+
+/*RequireRole: refuse a user holding none of the roles*/
+func (u *UserService) RequireRole(user *models.User, roles ...string) error {
+	if !user.HasRole(roles...) {
+		return helpers.NewErrForbidden("access denied")
+	}
+	return nil
+}
+
 /*Create: create a new record to a collection*/
 func (u *UserService) Create(newUser model.NewUser) (*models.User, error) {
 	return u.UserRepository.Create(newUser)
@@ -38,9 +63,16 @@ func (u UserService) UpdateOne(filter bson.M, update bson.M) (*models.User, erro
 	return u.UserRepository.UpdateOne(filter, update)
 }
 
-//DeleteOne func is to update one record from a collection
+//DeleteOne func is to delete one record from a collection once its references are resolved
 func (u UserService) DeleteOne(filter bson.M) (*models.User, error) {
-	return u.UserRepository.DeleteOne(filter)
+	user, err := u.UserRepository.FindOne(filter)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.ReferenceService.Delete(models.CollectionUserName, user.ID, nil); err != nil {
+		return nil, err
+	}
+	return user, nil
 }
 
 func (u UserService) Login(input model.Login) (*models.User, error) {
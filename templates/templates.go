@@ -0,0 +1,8 @@
+package templates
+
+import "embed"
+
+/*Mails: the default mail templates, <name>.<language>.html and <name>.<language>.txt,
+the subject is the "subject" block of the txt template*/
+//go:embed mails
+var Mails embed.FS
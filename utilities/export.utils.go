@@ -0,0 +1,143 @@
+package utilities
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+/* RowWriter: writes the rows of an export one by one */
+type RowWriter interface {
+	WriteRow(row []string) error
+	Close() error
+}
+
+/* CsvRowWriter: rows as RFC 4180 csv, fields with separators, quotes or new lines are quoted */
+type CsvRowWriter struct {
+	writer *csv.Writer
+	rows   int
+}
+
+/* NewCsvRowWriter: write csv rows to w */
+func NewCsvRowWriter(w io.Writer) *CsvRowWriter {
+	return &CsvRowWriter{writer: csv.NewWriter(w)}
+}
+
+/* WriteRow: write one row, flushed every 100 rows so large exports stream */
+func (c *CsvRowWriter) WriteRow(row []string) error {
+	cells := make([]string, 0, len(row))
+	for _, value := range row {
+		cells = append(cells, EscapeFormula(value))
+	}
+	if err := c.writer.Write(cells); err != nil {
+		return err
+	}
+	c.rows++
+	if c.rows%100 == 0 {
+		c.writer.Flush()
+	}
+	return c.writer.Error()
+}
+
+/* Close: flush the remaining rows */
+func (c *CsvRowWriter) Close() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+//the parts of a workbook with a single sheet of inline strings
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+}
+
+/* XlsxRowWriter: rows as the only sheet of an xlsx workbook, written while the rows come */
+type XlsxRowWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+}
+
+/* NewXlsxRowWriter: start a workbook with a sheet named sheetName on w */
+func NewXlsxRowWriter(w io.Writer, sheetName string) (*XlsxRowWriter, error) {
+	archive := zip.NewWriter(w)
+	for _, part := range xlsxParts {
+		f, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+	f, err := archive.Create("xl/workbook.xml")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(f, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="`+xmlEscape(sheetName)+`" sheetId="1" r:id="rId1"/></sheets></workbook>`); err != nil {
+		return nil, err
+	}
+	//the sheet is the last part so it can be streamed
+	f, err = archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(f)
+	if _, err := sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return nil, err
+	}
+	return &XlsxRowWriter{archive: archive, sheet: sheet}, nil
+}
+
+/* WriteRow: write one row of text cells */
+func (x *XlsxRowWriter) WriteRow(row []string) error {
+	x.sheet.WriteString("<row>")
+	for _, value := range row {
+		x.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+		//inline strings are never run as formulas, they are written as they are
+		x.sheet.WriteString(xmlEscape(value))
+		x.sheet.WriteString("</t></is></c>")
+	}
+	_, err := x.sheet.WriteString("</row>")
+	return err
+}
+
+/* Close: end the sheet and the workbook */
+func (x *XlsxRowWriter) Close() error {
+	if _, err := x.sheet.WriteString("</sheetData></worksheet>"); err != nil {
+		return err
+	}
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.archive.Close()
+}
+
+/*
+EscapeFormula: keep a spreadsheet opening a csv file from running a cell as a formula.
+Cells starting like a formula are prefixed with a quote so they are shown as text
+*/
+func EscapeFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+/* xmlEscape: escape a text for xml, characters xml cannot hold are replaced */
+func xmlEscape(value string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
@@ -0,0 +1,95 @@
+package utilities
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/khanhvtn/netevent-go/helpers"
+)
+
+//ImportDateLayouts: the date formats accepted in an import file
+var ImportDateLayouts = []string{"2006-01-02", time.RFC3339, "02/01/2006"}
+
+/* ImportRow: a row of an import file and the line it starts on */
+type ImportRow struct {
+	Line   int
+	Record []string
+}
+
+/*
+ReadImportFile: read the header and the rows of a csv file, the separator is guessed from the header so spreadsheets saved with ";" work too.
+Blank rows are skipped, files larger than maxBytes or with more than maxRows rows are refused
+*/
+func ReadImportFile(file io.Reader, maxBytes int64, maxRows int) ([]string, []ImportRow, error) {
+	content, err := io.ReadAll(io.LimitReader(file, maxBytes+1))
+	if err != nil {
+		return nil, nil, err
+	}
+	if int64(len(content)) > maxBytes {
+		return nil, nil, helpers.NewErrValidation(fmt.Sprintf("a file can be at most %d bytes", maxBytes))
+	}
+	firstLine := string(content)
+	if i := strings.IndexByte(firstLine, '\n'); i != -1 {
+		firstLine = firstLine[:i]
+	}
+	reader := csv.NewReader(strings.NewReader(string(content)))
+	if strings.Count(firstLine, ";") > strings.Count(firstLine, ",") {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, helpers.NewErrValidation("file is empty")
+	}
+	if err != nil {
+		return nil, nil, helpers.NewErrValidation(err.Error())
+	}
+	//the whole file is read before anything is registered, so a file over the limit registers nobody
+	rows := make([]ImportRow, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, helpers.NewErrValidation(err.Error())
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		if len(rows) == maxRows {
+			return nil, nil, helpers.NewErrValidation(fmt.Sprintf("a file can hold at most %d participants", maxRows))
+		}
+		//quoted cells can span several lines, the row is reported at the line it starts on
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, ImportRow{Line: line, Record: record})
+	}
+	return header, rows, nil
+}
+
+/* ParseImportDate: read a date in one of the accepted formats */
+func ParseImportDate(value string) (time.Time, error) {
+	var err error
+	for _, layout := range ImportDateLayouts {
+		var date time.Time
+		if date, err = time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+/* isBlankRecord: rows left empty by spreadsheets are skipped */
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+package utilities
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadImportFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		maxBytes   int64
+		maxRows    int
+		wantHeader []string
+		wantRows   []ImportRow
+		wantErr    string
+	}{
+		{
+			name:       "comma separated",
+			content:    "email,name\nlan@example.com,Lan\nan@example.com,An\n",
+			wantHeader: []string{"email", "name"},
+			wantRows:   []ImportRow{{Line: 2, Record: []string{"lan@example.com", "Lan"}}, {Line: 3, Record: []string{"an@example.com", "An"}}},
+		},
+		{
+			name:       "semicolon separated",
+			content:    "email;name;note\nlan@example.com;Lan;a, b\n",
+			wantHeader: []string{"email", "name", "note"},
+			wantRows:   []ImportRow{{Line: 2, Record: []string{"lan@example.com", "Lan", "a, b"}}},
+		},
+		{
+			name:       "crlf and leading spaces",
+			content:    "email, name\r\nlan@example.com, Lan\r\n",
+			wantHeader: []string{"email", "name"},
+			wantRows:   []ImportRow{{Line: 2, Record: []string{"lan@example.com", "Lan"}}},
+		},
+		{
+			name:       "blank rows are skipped",
+			content:    "email,name\n,\n\nlan@example.com,Lan\n ; \n",
+			wantHeader: []string{"email", "name"},
+			wantRows:   []ImportRow{{Line: 4, Record: []string{"lan@example.com", "Lan"}}, {Line: 5, Record: []string{"; "}}},
+		},
+		{
+			name:       "rows are reported at the line they start on",
+			content:    "email,note\nlan@example.com,\"first\nsecond\"\nan@example.com,x\n",
+			wantHeader: []string{"email", "note"},
+			wantRows:   []ImportRow{{Line: 2, Record: []string{"lan@example.com", "first\nsecond"}}, {Line: 4, Record: []string{"an@example.com", "x"}}},
+		},
+		{
+			name:       "rows can have fewer cells",
+			content:    "email,name\nlan@example.com\n",
+			wantHeader: []string{"email", "name"},
+			wantRows:   []ImportRow{{Line: 2, Record: []string{"lan@example.com"}}},
+		},
+		{
+			name:       "header only",
+			content:    "email,name\n",
+			wantHeader: []string{"email", "name"},
+			wantRows:   []ImportRow{},
+		},
+		{name: "empty file", content: "", wantErr: "file is empty"},
+		{name: "too large", content: "email,name\nlan@example.com,Lan\n", maxBytes: 10, wantErr: "a file can be at most 10 bytes"},
+		{name: "too many rows", content: "email\na@example.com\nb@example.com\n", maxRows: 1, wantErr: "a file can hold at most 1 participants"},
+		{name: "blank rows do not count towards the limit", content: "email\na@example.com\n\n,\n", maxRows: 1, wantHeader: []string{"email"}, wantRows: []ImportRow{{Line: 2, Record: []string{"a@example.com"}}}},
+		{name: "unterminated quote", content: "email,name\n\"lan@example.com,Lan\n", wantErr: "extraneous or missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maxBytes, maxRows := tt.maxBytes, tt.maxRows
+			if maxBytes == 0 {
+				maxBytes = 1 << 20
+			}
+			if maxRows == 0 {
+				maxRows = 100
+			}
+			header, rows, err := ReadImportFile(strings.NewReader(tt.content), maxBytes, maxRows)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ReadImportFile() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadImportFile(): %v", err)
+			}
+			if !reflect.DeepEqual(header, tt.wantHeader) {
+				t.Errorf("header = %q, want %q", header, tt.wantHeader)
+			}
+			if !reflect.DeepEqual(rows, tt.wantRows) {
+				t.Errorf("rows = %v, want %v", rows, tt.wantRows)
+			}
+		})
+	}
+}
+
+func TestParseImportDate(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "2001-09-15", want: time.Date(2001, time.September, 15, 0, 0, 0, 0, time.UTC)},
+		{value: "2001-09-15T08:30:00+07:00", want: time.Date(2001, time.September, 15, 1, 30, 0, 0, time.UTC)},
+		{value: "15/09/2001", want: time.Date(2001, time.September, 15, 0, 0, 0, 0, time.UTC)},
+		{value: "09/15/2001", wantErr: true},
+		{value: "yesterday", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseImportDate(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseImportDate(%q) = %s, want an error", tt.value, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseImportDate(%q): %v", tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseImportDate(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package utilities
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+/* QrPayload: what the QR code of a participant identifies */
+type QrPayload struct {
+	EventID       string `json:"eventId"`
+	ParticipantID string `json:"participantId"`
+}
+
+/* EncodeQrPayload: encrypt event id and participant id into text that fits in a QR code */
+func EncodeQrPayload(eventId, participantId string) (string, error) {
+	valueQrCodeJson, err := json.Marshal(QrPayload{
+		EventID:       eventId,
+		ParticipantID: participantId,
+	})
+	if err != nil {
+		return "", err
+	}
+	encryptedValueQrCodeJson, err := Encrypt(valueQrCodeJson)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(encryptedValueQrCodeJson), nil
+}
+
+/* DecodeQrPayload: decrypt and verify a scanned QR code */
+func DecodeQrPayload(payload string) (*QrPayload, error) {
+	encrypted, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		//codes sent before the payload was encoded hold the raw cipher text
+		encrypted = []byte(payload)
+	}
+	//decryption fails when the payload was not issued by this server or was altered
+	valueQrCodeJson, err := Decrypted(encrypted)
+	if err != nil {
+		return nil, errors.New("invalid QR code")
+	}
+	qrPayload := &QrPayload{}
+	if err := json.Unmarshal(valueQrCodeJson, qrPayload); err != nil {
+		return nil, errors.New("invalid QR code")
+	}
+	return qrPayload, nil
+}
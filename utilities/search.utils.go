@@ -0,0 +1,226 @@
+package utilities
+
+import (
+	"html"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+//searchAccents: the accented forms a letter of a search term also matches in a regular expression
+var searchAccents = map[rune]string{
+	'a': "aàáảãạăằắẳẵặâầấẩẫậäå",
+	'e': "eèéẻẽẹêềếểễệë",
+	'i': "iìíỉĩịîï",
+	'o': "oòóỏõọôồốổỗộơờớởỡợö",
+	'u': "uùúủũụưừứửữựûü",
+	'y': "yỳýỷỹỵ",
+	'd': "dđ",
+	'c': "cç",
+	'n': "nñ",
+}
+
+/* TextToken: a word of a text, its position in runes and its folded form */
+type TextToken struct {
+	Start  int
+	End    int
+	Folded string
+}
+
+/* FoldText: lower case without accents, so Nguyễn and nguyen compare equal */
+func FoldText(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(s)) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		//đ has no decomposed form
+		if r == 'đ' {
+			r = 'd'
+		}
+		b.WriteRune(r)
+	}
+	return norm.NFC.String(b.String())
+}
+
+/* Tokenize: the words of a text, a word is a run of letters and digits */
+func Tokenize(s string) []TextToken {
+	tokens := make([]TextToken, 0)
+	runes := []rune(s)
+	start := -1
+	for i := 0; i <= len(runes); i++ {
+		isWord := i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || unicode.Is(unicode.Mn, runes[i]))
+		if isWord && start < 0 {
+			start = i
+		}
+		if !isWord && start >= 0 {
+			tokens = append(tokens, TextToken{Start: start, End: i, Folded: FoldText(string(runes[start:i]))})
+			start = -1
+		}
+	}
+	return tokens
+}
+
+/* SearchTerms: the distinct folded words of a search query */
+func SearchTerms(query string) []string {
+	terms := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, token := range Tokenize(query) {
+		if !seen[token.Folded] {
+			seen[token.Folded] = true
+			terms = append(terms, token.Folded)
+		}
+	}
+	return terms
+}
+
+/* AllowedTypos: the edits tolerated in a term, none in short terms which would match almost anything */
+func AllowedTypos(term string) int {
+	switch length := len([]rune(term)); {
+	case length < 4:
+		return 0
+	case length < 8:
+		return 1
+	}
+	return 2
+}
+
+/* EditDistance: insertions, deletions, substitutions and swaps of neighbour letters turning a into b */
+func EditDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	rows := make([][]int, len(ra)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(rb)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			rows[i][j] = minInt(rows[i-1][j]+1, minInt(rows[i][j-1]+1, rows[i-1][j-1]+cost))
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				rows[i][j] = minInt(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(ra)][len(rb)]
+}
+
+/* PrefixDistance: the edits turning a term into the start of a word, so partly typed words match */
+func PrefixDistance(term string, word string) int {
+	rw := []rune(word)
+	length := len([]rune(term))
+	best := EditDistance(term, word)
+	for l := length - 2; l <= length+2; l++ {
+		if l > 0 && l < len(rw) {
+			if d := EditDistance(term, string(rw[:l])); d < best {
+				best = d
+			}
+		}
+	}
+	return best
+}
+
+/*
+FuzzyPattern: a regular expression matching the start of any word within one typo of a folded term,
+whatever the accents of the word. Terms too short for typos must match exactly
+*/
+func FuzzyPattern(term string) string {
+	runes := []rune(term)
+	letters := func(rs []rune) string {
+		var b strings.Builder
+		for _, r := range rs {
+			if accents, ok := searchAccents[r]; ok {
+				b.WriteString("[" + accents + strings.ToUpper(accents) + "]")
+			} else {
+				b.WriteString(regexpQuote(r))
+			}
+		}
+		return b.String()
+	}
+	alternatives := []string{letters(runes)}
+	if AllowedTypos(term) > 0 {
+		for i := range runes {
+			//a different or missing letter
+			alternatives = append(alternatives, letters(runes[:i])+`\p{L}?`+letters(runes[i+1:]))
+			//an extra letter
+			alternatives = append(alternatives, letters(runes[:i])+`\p{L}`+letters(runes[i:]))
+			//two letters swapped
+			if i+1 < len(runes) {
+				swapped := append(append(append([]rune{}, runes[:i]...), runes[i+1], runes[i]), runes[i+2:]...)
+				alternatives = append(alternatives, letters(swapped))
+			}
+		}
+	}
+	return `(?:^|[^\p{L}\p{N}])(?:` + strings.Join(alternatives, "|") + `)`
+}
+
+/* regexpQuote: a rune escaped for a regular expression */
+func regexpQuote(r rune) string {
+	if strings.ContainsRune(`\.+*?()|[]{}^$`, r) {
+		return `\` + string(r)
+	}
+	return string(r)
+}
+
+/*
+Highlight: an html escaped extract of about length runes around the first span, every span is wrapped in <mark>.
+Spans are [start, end) rune positions in the text
+*/
+func Highlight(text string, spans [][2]int, length int) string {
+	runes := []rune(text)
+	start, end := 0, len(runes)
+	if len(runes) > length {
+		//the first match sits a third into the extract
+		if len(spans) > 0 {
+			start = spans[0][0] - length/3
+		}
+		if start < 0 {
+			start = 0
+		}
+		end = start + length
+		if end > len(runes) {
+			end = len(runes)
+			start = maxInt(0, end-length)
+		}
+	}
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	position := start
+	for _, span := range spans {
+		if span[1] <= position || span[0] >= end {
+			continue
+		}
+		from, to := maxInt(span[0], position), minInt(span[1], end)
+		b.WriteString(html.EscapeString(string(runes[position:from])))
+		b.WriteString("<mark>" + html.EscapeString(string(runes[from:to])) + "</mark>")
+		position = to
+	}
+	b.WriteString(html.EscapeString(string(runes[position:end])))
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,143 @@
+package utilities
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestFoldText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "Nguyễn", want: "nguyen"},
+		{text: "Đà Nẵng", want: "da nang"},
+		{text: "ÉCOLE Française", want: "ecole francaise"},
+		{text: "go 2026", want: "go 2026"},
+	}
+	for _, tt := range tests {
+		if got := FoldText(tt.text); got != tt.want {
+			t.Errorf("FoldText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "Hội thảo Go", want: []string{"hoi", "thao", "go"}},
+		{query: "hội-thảo, Hoi THAO!", want: []string{"hoi", "thao"}},
+		{query: "  ,;  ", want: []string{}},
+		{query: "event2026 2026", want: []string{"event2026", "2026"}},
+	}
+	for _, tt := range tests {
+		if got := SearchTerms(tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SearchTerms(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestAllowedTypos(t *testing.T) {
+	tests := []struct {
+		term string
+		want int
+	}{
+		{term: "go", want: 0},
+		{term: "hội", want: 0},
+		{term: "work", want: 1},
+		{term: "seminar", want: 1},
+		{term: "workshop", want: 2},
+	}
+	for _, tt := range tests {
+		if got := AllowedTypos(tt.term); got != tt.want {
+			t.Errorf("AllowedTypos(%q) = %d, want %d", tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "event", b: "event", want: 0},
+		{a: "event", b: "", want: 5},
+		{a: "", b: "go", want: 2},
+		{a: "kitten", b: "sitting", want: 3},
+		{a: "workshop", b: "worshop", want: 1},
+		{a: "event", b: "evnet", want: 1},
+		{a: "ab", b: "ba", want: 1},
+		{a: "nguyen", b: "ngyuen", want: 1},
+		{a: "hội", b: "hoi", want: 1},
+		{a: "ca", b: "abc", want: 3},
+	}
+	for _, tt := range tests {
+		if got := EditDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("EditDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := EditDistance(tt.b, tt.a); got != tt.want {
+			t.Errorf("EditDistance(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestPrefixDistance(t *testing.T) {
+	tests := []struct {
+		term string
+		word string
+		want int
+	}{
+		{term: "work", word: "workshop", want: 0},
+		{term: "wrok", word: "workshop", want: 1},
+		{term: "event", word: "events", want: 0},
+		{term: "workshop", word: "work", want: 4},
+		{term: "xyz", word: "workshop", want: 3},
+	}
+	for _, tt := range tests {
+		if got := PrefixDistance(tt.term, tt.word); got != tt.want {
+			t.Errorf("PrefixDistance(%q, %q) = %d, want %d", tt.term, tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestFuzzyPattern(t *testing.T) {
+	tests := []struct {
+		name  string
+		term  string
+		text  string
+		match bool
+	}{
+		{name: "exact", term: "workshop", text: "Go workshop", match: true},
+		{name: "accents and case", term: "nguyen", text: "Trần Nguyễn", match: true},
+		{name: "accented short term", term: "hoi", text: "Hội thảo", match: true},
+		{name: "prefix", term: "work", text: "Workshops", match: true},
+		{name: "missing letter", term: "worshop", text: "workshop", match: true},
+		{name: "extra letter", term: "workkshop", text: "workshop", match: true},
+		{name: "different letter", term: "evant", text: "the event", match: true},
+		{name: "swapped letters", term: "event", text: "the evnet", match: true},
+		{name: "two typos", term: "evant", text: "the evnxt", match: false},
+		{name: "short terms match exactly", term: "cat", text: "cut", match: false},
+		{name: "short term prefix", term: "go", text: "Golang", match: true},
+		{name: "only at the start of a word", term: "go", text: "ago", match: false},
+		{name: "after punctuation", term: "go", text: "(go)", match: true},
+		{name: "special characters are quoted", term: "c++", text: "c++ meetup", match: true},
+		{name: "special characters do not match anything", term: "c++", text: "cc meetup", match: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			//the repository matches case insensitively
+			pattern, err := regexp.Compile("(?i)" + FuzzyPattern(tt.term))
+			if err != nil {
+				t.Fatalf("FuzzyPattern(%q) does not compile: %v", tt.term, err)
+			}
+			if got := pattern.MatchString(tt.text); got != tt.match {
+				t.Errorf("FuzzyPattern(%q) matching %q = %v, want %v", tt.term, tt.text, got, tt.match)
+			}
+		})
+	}
+}
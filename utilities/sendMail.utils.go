@@ -1,60 +1,27 @@
 package utilities
 
 import (
-	"encoding/json"
-	"fmt"
-	"html/template"
-	"os"
-
 	"github.com/khanhvtn/netevent-go/models"
 	"github.com/skip2/go-qrcode"
 )
 
-/* Send email from project email to others */
-func SendMail(eventName, participantId, eventId string, listReceiver []*models.Participant) error {
-	dir, err := os.Getwd()
+/* SendQrCodeMail: send a composed mail to a participant with their own QR code shown inline as cid:qrCode */
+func SendQrCodeMail(mailer models.Mailer, m *models.Mail, receiver *models.Participant) error {
+	qrCode, err := generateQrCode(receiver.Event.Hex(), receiver.ID.Hex())
 	if err != nil {
 		return err
 	}
-	sender := models.NewSender()
-	//encrypt event id and participant id
-	valueQrCode := struct {
-		EventID       string `json:"eventId"`
-		ParticipantID string `json:"participantId"`
-	}{
-		EventID:       eventId,
-		ParticipantID: participantId,
-	}
-	valueQrCodeJson, err := json.Marshal(valueQrCode)
+	m.To = append(m.To, receiver.Email)
+	m.AttachInline("qrCode", "qrCode.png", qrCode)
+	return mailer.Send(m)
+}
+
+/* generateQrCode: put the check in payload of a participant into a QR code image */
+func generateQrCode(eventId, participantId string) ([]byte, error) {
+	payload, err := EncodeQrPayload(eventId, participantId)
 	if err != nil {
-		return err
-	}
-	encryptedValueQrCodeJson, err := Encrypt(valueQrCodeJson)
-	if err != nil {
-		return err
+		return nil, err
 	}
 	//generate qrcode
-	qrCode, err := qrcode.Encode(string(encryptedValueQrCodeJson), qrcode.Medium, 256)
-	if err != nil {
-		return err
-	}
-	//send email to all receivers
-	for _, v := range listReceiver {
-		m := models.NewMail()
-		m.To = append(m.To, v.Email)
-		m.Subject = fmt.Sprintf("Invitation for %s event", eventName)
-		t, err := template.ParseFiles(dir + "/templates/mail.template.html")
-		if err != nil {
-			return err
-		}
-		m.MailTemplate = &models.MailTemplate{Template: t, Data: struct{ Name string }{
-			Name: v.Name,
-		}}
-
-		m.AttachByteFile("qrCode.png", qrCode)
-		if err := sender.Send(m); err != nil {
-			return err
-		}
-	}
-	return nil
+	return qrcode.Encode(payload, qrcode.Medium, 256)
 }